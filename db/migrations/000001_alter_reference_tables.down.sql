@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS seats_cinema_id_seat_code_key;
+
+ALTER TABLE seats DROP COLUMN IF EXISTS is_active;
+ALTER TABLE times DROP COLUMN IF EXISTS is_active;
+ALTER TABLE locations DROP COLUMN IF EXISTS is_active;
+
+ALTER TABLE cinemas
+    DROP COLUMN IF EXISTS is_active,
+    DROP COLUMN IF EXISTS price_tier,
+    DROP COLUMN IF EXISTS logo_url,
+    DROP COLUMN IF EXISTS address;
@@ -0,0 +1,17 @@
+ALTER TABLE cinemas
+    ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS logo_url TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS price_tier VARCHAR(20) NOT NULL DEFAULT 'regular'
+        CHECK (price_tier IN ('regular', 'premium', 'vip')),
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE locations
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE times
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE seats
+    ADD COLUMN IF NOT EXISTS is_active BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS seats_cinema_id_seat_code_key ON seats (cinema_id, seat_code);
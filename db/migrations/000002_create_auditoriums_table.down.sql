@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS schedules_auditorium_id_idx;
+DROP INDEX IF EXISTS seats_auditorium_id_seat_code_key;
+
+ALTER TABLE schedules DROP COLUMN IF EXISTS auditorium_id;
+ALTER TABLE seats DROP COLUMN IF EXISTS auditorium_id;
+
+-- hanya valid jika setiap cinema masih punya satu studio
+CREATE UNIQUE INDEX IF NOT EXISTS seats_cinema_id_seat_code_key ON seats (cinema_id, seat_code);
+
+DROP TABLE IF EXISTS auditoriums;
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS auditoriums (
+    id         SERIAL PRIMARY KEY,
+    cinema_id  INT NOT NULL REFERENCES cinemas (id),
+    name       VARCHAR(100) NOT NULL,
+    format     VARCHAR(10) NOT NULL DEFAULT '2D'
+        CHECK (format IN ('2D', '3D', 'IMAX', '4DX')),
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE,
+    UNIQUE (cinema_id, name)
+);
+
+-- satu studio default per cinema untuk data lama
+INSERT INTO auditoriums (cinema_id, name)
+SELECT id, 'Studio 1' FROM cinemas
+ON CONFLICT (cinema_id, name) DO NOTHING;
+
+ALTER TABLE seats ADD COLUMN IF NOT EXISTS auditorium_id INT REFERENCES auditoriums (id);
+UPDATE seats s
+SET auditorium_id = a.id
+FROM auditoriums a
+WHERE a.cinema_id = s.cinema_id AND a.name = 'Studio 1' AND s.auditorium_id IS NULL;
+ALTER TABLE seats ALTER COLUMN auditorium_id SET NOT NULL;
+
+ALTER TABLE schedules ADD COLUMN IF NOT EXISTS auditorium_id INT REFERENCES auditoriums (id);
+UPDATE schedules s
+SET auditorium_id = a.id
+FROM auditoriums a
+WHERE a.cinema_id = s.cinema_id AND a.name = 'Studio 1' AND s.auditorium_id IS NULL;
+ALTER TABLE schedules ALTER COLUMN auditorium_id SET NOT NULL;
+
+DROP INDEX IF EXISTS seats_cinema_id_seat_code_key;
+CREATE UNIQUE INDEX IF NOT EXISTS seats_auditorium_id_seat_code_key ON seats (auditorium_id, seat_code);
+CREATE INDEX IF NOT EXISTS schedules_auditorium_id_idx ON schedules (auditorium_id);
@@ -0,0 +1,3 @@
+ALTER TABLE movies
+    DROP COLUMN IF EXISTS tmdb_backdrop_path,
+    DROP COLUMN IF EXISTS tmdb_poster_path;
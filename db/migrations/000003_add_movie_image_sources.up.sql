@@ -0,0 +1,13 @@
+-- path asli TMDB dari poster/backdrop yang di-mirror ke storage sendiri,
+-- dipakai sync untuk tahu apakah gambar perlu diunduh ulang
+ALTER TABLE movies
+    ADD COLUMN IF NOT EXISTS tmdb_poster_path TEXT,
+    ADD COLUMN IF NOT EXISTS tmdb_backdrop_path TEXT;
+
+UPDATE movies
+SET tmdb_poster_path = poster_path
+WHERE tmdb_id IS NOT NULL AND poster_path LIKE '/%' AND poster_path NOT LIKE '/uploads/%';
+
+UPDATE movies
+SET tmdb_backdrop_path = backdrop_path
+WHERE tmdb_id IS NOT NULL AND backdrop_path LIKE '/%' AND backdrop_path NOT LIKE '/uploads/%';
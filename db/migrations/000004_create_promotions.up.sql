@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS promotions (
+    id                SERIAL PRIMARY KEY,
+    code              VARCHAR(50) NOT NULL UNIQUE, -- selalu huruf besar
+    description       TEXT NOT NULL DEFAULT '',
+    discount_type     VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value    INT NOT NULL CHECK (discount_value > 0),
+    max_discount      INT,                          -- batas potongan untuk tipe percent
+    valid_from        TIMESTAMPTZ,
+    valid_until       TIMESTAMPTZ,
+    max_uses          INT,
+    max_uses_per_user INT,
+    min_seats         INT NOT NULL DEFAULT 1,
+    -- kosong = berlaku untuk semua
+    movie_ids         INT[] NOT NULL DEFAULT '{}',
+    cinema_ids        INT[] NOT NULL DEFAULT '{}',
+    days_of_week      INT[] NOT NULL DEFAULT '{}', -- 0 = Minggu, dari tanggal tayang
+    used_count        INT NOT NULL DEFAULT 0,
+    is_active         BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id           SERIAL PRIMARY KEY,
+    promotion_id INT NOT NULL REFERENCES promotions (id),
+    order_id     INT NOT NULL UNIQUE REFERENCES orders (id),
+    user_id      INT NOT NULL REFERENCES users (id),
+    discount     INT NOT NULL,
+    redeemed_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS promotion_redemptions_promotion_user_idx ON promotion_redemptions (promotion_id, user_id);
+
+-- total_price = subtotal - discount
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS subtotal INT,
+    ADD COLUMN IF NOT EXISTS discount INT NOT NULL DEFAULT 0;
+UPDATE orders SET subtotal = total_price WHERE subtotal IS NULL;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS payment_events;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS points_discount,
+    DROP COLUMN IF EXISTS points_redeemed;
+
+DROP TABLE IF EXISTS point_ledger;
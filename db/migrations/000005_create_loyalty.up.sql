@@ -0,0 +1,26 @@
+-- ledger poin: tidak pernah di-update, saldo = SUM(points)
+CREATE TABLE IF NOT EXISTS point_ledger (
+    id          SERIAL PRIMARY KEY,
+    user_id     INT NOT NULL REFERENCES users (id),
+    order_id    INT REFERENCES orders (id),
+    kind        VARCHAR(10) NOT NULL CHECK (kind IN ('earn', 'redeem', 'reverse', 'refund')),
+    points      INT NOT NULL, -- positif = masuk, negatif = keluar
+    description TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- satu entri per jenis per order, sehingga poin tidak pernah diberikan / dibalik dua kali
+CREATE UNIQUE INDEX IF NOT EXISTS point_ledger_order_kind_key ON point_ledger (order_id, kind) WHERE order_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS point_ledger_user_idx ON point_ledger (user_id, created_at DESC);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS points_redeemed INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS points_discount INT NOT NULL DEFAULT 0;
+
+-- event dari payment gateway, event_id yang sama hanya diproses sekali
+CREATE TABLE IF NOT EXISTS payment_events (
+    event_id    VARCHAR(100) PRIMARY KEY,
+    order_id    INT NOT NULL REFERENCES orders (id),
+    status      VARCHAR(20) NOT NULL,
+    payload     JSONB NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS gift_cards_total;
+
+DROP TABLE IF EXISTS order_payments;
+DROP TABLE IF EXISTS gift_card_ledger;
+DROP TABLE IF EXISTS gift_cards;
@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS gift_cards (
+    id                SERIAL PRIMARY KEY,
+    code              VARCHAR(19) NOT NULL UNIQUE, -- XXXX-XXXX-XXXX-XXXX
+    initial_amount    INT NOT NULL CHECK (initial_amount > 0),
+    status            VARCHAR(10) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'voided')),
+    source            VARCHAR(10) NOT NULL CHECK (source IN ('admin', 'purchase')),
+    purchase_order_id INT REFERENCES orders (id),
+    issued_by         INT REFERENCES users (id), -- admin atau pembeli
+    recipient_email   VARCHAR(255) NOT NULL DEFAULT '',
+    message           TEXT NOT NULL DEFAULT '',
+    expires_at        TIMESTAMPTZ,
+    void_reason       TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    voided_at         TIMESTAMPTZ
+);
+
+-- ledger saldo gift card: tidak pernah di-update, saldo = SUM(amount)
+CREATE TABLE IF NOT EXISTS gift_card_ledger (
+    id           SERIAL PRIMARY KEY,
+    gift_card_id INT NOT NULL REFERENCES gift_cards (id),
+    order_id     INT REFERENCES orders (id),
+    kind         VARCHAR(10) NOT NULL CHECK (kind IN ('issue', 'redeem', 'refund', 'void')),
+    amount       INT NOT NULL, -- positif = menambah saldo
+    created_by   INT REFERENCES users (id),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS gift_card_ledger_order_kind_key
+    ON gift_card_ledger (gift_card_id, order_id, kind) WHERE order_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS gift_card_ledger_card_idx ON gift_card_ledger (gift_card_id, created_at);
+
+-- rincian pembayaran satu order: gift card dan metode lain untuk sisanya
+CREATE TABLE IF NOT EXISTS order_payments (
+    id           SERIAL PRIMARY KEY,
+    order_id     INT NOT NULL REFERENCES orders (id),
+    method       VARCHAR(30) NOT NULL,
+    gift_card_id INT REFERENCES gift_cards (id),
+    amount       INT NOT NULL CHECK (amount > 0),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS order_payments_order_idx ON order_payments (order_id);
+
+-- gift card yang ikut dibeli, termasuk di total_price tetapi tidak mendapat poin
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS gift_cards_total INT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS items_total;
+
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS cinema_concessions;
+DROP TABLE IF EXISTS concession_items;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS concession_items (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    category    VARCHAR(10) NOT NULL DEFAULT 'food' CHECK (category IN ('food', 'drink', 'combo')),
+    image_url   TEXT NOT NULL DEFAULT '',
+    is_active   BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- harga dan stok per cinema, stock NULL = tidak dibatasi
+CREATE TABLE IF NOT EXISTS cinema_concessions (
+    cinema_id    INT NOT NULL REFERENCES cinemas (id),
+    item_id      INT NOT NULL REFERENCES concession_items (id),
+    price        INT NOT NULL CHECK (price >= 0),
+    stock        INT CHECK (stock >= 0),
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    PRIMARY KEY (cinema_id, item_id)
+);
+
+-- nama dan harga disalin supaya riwayat order tidak berubah saat katalog diubah
+CREATE TABLE IF NOT EXISTS order_items (
+    id           SERIAL PRIMARY KEY,
+    order_id     INT NOT NULL REFERENCES orders (id),
+    item_id      INT NOT NULL REFERENCES concession_items (id),
+    name         VARCHAR(100) NOT NULL,
+    unit_price   INT NOT NULL,
+    quantity     INT NOT NULL CHECK (quantity > 0),
+    status       VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'fulfilled', 'cancelled')),
+    fulfilled_at TIMESTAMPTZ,
+    fulfilled_by INT REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS order_items_order_idx ON order_items (order_id);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS items_total INT NOT NULL DEFAULT 0;
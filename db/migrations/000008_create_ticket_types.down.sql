@@ -0,0 +1,6 @@
+ALTER TABLE order_seats
+    DROP COLUMN IF EXISTS price,
+    DROP COLUMN IF EXISTS ticket_type;
+
+DROP TABLE IF EXISTS ticket_prices;
+DROP TABLE IF EXISTS ticket_types;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS ticket_types (
+    code       VARCHAR(20) PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    is_active  BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+INSERT INTO ticket_types (code, name, sort_order) VALUES
+    ('adult', 'Adult', 1),
+    ('child', 'Child', 2),
+    ('student', 'Student', 3),
+    ('senior', 'Senior', 4)
+ON CONFLICT (code) DO NOTHING;
+
+-- harga per tipe tiket: schedule_id lebih spesifik dari cinema_id, keduanya NULL = semua jadwal.
+-- Isi salah satu: price (harga tetap) atau percent (persen dari schedules.price).
+-- Tipe tanpa aturan memakai schedules.price.
+CREATE TABLE IF NOT EXISTS ticket_prices (
+    id          SERIAL PRIMARY KEY,
+    ticket_type VARCHAR(20) NOT NULL REFERENCES ticket_types (code),
+    cinema_id   INT REFERENCES cinemas (id),
+    schedule_id INT REFERENCES schedules (id),
+    price       INT CHECK (price >= 0),
+    percent     INT CHECK (percent > 0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (num_nonnulls(price, percent) = 1),
+    CHECK (cinema_id IS NULL OR schedule_id IS NULL)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS ticket_prices_scope_idx
+    ON ticket_prices (ticket_type, COALESCE(cinema_id, 0), COALESCE(schedule_id, 0));
+
+-- harga disimpan per kursi karena tiap kursi bisa berbeda tipe
+ALTER TABLE order_seats
+    ADD COLUMN IF NOT EXISTS ticket_type VARCHAR(20) NOT NULL DEFAULT 'adult' REFERENCES ticket_types (code),
+    ADD COLUMN IF NOT EXISTS price INT;
+UPDATE order_seats os SET price = s.price
+FROM orders o
+JOIN schedules s ON s.id = o.schedule_id
+WHERE o.id = os.order_id AND os.price IS NULL;
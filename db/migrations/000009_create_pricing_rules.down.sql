@@ -0,0 +1,3 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS pricing;
+
+DROP TABLE IF EXISTS pricing_rules;
@@ -0,0 +1,25 @@
+-- aturan harga dinamis, dievaluasi berurutan (priority kecil dulu, lalu id) terhadap schedules.price.
+-- Kondisi yang kosong / NULL tidak membatasi.
+CREATE TABLE IF NOT EXISTS pricing_rules (
+    id               SERIAL PRIMARY KEY,
+    name             VARCHAR(100) NOT NULL,
+    description      TEXT NOT NULL DEFAULT '',
+    priority         INT NOT NULL DEFAULT 100,
+    days_of_week     INT[] NOT NULL DEFAULT '{}', -- 0 = Minggu, dari tanggal tayang
+    start_from       TIME,                        -- jam tayang [start_from, start_until), boleh melewati tengah malam
+    start_until      TIME,
+    premiere_days    INT CHECK (premiere_days > 0), -- tayang dalam N hari sejak release_date
+    min_occupancy    INT CHECK (min_occupancy BETWEEN 0 AND 100),
+    max_occupancy    INT CHECK (max_occupancy BETWEEN 0 AND 100),
+    movie_ids        INT[] NOT NULL DEFAULT '{}',
+    cinema_ids       INT[] NOT NULL DEFAULT '{}',
+    adjustment_type  VARCHAR(10) NOT NULL CHECK (adjustment_type IN ('percent', 'fixed', 'set')),
+    adjustment_value INT NOT NULL,
+    stop_processing  BOOLEAN NOT NULL DEFAULT FALSE, -- rule setelahnya tidak dievaluasi
+    is_active        BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- hasil evaluasi harga saat order dibuat, untuk audit
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pricing JSONB;
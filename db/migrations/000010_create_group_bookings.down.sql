@@ -0,0 +1,4 @@
+ALTER TABLE orders DROP COLUMN IF EXISTS group_booking_id;
+
+DROP TABLE IF EXISTS group_booking_seats;
+DROP TABLE IF EXISTS group_bookings;
@@ -0,0 +1,26 @@
+-- group booking: organiser menahan beberapa kursi, setiap anggota mengklaim satu kursi
+-- dan membayarnya lewat order sendiri (orders.group_booking_id)
+CREATE TABLE IF NOT EXISTS group_bookings (
+    id           SERIAL PRIMARY KEY,
+    token        VARCHAR(32) NOT NULL UNIQUE, -- dipakai di link undangan
+    organiser_id INT NOT NULL REFERENCES users (id),
+    schedule_id  INT NOT NULL REFERENCES schedules (id),
+    status       VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'completed', 'closed', 'cancelled')),
+    deadline     TIMESTAMPTZ NOT NULL, -- kursi yang belum dibayar dilepas setelah ini
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    closed_at    TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS group_bookings_open_idx ON group_bookings (deadline) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS group_booking_seats (
+    group_id   INT NOT NULL REFERENCES group_bookings (id),
+    seat_code  VARCHAR(10) NOT NULL,
+    claimed_by INT REFERENCES users (id),
+    claimed_at TIMESTAMPTZ,
+    order_id   INT REFERENCES orders (id),
+    PRIMARY KEY (group_id, seat_code)
+);
+-- satu kursi per anggota dalam satu group
+CREATE UNIQUE INDEX IF NOT EXISTS group_booking_seats_claim_idx ON group_booking_seats (group_id, claimed_by);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS group_booking_id INT REFERENCES group_bookings (id);
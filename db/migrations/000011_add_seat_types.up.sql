@@ -0,0 +1,4 @@
+-- jenis kursi untuk pemilihan kursi otomatis (best available)
+ALTER TABLE seats
+    ADD COLUMN IF NOT EXISTS seat_type VARCHAR(20) NOT NULL DEFAULT 'standard'
+        CHECK (seat_type IN ('standard', 'wheelchair', 'couple'));
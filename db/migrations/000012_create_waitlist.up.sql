@@ -0,0 +1,18 @@
+-- waitlist jadwal yang sold out, diproses FIFO (urutan id)
+CREATE TABLE IF NOT EXISTS schedule_waitlist (
+    id               SERIAL PRIMARY KEY,
+    schedule_id      INT NOT NULL REFERENCES schedules (id),
+    user_id          INT NOT NULL REFERENCES users (id),
+    seat_count       INT NOT NULL CHECK (seat_count BETWEEN 1 AND 10),
+    status           VARCHAR(20) NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'offered', 'booked', 'expired', 'left')),
+    offered_seats    TEXT[] NOT NULL DEFAULT '{}',
+    offered_at       TIMESTAMPTZ,
+    offer_expires_at TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- satu entry aktif per user per jadwal
+CREATE UNIQUE INDEX IF NOT EXISTS schedule_waitlist_active_idx
+    ON schedule_waitlist (schedule_id, user_id) WHERE status IN ('waiting', 'offered');
+CREATE INDEX IF NOT EXISTS schedule_waitlist_queue_idx ON schedule_waitlist (schedule_id, status, id);
@@ -0,0 +1,26 @@
+-- bahasa template notifikasi
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS language VARCHAR(5) NOT NULL DEFAULT 'en'
+        CHECK (language IN ('en', 'id'));
+
+-- outbox: ditulis di transaksi yang sama dengan perubahan datanya, dikirim oleh worker
+CREATE TABLE IF NOT EXISTS notifications (
+    id              BIGSERIAL PRIMARY KEY,
+    user_id         INT NOT NULL REFERENCES users (id),
+    template        VARCHAR(50) NOT NULL,
+    language        VARCHAR(5) NOT NULL,
+    recipient       TEXT NOT NULL,
+    payload         JSONB NOT NULL DEFAULT '{}',
+    status          VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'sent', 'failed')),
+    channel         VARCHAR(20),
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT,
+    -- juga dipakai sebagai lease saat sedang dikirim worker
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    sent_at         TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS notifications_due_idx ON notifications (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS notifications_user_idx ON notifications (user_id, created_at DESC);
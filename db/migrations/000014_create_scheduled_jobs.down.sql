@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS notifications_dedup_key_idx;
+ALTER TABLE notifications DROP COLUMN IF EXISTS dedup_key;
+
+DROP TABLE IF EXISTS scheduled_jobs;
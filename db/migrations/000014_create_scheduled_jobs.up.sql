@@ -0,0 +1,23 @@
+-- delayed job: dieksekusi sekali setelah due_at, aman untuk banyak replica (SKIP LOCKED + lease di run_at)
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    id          BIGSERIAL PRIMARY KEY,
+    kind        VARCHAR(50) NOT NULL,
+    -- job yang sama tidak pernah dijadwalkan dua kali
+    dedup_key   TEXT NOT NULL UNIQUE,
+    payload     JSONB NOT NULL DEFAULT '{}',
+    due_at      TIMESTAMPTZ NOT NULL,
+    -- percobaan berikutnya, juga lease saat job sedang dikerjakan
+    run_at      TIMESTAMPTZ NOT NULL,
+    status      VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'done', 'cancelled', 'expired', 'failed')),
+    attempts    INT NOT NULL DEFAULT 0,
+    last_error  TEXT,
+    finished_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_jobs_due_idx ON scheduled_jobs (run_at) WHERE status = 'pending';
+
+-- notifikasi dari job (mis. pengingat jam tayang) tidak dikirim dua kali walau job diulang
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS dedup_key TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_dedup_key_idx ON notifications (dedup_key);
@@ -0,0 +1,298 @@
+// Package booking menyimpan hold kursi sementara di Redis dan menyebarkan
+// perubahan status kursi ke semua replica lewat Redis pub/sub.
+package booking
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultHoldTTL = 10 * time.Minute
+
+	holdKeyPrefix = "seat:hold:"  // seat:hold:<schedule>:<seat> = user id atau group:<id> (TTL)
+	holdSetPrefix = "seat:holds:" // seat:holds:<schedule> = ZSET seat -> expiry (unix)
+	holdIndexKey  = "seat:holds:schedules"
+	lockKeyPrefix = "seat:lock:"
+)
+
+// SeatHeldError: kursi sedang di-hold user lain
+type SeatHeldError struct {
+	Seat string
+}
+
+func (e *SeatHeldError) Error() string {
+	return fmt.Sprintf("seat %s is held by another user", e.Seat)
+}
+
+var ErrNoSeats = errors.New("no seats given")
+
+// holdScript: all-or-nothing, gagal jika salah satu kursi di-hold user lain.
+// ARGV: user, ttl, expiry, schedule, prefix, seats...
+var holdScript = redis.NewScript(`
+for i = 6, #ARGV do
+	local owner = redis.call("GET", ARGV[5] .. ARGV[i])
+	if owner and owner ~= ARGV[1] then
+		return ARGV[i]
+	end
+end
+for i = 6, #ARGV do
+	redis.call("SET", ARGV[5] .. ARGV[i], ARGV[1], "EX", ARGV[2])
+	redis.call("ZADD", KEYS[1], ARGV[3], ARGV[i])
+end
+redis.call("SADD", KEYS[2], ARGV[4])
+return ""`)
+
+// unlockScript: lock hanya dihapus oleh pemiliknya. ARGV: token
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// releaseScript: hanya melepas kursi milik user. ARGV: user, prefix, seats...
+var releaseScript = redis.NewScript(`
+local released = {}
+for i = 3, #ARGV do
+	if redis.call("GET", ARGV[2] .. ARGV[i]) == ARGV[1] then
+		redis.call("DEL", ARGV[2] .. ARGV[i])
+		redis.call("ZREM", KEYS[1], ARGV[i])
+		table.insert(released, ARGV[i])
+	end
+end
+return released`)
+
+// Holds: hold kursi per user dengan TTL. Setiap perubahan dipublikasikan lewat Hub.
+type Holds struct {
+	rdb *redis.Client
+	hub *Hub
+	ttl time.Duration
+}
+
+// NewHolds membaca SEAT_HOLD_TTL (mis. "10m")
+func NewHolds(rdb *redis.Client, hub *Hub) *Holds {
+	ttl, err := time.ParseDuration(os.Getenv("SEAT_HOLD_TTL"))
+	if err != nil || ttl <= 0 {
+		ttl = defaultHoldTTL
+	}
+	return &Holds{rdb: rdb, hub: hub, ttl: ttl}
+}
+
+func (h *Holds) TTL() time.Duration { return h.ttl }
+
+// Hold menahan kursi untuk user. Mengembalikan *SeatHeldError jika ada kursi milik user lain.
+func (h *Holds) Hold(ctx context.Context, scheduleID, userID int, seats []string) (time.Time, error) {
+	return h.hold(ctx, scheduleID, strconv.Itoa(userID), seats, time.Now().Add(h.ttl))
+}
+
+// HoldUntil menahan kursi untuk user sampai waktu tertentu, mis. hold prioritas waitlist
+func (h *Holds) HoldUntil(ctx context.Context, scheduleID, userID int, seats []string, until time.Time) error {
+	_, err := h.hold(ctx, scheduleID, strconv.Itoa(userID), seats, until)
+	return err
+}
+
+// HoldGroup menahan kursi group booking sampai deadline. Kursi tidak bisa di-hold
+// atau dipesan user lain sampai dibayar anggota group atau dilepas.
+func (h *Holds) HoldGroup(ctx context.Context, scheduleID, groupID int, seats []string, until time.Time) error {
+	_, err := h.hold(ctx, scheduleID, groupOwner(groupID), seats, until)
+	return err
+}
+
+func (h *Holds) hold(ctx context.Context, scheduleID int, owner string, seats []string, expiresAt time.Time) (time.Time, error) {
+	if len(seats) == 0 {
+		return time.Time{}, ErrNoSeats
+	}
+	ttl := max(int(time.Until(expiresAt).Seconds()), 1)
+
+	args := []interface{}{owner, ttl, expiresAt.Unix(), scheduleID, holdKeyPrefixFor(scheduleID)}
+	for _, s := range seats {
+		args = append(args, s)
+	}
+	conflict, err := holdScript.Run(ctx, h.rdb, []string{holdSetKey(scheduleID), holdIndexKey}, args...).Text()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if conflict != "" {
+		return time.Time{}, &SeatHeldError{Seat: conflict}
+	}
+
+	h.hub.Publish(ctx, models.SeatEvent{ScheduleID: scheduleID, Type: "held", Seats: seats})
+	return expiresAt, nil
+}
+
+// Release melepas kursi milik user, mengembalikan kursi yang benar-benar dilepas
+func (h *Holds) Release(ctx context.Context, scheduleID, userID int, seats []string) ([]string, error) {
+	return h.release(ctx, scheduleID, strconv.Itoa(userID), seats)
+}
+
+// ReleaseGroup melepas kursi group booking yang belum dibayar
+func (h *Holds) ReleaseGroup(ctx context.Context, scheduleID, groupID int, seats []string) ([]string, error) {
+	return h.release(ctx, scheduleID, groupOwner(groupID), seats)
+}
+
+func (h *Holds) release(ctx context.Context, scheduleID int, owner string, seats []string) ([]string, error) {
+	if len(seats) == 0 {
+		return nil, ErrNoSeats
+	}
+	args := []interface{}{owner, holdKeyPrefixFor(scheduleID)}
+	for _, s := range seats {
+		args = append(args, s)
+	}
+	released, err := releaseScript.Run(ctx, h.rdb, []string{holdSetKey(scheduleID)}, args...).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(released) > 0 {
+		h.hub.Publish(ctx, models.SeatEvent{ScheduleID: scheduleID, Type: "released", Seats: released})
+	}
+	return released, nil
+}
+
+// CheckAvailable memastikan tidak ada kursi yang sedang di-hold user lain
+func (h *Holds) CheckAvailable(ctx context.Context, scheduleID, userID int, seats []string) error {
+	prefix := holdKeyPrefixFor(scheduleID)
+	for _, s := range seats {
+		owner, err := h.rdb.Get(ctx, prefix+s).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if owner != strconv.Itoa(userID) {
+			return &SeatHeldError{Seat: s}
+		}
+	}
+	return nil
+}
+
+// Booked dipanggil setelah order tersimpan: hold user dilepas tanpa event
+// "released" lalu event "booked" dipublikasikan
+func (h *Holds) Booked(ctx context.Context, scheduleID, userID int, seats []string) {
+	h.booked(ctx, scheduleID, strconv.Itoa(userID), seats)
+}
+
+// BookedGroup: sama seperti Booked untuk kursi group booking yang sudah dibayar
+func (h *Holds) BookedGroup(ctx context.Context, scheduleID, groupID int, seats []string) {
+	h.booked(ctx, scheduleID, groupOwner(groupID), seats)
+}
+
+func (h *Holds) booked(ctx context.Context, scheduleID int, owner string, seats []string) {
+	args := []interface{}{owner, holdKeyPrefixFor(scheduleID)}
+	for _, s := range seats {
+		args = append(args, s)
+	}
+	releaseScript.Run(ctx, h.rdb, []string{holdSetKey(scheduleID)}, args...)
+	h.hub.Publish(ctx, models.SeatEvent{ScheduleID: scheduleID, Type: "booked", Seats: seats})
+}
+
+// Held: kursi yang sedang di-hold (belum kadaluarsa) untuk satu jadwal
+func (h *Holds) Held(ctx context.Context, scheduleID int) ([]string, error) {
+	return h.rdb.ZRangeByScore(ctx, holdSetKey(scheduleID), &redis.ZRangeBy{
+		Min: strconv.FormatInt(time.Now().Unix()+1, 10),
+		Max: "+inf",
+	}).Result()
+}
+
+// HeldByOthers: kursi yang sedang di-hold selain oleh userID (termasuk kursi group booking)
+func (h *Holds) HeldByOthers(ctx context.Context, scheduleID, userID int) ([]string, error) {
+	seats, err := h.Held(ctx, scheduleID)
+	if err != nil || len(seats) == 0 {
+		return seats, err
+	}
+	prefix := holdKeyPrefixFor(scheduleID)
+	keys := make([]string, len(seats))
+	for i, s := range seats {
+		keys[i] = prefix + s
+	}
+	owners, err := h.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	me := strconv.Itoa(userID)
+	others := []string{}
+	for i, owner := range owners {
+		// nil = hold sudah kadaluarsa tapi belum disapu dari index
+		if owner == nil || owner == me {
+			continue
+		}
+		others = append(others, seats[i])
+	}
+	return others, nil
+}
+
+// sweepExpired menghapus hold yang kadaluarsa dari index dan mempublikasikan
+// "released". Script atomik memastikan hanya satu replica yang mengirim event.
+func (h *Holds) sweepExpired(ctx context.Context) error {
+	schedules, err := h.rdb.SMembers(ctx, holdIndexKey).Result()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().Unix()
+	for _, sid := range schedules {
+		scheduleID, err := strconv.Atoi(sid)
+		if err != nil {
+			h.rdb.SRem(ctx, holdIndexKey, sid)
+			continue
+		}
+		released, err := sweepScript.Run(ctx, h.rdb, []string{holdSetKey(scheduleID), holdIndexKey},
+			now, holdKeyPrefixFor(scheduleID), sid).StringSlice()
+		if err != nil {
+			return err
+		}
+		if len(released) > 0 {
+			h.hub.Publish(ctx, models.SeatEvent{ScheduleID: scheduleID, Type: "released", Seats: released})
+		}
+	}
+	return nil
+}
+
+// sweepScript: ARGV: now, prefix, schedule. Kursi yang key-nya masih ada
+// (baru di-hold lagi) tidak dianggap kadaluarsa.
+var sweepScript = redis.NewScript(`
+local released = {}
+for _, seat in ipairs(redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])) do
+	if redis.call("EXISTS", ARGV[2] .. seat) == 0 then
+		redis.call("ZREM", KEYS[1], seat)
+		table.insert(released, seat)
+	end
+end
+if redis.call("ZCARD", KEYS[1]) == 0 then
+	redis.call("SREM", KEYS[2], ARGV[3])
+end
+return released`)
+
+// Lock: lock sederhana lintas replica (SET NX dengan TTL). ok = false jika dipegang replica lain.
+func (h *Holds) Lock(ctx context.Context, name string, ttl time.Duration) (unlock func(), ok bool, err error) {
+	key := lockKeyPrefix + name
+	token := rand.Text()
+	ok, err = h.rdb.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return func() { unlockScript.Run(context.Background(), h.rdb, []string{key}, token) }, true, nil
+}
+
+// groupOwner: pemilik hold group booking, tidak pernah sama dengan user id
+func groupOwner(groupID int) string {
+	return "group:" + strconv.Itoa(groupID)
+}
+
+func holdKeyPrefixFor(scheduleID int) string {
+	return holdKeyPrefix + strconv.Itoa(scheduleID) + ":"
+}
+
+func holdSetKey(scheduleID int) string {
+	return holdSetPrefix + strconv.Itoa(scheduleID)
+}
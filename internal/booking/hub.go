@@ -0,0 +1,136 @@
+package booking
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	channelPrefix = "seats:schedule:"
+
+	subscriberBuffer = 32
+	watcherBuffer    = 256
+	sweepInterval    = 5 * time.Second
+)
+
+// Hub: satu langganan Redis (PSUBSCRIBE) per replica, event diteruskan ke
+// semua subscriber lokal (koneksi SSE) dari jadwal yang sama.
+type Hub struct {
+	rdb *redis.Client
+
+	mu       sync.Mutex
+	subs     map[int]map[chan models.SeatEvent]struct{}
+	watchers []chan models.SeatEvent
+}
+
+func NewHub(rdb *redis.Client) *Hub {
+	return &Hub{rdb: rdb, subs: map[int]map[chan models.SeatEvent]struct{}{}}
+}
+
+// Run mendengarkan Redis dan membersihkan hold kadaluarsa sampai ctx selesai
+func (h *Hub) Run(ctx context.Context, holds *Holds) {
+	pubsub := h.rdb.PSubscribe(ctx, channelPrefix+"*")
+	defer pubsub.Close()
+
+	sweep := time.NewTicker(sweepInterval)
+	defer sweep.Stop()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sweep.C:
+			if err := holds.sweepExpired(ctx); err != nil {
+				log.Println("seat hold sweep:", err)
+			}
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var ev models.SeatEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &ev); err != nil {
+				log.Println("seat event:", err)
+				continue
+			}
+			h.dispatch(ev)
+		}
+	}
+}
+
+// Publish mengirim event ke semua replica (termasuk replica ini)
+func (h *Hub) Publish(ctx context.Context, ev models.SeatEvent) {
+	if ev.At.IsZero() {
+		ev.At = time.Now()
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+	if err := h.rdb.Publish(ctx, channelPrefix+strconv.Itoa(ev.ScheduleID), data).Err(); err != nil {
+		log.Println("seat event publish:", err)
+	}
+}
+
+// Subscribe mendaftarkan subscriber lokal. Channel ditutup saat cancel dipanggil
+// atau saat subscriber terlalu lambat (client harus reconnect dan ambil snapshot baru).
+func (h *Hub) Subscribe(scheduleID int) (<-chan models.SeatEvent, func()) {
+	ch := make(chan models.SeatEvent, subscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs[scheduleID] == nil {
+		h.subs[scheduleID] = map[chan models.SeatEvent]struct{}{}
+	}
+	h.subs[scheduleID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	return ch, func() { h.remove(scheduleID, ch) }
+}
+
+// Watch: event dari semua jadwal untuk worker di replica ini. Berbeda dengan Subscribe, channel
+// tidak pernah ditutup; event dibuang jika worker tertinggal, jadi worker harus punya sweep sendiri.
+func (h *Hub) Watch() <-chan models.SeatEvent {
+	ch := make(chan models.SeatEvent, watcherBuffer)
+	h.mu.Lock()
+	h.watchers = append(h.watchers, ch)
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *Hub) dispatch(ev models.SeatEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, ch := range h.watchers {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+	for ch := range h.subs[ev.ScheduleID] {
+		select {
+		case ch <- ev:
+		default:
+			delete(h.subs[ev.ScheduleID], ch)
+			close(ch)
+		}
+	}
+}
+
+func (h *Hub) remove(scheduleID int, ch chan models.SeatEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[scheduleID][ch]; ok {
+		delete(h.subs[scheduleID], ch)
+		close(ch)
+	}
+	if len(h.subs[scheduleID]) == 0 {
+		delete(h.subs, scheduleID)
+	}
+}
@@ -0,0 +1,287 @@
+package booking
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	waitRoomPrefix     = "waitroom:"        // waitroom:<scope>:<id>:{config,queue,seen,active,token:<user>}
+	waitRoomEnabledKey = "waitroom:enabled" // SET "<scope>:<id>" yang aktif
+
+	defaultTokenTTL   = 10 * time.Minute
+	defaultAvgSession = 3 * time.Minute
+	// user yang tidak polling selama ini dianggap keluar dari antrian
+	queueStaleAfter = time.Minute
+)
+
+var (
+	ErrInvalidScope = errors.New("scope must be schedule or movie")
+	ErrNotInQueue   = errors.New("not in queue, join first")
+)
+
+// admitScript membersihkan slot/antrian kadaluarsa lalu memasukkan user ke active
+// jika posisinya masih dalam kapasitas. Return {posisi (0 = admitted), token, expiry ms}.
+// KEYS: queue, seen, active, token. ARGV: user, now ms, capacity, ttl ms, token baru, stale ms, join (1/0)
+var admitScript = redis.NewScript(`
+local now, capacity, ttl, stale = tonumber(ARGV[2]), tonumber(ARGV[3]), tonumber(ARGV[4]), tonumber(ARGV[6])
+redis.call("ZREMRANGEBYSCORE", KEYS[3], "-inf", now)
+for _, m in ipairs(redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", now - stale)) do
+	redis.call("ZREM", KEYS[1], m)
+	redis.call("ZREM", KEYS[2], m)
+end
+
+local expiry = redis.call("ZSCORE", KEYS[3], ARGV[1])
+if expiry then
+	return {0, redis.call("GET", KEYS[4]) or "", tonumber(expiry)}
+end
+
+if not redis.call("ZSCORE", KEYS[1], ARGV[1]) then
+	if ARGV[7] ~= "1" then
+		return {-1, "", 0}
+	end
+	redis.call("ZADD", KEYS[1], now, ARGV[1])
+end
+redis.call("ZADD", KEYS[2], now, ARGV[1])
+
+local rank = redis.call("ZRANK", KEYS[1], ARGV[1])
+if rank < capacity - redis.call("ZCARD", KEYS[3]) then
+	redis.call("ZREM", KEYS[1], ARGV[1])
+	redis.call("ZREM", KEYS[2], ARGV[1])
+	redis.call("ZADD", KEYS[3], now + ttl, ARGV[1])
+	redis.call("SET", KEYS[4], ARGV[5], "PX", ttl)
+	return {0, ARGV[5], now + ttl}
+end
+return {rank + 1, "", 0}`)
+
+// WaitRoom: antrian virtual per jadwal atau per movie. Hanya Capacity user yang
+// memegang admission token pada satu waktu, sisanya menunggu di ZSET sesuai urutan join.
+type WaitRoom struct {
+	rdb *redis.Client
+}
+
+func NewWaitRoom(rdb *redis.Client) *WaitRoom {
+	return &WaitRoom{rdb: rdb}
+}
+
+func waitRoomKey(scope string, id int, suffix string) string {
+	return fmt.Sprintf("%s%s:%d:%s", waitRoomPrefix, scope, id, suffix)
+}
+
+func validScope(scope string) bool {
+	return scope == "schedule" || scope == "movie"
+}
+
+// Config membaca pengaturan, redis.Nil jika belum pernah diset
+func (w *WaitRoom) Config(ctx context.Context, scope string, id int) (*models.WaitRoomConfig, error) {
+	if !validScope(scope) {
+		return nil, ErrInvalidScope
+	}
+	data, err := w.rdb.Get(ctx, waitRoomKey(scope, id, "config")).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var cfg models.WaitRoomConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// SetConfig menyimpan pengaturan; antrian yang sudah ada tetap dipertahankan
+func (w *WaitRoom) SetConfig(ctx context.Context, cfg models.WaitRoomConfig) error {
+	if !validScope(cfg.Scope) {
+		return ErrInvalidScope
+	}
+	if cfg.TokenTTLSeconds <= 0 {
+		cfg.TokenTTLSeconds = int(defaultTokenTTL / time.Second)
+	}
+	if cfg.AvgSessionSeconds <= 0 {
+		cfg.AvgSessionSeconds = int(defaultAvgSession / time.Second)
+	}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	member := fmt.Sprintf("%s:%d", cfg.Scope, cfg.ScopeID)
+	pipe := w.rdb.TxPipeline()
+	pipe.Set(ctx, waitRoomKey(cfg.Scope, cfg.ScopeID, "config"), data, 0)
+	if cfg.Enabled {
+		pipe.SAdd(ctx, waitRoomEnabledKey, member)
+	} else {
+		pipe.SRem(ctx, waitRoomEnabledKey, member)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Delete menghapus pengaturan beserta antrian dan slot aktif.
+// Token yang sudah dibagikan ikut tidak berlaku karena waiting room tidak aktif lagi.
+func (w *WaitRoom) Delete(ctx context.Context, scope string, id int) error {
+	if !validScope(scope) {
+		return ErrInvalidScope
+	}
+	pipe := w.rdb.TxPipeline()
+	pipe.SRem(ctx, waitRoomEnabledKey, fmt.Sprintf("%s:%d", scope, id))
+	for _, suffix := range []string{"config", "queue", "seen", "active"} {
+		pipe.Del(ctx, waitRoomKey(scope, id, suffix))
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Stats: pengaturan beserta jumlah user yang menunggu dan yang sedang booking
+func (w *WaitRoom) Stats(ctx context.Context, scope string, id int) (*models.WaitRoomStats, error) {
+	cfg, err := w.Config(ctx, scope, id)
+	if err != nil {
+		return nil, err
+	}
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	waiting, err := w.rdb.ZCard(ctx, waitRoomKey(scope, id, "queue")).Result()
+	if err != nil {
+		return nil, err
+	}
+	active, err := w.rdb.ZCount(ctx, waitRoomKey(scope, id, "active"), "("+now, "+inf").Result()
+	if err != nil {
+		return nil, err
+	}
+	return &models.WaitRoomStats{WaitRoomConfig: *cfg, Waiting: waiting, Active: active}, nil
+}
+
+// Resolve mencari waiting room yang berlaku untuk satu jadwal: per jadwal lebih dulu,
+// lalu per movie. movieID hanya dipanggil jika ada waiting room per movie yang aktif.
+// Mengembalikan nil jika tidak ada antrian.
+func (w *WaitRoom) Resolve(ctx context.Context, scheduleID int, movieID func(context.Context, int) (int, error)) (*models.WaitRoomConfig, error) {
+	enabled, err := w.rdb.SMembers(ctx, waitRoomEnabledKey).Result()
+	if err != nil || len(enabled) == 0 {
+		return nil, err
+	}
+
+	scheduleMember := fmt.Sprintf("schedule:%d", scheduleID)
+	hasMovie := false
+	for _, m := range enabled {
+		if m == scheduleMember {
+			return w.enabledConfig(ctx, "schedule", scheduleID)
+		}
+		hasMovie = hasMovie || strings.HasPrefix(m, "movie:")
+	}
+	if !hasMovie {
+		return nil, nil
+	}
+
+	mid, err := movieID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range enabled {
+		if m == fmt.Sprintf("movie:%d", mid) {
+			return w.enabledConfig(ctx, "movie", mid)
+		}
+	}
+	return nil, nil
+}
+
+func (w *WaitRoom) enabledConfig(ctx context.Context, scope string, id int) (*models.WaitRoomConfig, error) {
+	cfg, err := w.Config(ctx, scope, id)
+	if errors.Is(err, redis.Nil) || (err == nil && !cfg.Enabled) {
+		return nil, nil
+	}
+	return cfg, err
+}
+
+// Join memasukkan user ke antrian (atau langsung admitted jika masih ada slot)
+func (w *WaitRoom) Join(ctx context.Context, cfg *models.WaitRoomConfig, userID int) (*models.QueueStatus, error) {
+	return w.admit(ctx, cfg, userID, true)
+}
+
+// Status: posisi terbaru user. Client perlu polling secara berkala;
+// user yang berhenti polling lebih dari satu menit dikeluarkan dari antrian.
+func (w *WaitRoom) Status(ctx context.Context, cfg *models.WaitRoomConfig, userID int) (*models.QueueStatus, error) {
+	return w.admit(ctx, cfg, userID, false)
+}
+
+func (w *WaitRoom) admit(ctx context.Context, cfg *models.WaitRoomConfig, userID int, join bool) (*models.QueueStatus, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+	joinArg := "0"
+	if join {
+		joinArg = "1"
+	}
+	ttl := time.Duration(cfg.TokenTTLSeconds) * time.Second
+	keys := []string{
+		waitRoomKey(cfg.Scope, cfg.ScopeID, "queue"),
+		waitRoomKey(cfg.Scope, cfg.ScopeID, "seen"),
+		waitRoomKey(cfg.Scope, cfg.ScopeID, "active"),
+		waitRoomKey(cfg.Scope, cfg.ScopeID, "token:"+strconv.Itoa(userID)),
+	}
+	res, err := admitScript.Run(ctx, w.rdb, keys,
+		userID, time.Now().UnixMilli(), cfg.Capacity, ttl.Milliseconds(), token, queueStaleAfter.Milliseconds(), joinArg,
+	).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	position, _ := res[0].(int64)
+	status := &models.QueueStatus{Scope: cfg.Scope, ScopeID: cfg.ScopeID, Enabled: true}
+	switch {
+	case position < 0:
+		return nil, ErrNotInQueue
+	case position == 0:
+		expiryMs, _ := res[2].(int64)
+		expiresAt := time.UnixMilli(expiryMs)
+		status.Admitted = true
+		status.Token, _ = res[1].(string)
+		status.ExpiresAt = &expiresAt
+	default:
+		status.Position = int(position)
+		// setiap "gelombang" Capacity user butuh kira-kira satu sesi booking
+		waves := (int(position) + cfg.Capacity - 1) / cfg.Capacity
+		status.EstimatedWaitSeconds = waves * cfg.AvgSessionSeconds
+	}
+	return status, nil
+}
+
+// Admitted: true jika token milik user dan masih berlaku
+func (w *WaitRoom) Admitted(ctx context.Context, cfg *models.WaitRoomConfig, userID int, token string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+	stored, err := w.rdb.Get(ctx, waitRoomKey(cfg.Scope, cfg.ScopeID, "token:"+strconv.Itoa(userID))).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	return stored == token, err
+}
+
+// Leave melepas slot (mis. setelah order dibuat) supaya user berikutnya bisa masuk
+func (w *WaitRoom) Leave(ctx context.Context, cfg *models.WaitRoomConfig, userID int) error {
+	user := strconv.Itoa(userID)
+	pipe := w.rdb.TxPipeline()
+	pipe.ZRem(ctx, waitRoomKey(cfg.Scope, cfg.ScopeID, "active"), user)
+	pipe.ZRem(ctx, waitRoomKey(cfg.Scope, cfg.ScopeID, "queue"), user)
+	pipe.ZRem(ctx, waitRoomKey(cfg.Scope, cfg.ScopeID, "seen"), user)
+	pipe.Del(ctx, waitRoomKey(cfg.Scope, cfg.ScopeID, "token:"+user))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,116 @@
+// Package export menulis tabel (header + baris) sebagai CSV, JSONL atau XLSX
+// secara streaming: setiap baris langsung ditulis ke io.Writer tanpa ditampung.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format, use csv, jsonl or xlsx")
+
+// Writer: WriteHeader dipanggil sekali sebelum WriteRow, Close wajib dipanggil di akhir
+type Writer interface {
+	WriteHeader(columns []string) error
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+var contentTypes = map[string]string{
+	"csv":   "text/csv; charset=utf-8",
+	"jsonl": "application/x-ndjson",
+	"xlsx":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ContentType untuk header response, "" jika format tidak dikenal
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case "jsonl":
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case "xlsx":
+		return newXLSXWriter(w), nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ===================== CSV =====================
+
+type csvWriter struct {
+	w   *csv.Writer
+	buf []string
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) WriteRow(values []interface{}) error {
+	c.buf = c.buf[:0]
+	for _, v := range values {
+		c.buf = append(c.buf, formatValue(v))
+	}
+	return c.w.Write(c.buf)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ===================== JSONL =====================
+
+type jsonlWriter struct {
+	enc     *json.Encoder
+	columns []string
+}
+
+func (j *jsonlWriter) WriteHeader(columns []string) error {
+	j.columns = columns
+	return nil
+}
+
+func (j *jsonlWriter) WriteRow(values []interface{}) error {
+	row := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		if t, ok := v.(time.Time); ok {
+			v = formatValue(t)
+		}
+		row[j.columns[i]] = v
+	}
+	return j.enc.Encode(row)
+}
+
+func (j *jsonlWriter) Close() error { return nil }
+
+// formatValue: representasi teks untuk CSV/XLSX
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,60 @@
+// Package giftcard membuat dan menormalkan kode gift card serta mendefinisikan
+// alasan gift card tidak bisa dipakai.
+package giftcard
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// tanpa 0/O dan 1/I supaya mudah dibaca dan diketik
+const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Error: gift card tidak bisa dipakai untuk order ini
+type Error struct {
+	Reason  string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+var (
+	ErrNotFound = &Error{Reason: "gift_card_not_found", Message: "gift card not found"}
+	ErrVoided   = &Error{Reason: "gift_card_voided", Message: "gift card has been voided"}
+	ErrExpired  = &Error{Reason: "gift_card_expired", Message: "gift card has expired"}
+	ErrEmpty    = &Error{Reason: "gift_card_empty", Message: "gift card has no balance left"}
+)
+
+// NewCode: 16 karakter acak dalam format XXXX-XXXX-XXXX-XXXX
+func NewCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = alphabet[int(b[i])%len(alphabet)]
+	}
+	return format(string(b)), nil
+}
+
+// Normalize menerima kode dengan atau tanpa tanda hubung / spasi, huruf besar maupun kecil
+func Normalize(code string) string {
+	code = strings.ToUpper(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	if len(code) != 16 {
+		return code
+	}
+	return format(code)
+}
+
+func format(code string) string {
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+}
+
+// Mask menyembunyikan kode kecuali 4 karakter terakhir
+func Mask(code string) string {
+	if len(code) < 4 {
+		return code
+	}
+	return "XXXX-XXXX-XXXX-" + code[len(code)-4:]
+}
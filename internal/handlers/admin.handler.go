@@ -2,25 +2,31 @@ package handlers
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
+	"github.com/cristian-yw/Weekly10/internal/importer"
+	"github.com/cristian-yw/Weekly10/internal/jobs"
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/models"
 	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/cristian-yw/Weekly10/internal/tmdb"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
 )
 
 type AdminHandler struct {
-	repo *repository.AdminRepository
+	repo  *repository.AdminRepository
+	sync  *jobs.SyncRunner
+	media *media.Store
 }
 
-func NewAdminHandler(repo *repository.AdminRepository) *AdminHandler {
-	return &AdminHandler{repo: repo}
+func NewAdminHandler(repo *repository.AdminRepository, sync *jobs.SyncRunner, media *media.Store) *AdminHandler {
+	return &AdminHandler{repo: repo, sync: sync, media: media}
 }
 
 // ===================== CRUD =====================
@@ -40,12 +46,14 @@ func NewAdminHandler(repo *repository.AdminRepository) *AdminHandler {
 // @Param vote_average formData number false "Vote Average"
 // @Param vote_count formData int false "Vote Count"
 // @Param genres formData string false "Comma separated genres (e.g. Action,Drama)"
-// @Param schedules formData string false "JSON array of schedules. Example: [{\"cinema_id\":1,\"location_id\":1,\"time_id\":2,\"date\":\"2025-09-21\"}]"
-// @Param poster formData file false "Poster image file"
-// @Param backdrop formData file false "Backdrop image file"
+// @Param schedules formData string false "JSON array of schedules, auditorium_id optional (default studio of the cinema). Example: [{\"cinema_id\":1,\"auditorium_id\":3,\"location_id\":1,\"time_id\":2,\"date\":\"2025-09-21\"}]"
+// @Param poster formData file false "Poster image file (jpeg, png or gif)"
+// @Param backdrop formData file false "Backdrop image file (jpeg, png or gif)"
 // @Success 201 {object} map[string]interface{} "movie_id returned"
 // @Failure 400 {object} models.ErrorResponse "Invalid input"
+// @Failure 409 {object} models.ErrorResponse "Movie with the same tmdb_id already exists"
 // @Failure 401 {object} models.ErrorResponse "Unauthorized (Missing or invalid token)"
+// @Failure 413 {object} models.ErrorResponse "Image too large"
 // @Failure 500 {object} models.ErrorResponse "Server error"
 // @Router /admin/movies [post]
 func (h *AdminHandler) CreateMovie(c *gin.Context) {
@@ -131,43 +139,181 @@ func (h *AdminHandler) CreateMovie(c *gin.Context) {
 		req.Casts = casts
 	}
 
-	// Upload poster
-	if file, err := c.FormFile("poster"); err == nil {
-		if err := os.MkdirAll("/", os.ModePerm); err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
-			return
+	// Upload poster & backdrop (divalidasi, disimpan dengan nama hash + varian)
+	for field, target := range map[string]*string{"poster": &req.PosterPath, "backdrop": &req.BackdropPath} {
+		file, err := c.FormFile(field)
+		if err != nil {
+			continue
 		}
-		dst := filepath.Join(fmt.Sprintf("%d_%s", time.Now().Unix(), file.Filename))
-		if err := c.SaveUploadedFile(file, dst); err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		img, err := h.media.SaveUpload(c.Request.Context(), file, field+"s")
+		if err != nil {
+			mediaError(c, err)
 			return
 		}
-		req.PosterPath = dst
+		*target = img.Key
 	}
 
-	// Upload backdrop
-	if file, err := c.FormFile("backdrop"); err == nil {
-		if err := os.MkdirAll("uploads/backdrop", os.ModePerm); err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
-			return
-		}
-		dst := filepath.Join("/", fmt.Sprintf("%d_%s", time.Now().Unix(), file.Filename))
-		if err := c.SaveUploadedFile(file, dst); err != nil {
+	// Simpan ke DB
+	movieID, err := h.repo.CreateMovie(c.Request.Context(), req)
+	if errors.Is(err, repository.ErrMovieExists) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "movie created successfully",
+		"movie_id": movieID,
+	})
+}
+
+// @Summary Bulk import movies and schedules
+// @Description Import a CSV or JSON file of movies with their schedules. Every row is validated first;
+// @Description with dry_run=true (default) nothing is saved and the report shows what would happen.
+// @Description With dry_run=false everything is applied in one transaction. Movies are matched on tmdb_id
+// @Description and existing schedules are skipped, so re-importing the same file does not create duplicates.
+// @Description CSV columns: tmdb_id,title,overview,release_date,runtime,poster_path,backdrop_path,genres (a|b),
+// @Description director_tmdb_id,director_name,cinema_id,auditorium_id,location_id,time_id,date,price (one schedule per row).
+// @Description JSON: array of movies, each with an optional "schedules" array.
+// @Tags Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or JSON file"
+// @Param format formData string false "csv or json (default: from file extension)"
+// @Param dry_run query bool false "Validate only, do not save" default(true)
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 422 {object} models.ImportReport "validation errors, nothing saved"
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/import [post]
+func (h *AdminHandler) ImportMovies(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "dry_run must be true or false"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "file is required"})
+		return
+	}
+	format, err := importer.Format(c.PostForm("format"), file.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	defer f.Close()
+
+	movies, parseErrs, err := importer.Parse(f, format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	report := &models.ImportReport{DryRun: dryRun, Movies: len(movies), Errors: []models.ImportError{}}
+	report.Errors = append(append(report.Errors, parseErrs...), importer.Validate(movies)...)
+	if len(report.Errors) == 0 {
+		if report, err = h.repo.ImportMovies(c.Request.Context(), movies, dryRun); err != nil {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 			return
 		}
-		req.BackdropPath = dst
 	}
 
-	// Simpan ke DB
-	movieID, err := h.repo.CreateMovie(c.Request.Context(), req)
+	status := http.StatusOK
+	if len(report.Errors) > 0 && !dryRun {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, report)
+}
+
+// @Summary Search movies on TMDB
+// @Description Proxy of TMDB /search/movie, use the result id for POST /admin/movies/import/{tmdbId}
+// @Tags Admin
+// @Produce json
+// @Param q query string true "Search keyword"
+// @Param page query int false "Page" default(1)
+// @Success 200 {object} models.TMDBResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/tmdb/search [get]
+func (h *AdminHandler) SearchTMDB(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "q is required"})
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	result, err := h.repo.SearchTMDB(c.Request.Context(), q, page)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Import a movie from TMDB
+// @Description Fetch details, credits, genres and images of a TMDB movie and create it
+// @Description (same transaction as POST /admin/movies). Schedules can optionally be attached.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param tmdbId path int true "TMDB movie ID"
+// @Param body body models.ImportMovieRequest false "Optional schedules, cast limit and image mirroring"
+// @Success 201 {object} map[string]interface{} "movie_id returned"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "Not found on TMDB"
+// @Failure 409 {object} models.ErrorResponse "Movie already imported"
+// @Failure 502 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/movies/import/{tmdbId} [post]
+func (h *AdminHandler) ImportMovie(c *gin.Context) {
+	tmdbID, err := strconv.Atoi(c.Param("tmdbId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid tmdb id"})
+		return
+	}
+
+	// body boleh kosong (io.EOF), termasuk request chunked tanpa Content-Length
+	var req models.ImportMovieRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	movieID, err := h.repo.ImportMovie(c.Request.Context(), tmdbID, req)
+	if err != nil {
+		var apiErr *tmdb.APIError
+		switch {
+		case errors.Is(err, repository.ErrMovieExists):
+			c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+		case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "movie not found on tmdb"})
+		case errors.As(err, &apiErr):
+			c.JSON(http.StatusBadGateway, models.ErrorResponse{Error: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		}
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"message":  "movie created successfully",
+		"message":  "movie imported successfully",
 		"movie_id": movieID,
 	})
 }
@@ -193,6 +339,9 @@ func (h *AdminHandler) GetMovieByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "movie not found"})
 		return
 	}
+	base := requestBaseURL(c)
+	movie.PosterURL = h.media.PosterURL(c.Request.Context(), base, movie.PosterPath)
+	movie.BackdropURL = h.media.BackdropURL(c.Request.Context(), base, movie.BackdropPath)
 
 	c.JSON(http.StatusOK, movie)
 }
@@ -240,6 +389,66 @@ func (h *AdminHandler) PatchMovie(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "movie patched successfully"})
 }
 
+// @Summary Replace movie images
+// @Description Upload a new poster and/or backdrop. The previous images are removed from storage.
+// @Tags Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Movie ID"
+// @Param poster formData file false "Poster image file (jpeg, png or gif)"
+// @Param backdrop formData file false "Backdrop image file (jpeg, png or gif)"
+// @Success 200 {object} models.TMDBMovie
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/movies/{id}/images [patch]
+func (h *AdminHandler) ReplaceMovieImages(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid movie id"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	var poster, backdrop *string
+	for field, target := range map[string]**string{"poster": &poster, "backdrop": &backdrop} {
+		file, err := c.FormFile(field)
+		if err != nil {
+			continue
+		}
+		img, err := h.media.SaveUpload(ctx, file, field+"s")
+		if err != nil {
+			mediaError(c, err)
+			return
+		}
+		*target = &img.Key
+	}
+	if poster == nil && backdrop == nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "poster or backdrop file is required"})
+		return
+	}
+
+	oldPoster, oldBackdrop, err := h.repo.ReplaceMovieImages(ctx, id, poster, backdrop)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "movie not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if poster != nil {
+		deleteReplacedImage(ctx, h.media, h.repo.ImageInUse, oldPoster, *poster)
+	}
+	if backdrop != nil {
+		deleteReplacedImage(ctx, h.media, h.repo.ImageInUse, oldBackdrop, *backdrop)
+	}
+
+	h.GetMovieByID(c)
+}
+
 // @Summary Delete movie
 // @Tags Admin
 // @Produce json
@@ -267,20 +476,127 @@ func (h *AdminHandler) DeleteMovie(c *gin.Context) {
 
 // ===================== TMDB SYNC & LIST =====================
 
+// @Summary Sync Movies from TMDB
+// @Description Start a background sync of a TMDB list (popular, upcoming, now_playing, top_rated),
+// @Description including runtime, director and top-billed cast of every movie.
+// @Description Returns 202 with the job; poll GET /admin/sync/jobs/{id} for progress.
+// @Tags Admin
+// @Produce json
+// @Param list query string false "TMDB list" Enums(popular, upcoming, now_playing, top_rated) default(popular)
+// @Param pages query int false "Number of pages to fetch" default(1)
+// @Param cast_limit query int false "Number of top-billed cast to store" default(10)
+// @Param mirror_images query bool false "Download posters and backdrops into our media storage" default(false)
+// @Success 202 {object} models.SyncJob
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} map[string]string "another sync is running"
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/sync [post]
+func (h *AdminHandler) SyncMovies(c *gin.Context) {
+	h.syncMovies(c, c.DefaultQuery("list", "popular"))
+}
+
 // @Summary Sync Popular Movies
-// @Description Fetch popular movies from TMDB and store in database
+// @Description Start a background sync of popular movies from TMDB
 // @Tags Admin
 // @Produce json
-// @Success 200 {object} models.SuccessMessage
+// @Param pages query int false "Number of pages to fetch" default(1)
+// @Param mirror_images query bool false "Download posters and backdrops into our media storage" default(false)
+// @Success 202 {object} models.SyncJob
+// @Failure 409 {object} map[string]string "another sync is running"
 // @Failure 500 {object} models.ErrorResponse
 // @Security BearerAuth
 // @Router /admin/sync/popular [post]
 func (h *AdminHandler) SyncPopular(c *gin.Context) {
-	apiKey := os.Getenv("API_KEY")
-	if err := h.repo.SyncPopular(apiKey); err != nil {
+	h.syncMovies(c, "popular")
+}
+
+func (h *AdminHandler) syncMovies(c *gin.Context, list string) {
+	if !repository.SyncLists[list] {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "list must be one of popular, upcoming, now_playing, top_rated"})
+		return
+	}
+
+	pages, _ := strconv.Atoi(c.DefaultQuery("pages", "1"))
+	castLimit, _ := strconv.Atoi(c.DefaultQuery("cast_limit", "10"))
+	mirror, _ := strconv.ParseBool(c.DefaultQuery("mirror_images", "false"))
+
+	job, runningID, err := h.sync.Start(c.Request.Context(), models.SyncOptions{
+		List:         list,
+		Pages:        pages,
+		CastLimit:    castLimit,
+		MirrorImages: mirror,
+	}, "manual")
+	if errors.Is(err, jobs.ErrSyncRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job_id": runningID})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// @Summary Get TMDB sync job
+// @Description Progress, created/updated/failed counts and errors of a sync job
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.SyncJob
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/sync/jobs/{id} [get]
+func (h *AdminHandler) GetSyncJob(c *gin.Context) {
+	job, err := h.sync.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "sync job not found"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessMessage{Message: "Popular movies synced successfully"})
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Set user role
+// @Description Promote a user to staff (concession pickup) or admin, or demote back to user.
+// @Description The new role applies from the user's next login.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param body body models.UserRoleRequest true "Role"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid user id"})
+		return
+	}
+	var req models.UserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = h.repo.SetUserRole(c.Request.Context(), id, req.Role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "user not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "user role updated successfully"})
 }
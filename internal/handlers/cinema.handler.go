@@ -0,0 +1,586 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type CinemaHandler struct {
+	repo *repository.CinemaRepository
+}
+
+func NewCinemaHandler(repo *repository.CinemaRepository) *CinemaHandler {
+	return &CinemaHandler{repo: repo}
+}
+
+// ===================== CINEMAS =====================
+
+// @Summary List cinemas (admin)
+// @Description List all cinemas including retired ones
+// @Tags Admin Cinemas
+// @Produce json
+// @Success 200 {array} models.Cinema
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas [get]
+func (h *CinemaHandler) ListCinemas(c *gin.Context) {
+	cinemas, err := h.repo.ListCinemas(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cinemas)
+}
+
+// @Summary Create cinema
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param body body models.CinemaRequest true "Cinema"
+// @Success 201 {object} models.Cinema
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas [post]
+func (h *CinemaHandler) CreateCinema(c *gin.Context) {
+	var req models.CinemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	cinema, err := h.repo.CreateCinema(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, cinema)
+}
+
+// @Summary Update cinema
+// @Description Only the fields sent will be updated
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Param body body models.CinemaUpdate true "Fields to update"
+// @Success 200 {object} models.Cinema
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas/{id} [patch]
+func (h *CinemaHandler) UpdateCinema(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid cinema id"})
+		return
+	}
+
+	var req models.CinemaUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	cinema, err := h.repo.UpdateCinema(c.Request.Context(), id, req)
+	if err != nil {
+		referenceError(c, "cinema", err)
+		return
+	}
+	c.JSON(http.StatusOK, cinema)
+}
+
+// @Summary Retire cinema
+// @Description Deactivate a cinema. Rejected with 409 while upcoming schedules still use it.
+// @Tags Admin Cinemas
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas/{id} [delete]
+func (h *CinemaHandler) RetireCinema(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid cinema id"})
+		return
+	}
+
+	if err := h.repo.RetireCinema(c.Request.Context(), id); err != nil {
+		referenceError(c, "cinema", err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "cinema retired successfully"})
+}
+
+// ===================== AUDITORIUMS =====================
+
+// @Summary List auditoriums of a cinema
+// @Tags Admin Cinemas
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Success 200 {array} models.Auditorium
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas/{id}/auditoriums [get]
+func (h *CinemaHandler) ListAuditoriums(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid cinema id"})
+		return
+	}
+
+	auditoriums, err := h.repo.ListAuditoriums(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, auditoriums)
+}
+
+// @Summary Create auditorium
+// @Description Add a studio (2D, 3D, IMAX, 4DX) to an active cinema
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Param body body models.AuditoriumRequest true "Auditorium"
+// @Success 201 {object} models.Auditorium
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas/{id}/auditoriums [post]
+func (h *CinemaHandler) CreateAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid cinema id"})
+		return
+	}
+
+	var req models.AuditoriumRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	auditorium, err := h.repo.CreateAuditorium(c.Request.Context(), id, req)
+	if err != nil {
+		referenceError(c, "cinema", err)
+		return
+	}
+	c.JSON(http.StatusCreated, auditorium)
+}
+
+// @Summary Update auditorium
+// @Description Only the fields sent will be updated
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Param body body models.AuditoriumUpdate true "Fields to update"
+// @Success 200 {object} models.Auditorium
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/auditoriums/{id} [patch]
+func (h *CinemaHandler) UpdateAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid auditorium id"})
+		return
+	}
+
+	var req models.AuditoriumUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	auditorium, err := h.repo.UpdateAuditorium(c.Request.Context(), id, req)
+	if err != nil {
+		referenceError(c, "auditorium", err)
+		return
+	}
+	c.JSON(http.StatusOK, auditorium)
+}
+
+// @Summary Retire auditorium
+// @Description Deactivate a studio. Rejected with 409 while upcoming schedules still use it.
+// @Tags Admin Cinemas
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/auditoriums/{id} [delete]
+func (h *CinemaHandler) RetireAuditorium(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid auditorium id"})
+		return
+	}
+
+	if err := h.repo.RetireAuditorium(c.Request.Context(), id); err != nil {
+		referenceError(c, "auditorium", err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "auditorium retired successfully"})
+}
+
+// ===================== SEATS =====================
+
+// @Summary List seat inventory of an auditorium
+// @Tags Admin Cinemas
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Success 200 {array} models.SeatInventory
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/auditoriums/{id}/seats [get]
+func (h *CinemaHandler) ListSeats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid auditorium id"})
+		return
+	}
+
+	seats, err := h.repo.ListSeats(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, seats)
+}
+
+// @Summary Add seats to an auditorium
+// @Description Send seat_codes, or rows + seats_per_row to generate A1..A10, B1..B10, etc.
+// @Description Existing (also retired) seats are re-activated. seat_type (standard, wheelchair, couple) applies to
+// @Description every seat in the request; when empty new seats are standard and existing seats keep their type.
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Auditorium ID"
+// @Param body body models.SeatInventoryRequest true "Seats"
+// @Success 201 {array} models.SeatInventory
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/auditoriums/{id}/seats [post]
+func (h *CinemaHandler) AddSeats(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid auditorium id"})
+		return
+	}
+
+	var req models.SeatInventoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	codes := seatCodes(req)
+	if len(codes) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "no seats provided"})
+		return
+	}
+
+	seats, err := h.repo.AddSeats(c.Request.Context(), id, codes, req.SeatType)
+	if err != nil {
+		referenceError(c, "auditorium", err)
+		return
+	}
+	c.JSON(http.StatusCreated, seats)
+}
+
+// @Summary Retire seat
+// @Description Deactivate a seat. Rejected with 409 while it is sold or held by a pending order for an upcoming schedule.
+// @Tags Admin Cinemas
+// @Produce json
+// @Param id path int true "Seat ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/seats/{id} [delete]
+func (h *CinemaHandler) RetireSeat(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid seat id"})
+		return
+	}
+
+	if err := h.repo.RetireSeat(c.Request.Context(), id); err != nil {
+		referenceError(c, "seat", err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "seat retired successfully"})
+}
+
+// ===================== LOCATIONS =====================
+
+// @Summary List locations (admin)
+// @Tags Admin Cinemas
+// @Produce json
+// @Success 200 {array} models.Location
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/locations [get]
+func (h *CinemaHandler) ListLocations(c *gin.Context) {
+	locations, err := h.repo.ListLocations(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, locations)
+}
+
+// @Summary Create location
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param body body models.LocationRequest true "Location"
+// @Success 201 {object} models.Location
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/locations [post]
+func (h *CinemaHandler) CreateLocation(c *gin.Context) {
+	var req models.LocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	location, err := h.repo.CreateLocation(c.Request.Context(), req.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, location)
+}
+
+// @Summary Update location
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Location ID"
+// @Param body body models.LocationRequest true "Location"
+// @Success 200 {object} models.Location
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/locations/{id} [patch]
+func (h *CinemaHandler) UpdateLocation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid location id"})
+		return
+	}
+
+	var req models.LocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	location, err := h.repo.UpdateLocation(c.Request.Context(), id, req.Name)
+	if err != nil {
+		referenceError(c, "location", err)
+		return
+	}
+	c.JSON(http.StatusOK, location)
+}
+
+// @Summary Retire location
+// @Description Deactivate a location. Rejected with 409 while upcoming schedules still use it.
+// @Tags Admin Cinemas
+// @Produce json
+// @Param id path int true "Location ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/locations/{id} [delete]
+func (h *CinemaHandler) RetireLocation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid location id"})
+		return
+	}
+
+	if err := h.repo.RetireLocation(c.Request.Context(), id); err != nil {
+		referenceError(c, "location", err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "location retired successfully"})
+}
+
+// ===================== TIMES =====================
+
+// @Summary List showtime slots (admin)
+// @Tags Admin Cinemas
+// @Produce json
+// @Success 200 {array} models.ShowTime
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/times [get]
+func (h *CinemaHandler) ListTimes(c *gin.Context) {
+	times, err := h.repo.ListTimes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, times)
+}
+
+// @Summary Create showtime slot
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param body body models.ShowTimeRequest true "Start time (HH:MM)"
+// @Success 201 {object} models.ShowTime
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/times [post]
+func (h *CinemaHandler) CreateTime(c *gin.Context) {
+	var req models.ShowTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if _, err := time.Parse("15:04", req.StartTime); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "start_time must be HH:MM"})
+		return
+	}
+
+	t, err := h.repo.CreateTime(c.Request.Context(), req.StartTime)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, t)
+}
+
+// @Summary Update showtime slot
+// @Tags Admin Cinemas
+// @Accept json
+// @Produce json
+// @Param id path int true "Time ID"
+// @Param body body models.ShowTimeRequest true "Start time (HH:MM)"
+// @Success 200 {object} models.ShowTime
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/times/{id} [patch]
+func (h *CinemaHandler) UpdateTime(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid time id"})
+		return
+	}
+
+	var req models.ShowTimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if _, err := time.Parse("15:04", req.StartTime); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "start_time must be HH:MM"})
+		return
+	}
+
+	t, err := h.repo.UpdateTime(c.Request.Context(), id, req.StartTime)
+	if err != nil {
+		referenceError(c, "time", err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// @Summary Retire showtime slot
+// @Description Deactivate a showtime slot. Rejected with 409 while upcoming schedules still use it.
+// @Tags Admin Cinemas
+// @Produce json
+// @Param id path int true "Time ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/times/{id} [delete]
+func (h *CinemaHandler) RetireTime(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid time id"})
+		return
+	}
+
+	if err := h.repo.RetireTime(c.Request.Context(), id); err != nil {
+		referenceError(c, "time", err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "time retired successfully"})
+}
+
+// ===================== HELPERS =====================
+
+// referenceError memetakan error repository ke status HTTP
+func referenceError(c *gin.Context, entity string, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: entity + " not found"})
+	case errors.Is(err, repository.ErrReferenceInUse):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: entity + " " + err.Error()})
+	case errors.Is(err, repository.ErrAuditoriumRetired):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+}
+
+// seatCodes menggabungkan seat_codes eksplisit dan hasil generate rows x seats_per_row
+func seatCodes(req models.SeatInventoryRequest) []string {
+	seen := map[string]bool{}
+	codes := []string{}
+	add := func(code string) {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+
+	for _, code := range req.SeatCodes {
+		add(code)
+	}
+	for _, row := range req.Rows {
+		for n := 1; n <= req.SeatsPerRow; n++ {
+			add(fmt.Sprintf("%s%d", row, n))
+		}
+	}
+	return codes
+}
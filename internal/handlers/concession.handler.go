@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type ConcessionHandler struct {
+	repo *repository.ConcessionRepository
+}
+
+func NewConcessionHandler(repo *repository.ConcessionRepository) *ConcessionHandler {
+	return &ConcessionHandler{repo: repo}
+}
+
+// @Summary List concession items
+// @Description Full catalogue of food and drink items, including inactive ones.
+// @Tags Admin Concessions
+// @Produce json
+// @Success 200 {array} models.ConcessionItem
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/concessions [get]
+func (h *ConcessionHandler) ListItems(c *gin.Context) {
+	items, err := h.repo.ListItems(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Create concession item
+// @Description Price and stock are set per cinema with PUT /admin/cinemas/{id}/concessions/{itemId}.
+// @Tags Admin Concessions
+// @Accept json
+// @Produce json
+// @Param body body models.ConcessionItemRequest true "Item"
+// @Success 201 {object} models.ConcessionItem
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/concessions [post]
+func (h *ConcessionHandler) CreateItem(c *gin.Context) {
+	var req models.ConcessionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	item, err := h.repo.CreateItem(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, item)
+}
+
+// @Summary Update concession item
+// @Tags Admin Concessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Item ID"
+// @Param body body models.ConcessionItemUpdate true "Fields to update"
+// @Success 200 {object} models.ConcessionItem
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/concessions/{id} [patch]
+func (h *ConcessionHandler) UpdateItem(c *gin.Context) {
+	id, ok := concessionID(c, "id")
+	if !ok {
+		return
+	}
+	var req models.ConcessionItemUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	item, err := h.repo.UpdateItem(c.Request.Context(), id, req)
+	if err != nil {
+		concessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Deactivate concession item
+// @Description Items are never deleted because past orders refer to them.
+// @Tags Admin Concessions
+// @Produce json
+// @Param id path int true "Item ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/concessions/{id} [delete]
+func (h *ConcessionHandler) DeactivateItem(c *gin.Context) {
+	id, ok := concessionID(c, "id")
+	if !ok {
+		return
+	}
+	if err := h.repo.DeactivateItem(c.Request.Context(), id); err != nil {
+		concessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "concession item deactivated successfully"})
+}
+
+// @Summary Cinema concession menu (admin)
+// @Description All items configured for the cinema with price and stock, including unavailable ones.
+// @Tags Admin Concessions
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Success 200 {array} models.CinemaConcession
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas/{id}/concessions [get]
+func (h *ConcessionHandler) CinemaItems(c *gin.Context) {
+	cinemaID, ok := concessionID(c, "id")
+	if !ok {
+		return
+	}
+	menu, err := h.repo.CinemaMenu(c.Request.Context(), cinemaID, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, menu)
+}
+
+// @Summary Set cinema item price and stock
+// @Description Add an item to a cinema's menu or change its price, stock (null = unlimited) and availability.
+// @Tags Admin Concessions
+// @Accept json
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Param itemId path int true "Item ID"
+// @Param body body models.CinemaConcessionRequest true "Price and stock"
+// @Success 200 {object} models.CinemaConcession
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/cinemas/{id}/concessions/{itemId} [put]
+func (h *ConcessionHandler) SetCinemaItem(c *gin.Context) {
+	cinemaID, ok := concessionID(c, "id")
+	if !ok {
+		return
+	}
+	itemID, ok := concessionID(c, "itemId")
+	if !ok {
+		return
+	}
+	var req models.CinemaConcessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	item, err := h.repo.SetCinemaItem(c.Request.Context(), cinemaID, itemID, req)
+	if err != nil {
+		concessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Cinema concession menu
+// @Description Food and drink add-ons that can be ordered with tickets for a schedule at this cinema.
+// @Tags Concessions
+// @Produce json
+// @Param id path int true "Cinema ID"
+// @Success 200 {array} models.CinemaConcession
+// @Failure 500 {object} models.ErrorResponse
+// @Router /cinemas/{id}/concessions [get]
+func (h *ConcessionHandler) Menu(c *gin.Context) {
+	cinemaID, ok := concessionID(c, "id")
+	if !ok {
+		return
+	}
+	menu, err := h.repo.CinemaMenu(c.Request.Context(), cinemaID, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, menu)
+}
+
+// @Summary Order add-ons for pickup
+// @Description Add-ons of an order and their pickup status, for counter staff.
+// @Tags Staff
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.PickupOrder
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /staff/orders/{id}/items [get]
+func (h *ConcessionHandler) PickupOrder(c *gin.Context) {
+	orderID, ok := concessionID(c, "id")
+	if !ok {
+		return
+	}
+	order, err := h.repo.PickupOrder(c.Request.Context(), orderID)
+	if err != nil {
+		concessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+// @Summary Fulfil add-on pickup
+// @Description Mark add-ons of a paid order as handed over. Without item_ids every pending item is fulfilled.
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param body body models.PickupRequest false "Order item IDs"
+// @Success 200 {object} models.PickupOrder
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "nothing left to pick up"
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /staff/orders/{id}/pickup [post]
+func (h *ConcessionHandler) Pickup(c *gin.Context) {
+	orderID, ok := concessionID(c, "id")
+	if !ok {
+		return
+	}
+	var req models.PickupRequest
+	// body boleh kosong
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+	order, err := h.repo.Fulfill(c.Request.Context(), orderID, c.GetInt("userID"), req.ItemIDs)
+	if err != nil {
+		concessionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, order)
+}
+
+func concessionID(c *gin.Context, param string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid " + param})
+		return 0, false
+	}
+	return id, true
+}
+
+func concessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "concession item or cinema not found"})
+	case errors.Is(err, repository.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrNothingToPickup):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+}
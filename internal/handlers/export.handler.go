@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/export"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	repo *repository.ExportRepository
+}
+
+func NewExportHandler(repo *repository.ExportRepository) *ExportHandler {
+	return &ExportHandler{repo: repo}
+}
+
+// @Summary Export data
+// @Description Stream movies, schedules or orders as a file download.
+// @Description The date range applies to release_date (movies), schedule date (schedules) or order date (orders).
+// @Tags Admin Export
+// @Produce octet-stream
+// @Param dataset path string true "Dataset" Enums(movies, schedules, orders)
+// @Param format query string false "File format" Enums(csv, jsonl, xlsx) default(csv)
+// @Param from query string false "Start date (YYYY-MM-DD), inclusive"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive"
+// @Param cinema_id query int false "Only rows for this cinema"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/export/{dataset} [get]
+func (h *ExportHandler) Export(c *gin.Context) {
+	dataset := c.Param("dataset")
+	if !slices.Contains(repository.ExportDatasets(), dataset) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "dataset must be one of movies, schedules, orders"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	contentType := export.ContentType(format)
+	if contentType == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: export.ErrUnsupportedFormat.Error()})
+		return
+	}
+
+	filter, ok := exportFilter(c)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s.%s", dataset, time.Now().Format("20060102_150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	w, _ := export.NewWriter(c.Writer, format)
+	err := h.repo.Export(c.Request.Context(), dataset, filter, w)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		// jika belum ada byte yang terkirim, masih bisa membalas dengan error JSON
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+		log.Printf("export %s: %v", dataset, err)
+	}
+}
+
+// exportFilter membaca from, to dan cinema_id; menulis response 400 jika tidak valid
+func exportFilter(c *gin.Context) (models.ExportFilter, bool) {
+	var filter models.ExportFilter
+	for name, target := range map[string]**string{"from": &filter.From, "to": &filter.To} {
+		v := c.Query(name)
+		if v == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", v); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: name + " must be YYYY-MM-DD"})
+			return filter, false
+		}
+		*target = &v
+	}
+
+	if v := c.Query("cinema_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid cinema_id"})
+			return filter, false
+		}
+		filter.CinemaID = id
+	}
+	return filter, true
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/cristian-yw/Weekly10/internal/giftcard"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type GiftCardHandler struct {
+	repo *repository.GiftCardRepository
+}
+
+func NewGiftCardHandler(repo *repository.GiftCardRepository) *GiftCardHandler {
+	return &GiftCardHandler{repo: repo}
+}
+
+// @Summary Issue gift card
+// @Description Issue a new gift card. The generated code is only shown in full to admins.
+// @Tags Admin Gift Cards
+// @Accept json
+// @Produce json
+// @Param body body models.GiftCardIssueRequest true "Gift card"
+// @Success 201 {object} models.GiftCard
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/gift-cards [post]
+func (h *GiftCardHandler) Issue(c *gin.Context) {
+	var req models.GiftCardIssueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	card, err := h.repo.Issue(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, card)
+}
+
+// @Summary Look up gift card
+// @Description Gift card with its balance and full ledger.
+// @Tags Admin Gift Cards
+// @Produce json
+// @Param code path string true "Gift card code, with or without dashes"
+// @Success 200 {object} models.GiftCard
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/gift-cards/{code} [get]
+func (h *GiftCardHandler) Lookup(c *gin.Context) {
+	card, err := h.repo.Lookup(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		giftCardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, card)
+}
+
+// @Summary Void gift card
+// @Description Deactivate a gift card; the remaining balance is written off with a void ledger entry.
+// @Tags Admin Gift Cards
+// @Accept json
+// @Produce json
+// @Param code path string true "Gift card code"
+// @Param body body models.GiftCardVoidRequest true "Reason"
+// @Success 200 {object} models.GiftCard
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/gift-cards/{code}/void [post]
+func (h *GiftCardHandler) Void(c *gin.Context) {
+	var req models.GiftCardVoidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	card, err := h.repo.Void(c.Request.Context(), c.Param("code"), c.GetInt("userID"), req.Reason)
+	if err != nil {
+		giftCardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, card)
+}
+
+// @Summary Gift card balance
+// @Description Remaining balance of a gift card before using it at checkout.
+// @Tags Gift Cards
+// @Produce json
+// @Param code path string true "Gift card code"
+// @Success 200 {object} models.GiftCardBalance
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /gift-cards/{code} [get]
+func (h *GiftCardHandler) Balance(c *gin.Context) {
+	balance, err := h.repo.Balance(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		giftCardError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+func giftCardError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, giftcard.ErrNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrGiftCardVoided):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/booking"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultGroupDeadline = 2 * time.Hour
+	groupSweepInterval   = 30 * time.Second
+)
+
+// groupDeadline membaca GROUP_BOOKING_DEADLINE (mis. "2h"), dipakai jika deadline_minutes kosong
+func groupDeadline() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("GROUP_BOOKING_DEADLINE"))
+	if err != nil || d <= 0 {
+		return defaultGroupDeadline
+	}
+	return d
+}
+
+// @Summary Create group booking
+// @Description Hold 2-10 seats for a group and get an invite link. Every member (the organiser too) claims one seat
+// @Description and pays for it with their own order. Seats that are not paid by the deadline (default
+// @Description GROUP_BOOKING_DEADLINE, never later than the show) are released.
+// @Tags Group Bookings
+// @Accept json
+// @Produce json
+// @Param body body models.GroupBookingRequest true "Seats"
+// @Param X-Queue-Token header string false "Admission token, required while the waiting room is enabled"
+// @Success 201 {object} models.GroupBooking
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "waiting room active"
+// @Failure 404 {object} map[string]string "schedule not found"
+// @Failure 409 {object} map[string]string "seat held or already booked"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/groups [post]
+func (h *OrderHandler) CreateGroup(c *gin.Context) {
+	var req models.GroupBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID := c.GetInt("userID")
+	ctx := c.Request.Context()
+
+	if _, ok := h.admission(c, req.ScheduleID); !ok {
+		return
+	}
+	if err := h.holds.CheckAvailable(ctx, req.ScheduleID, userID, req.Seats); err != nil {
+		groupError(c, err)
+		return
+	}
+
+	deadline := time.Now().Add(groupDeadline())
+	if req.DeadlineMinutes > 0 {
+		deadline = time.Now().Add(time.Duration(req.DeadlineMinutes) * time.Minute)
+	}
+	group, err := h.repo.CreateGroup(ctx, userID, req, deadline)
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+
+	// hold pribadi organiser diganti hold milik group sampai deadline
+	if _, err := h.holds.Release(ctx, req.ScheduleID, userID, req.Seats); err != nil {
+		log.Println("group booking release:", err)
+	}
+	if err := h.holds.HoldGroup(ctx, req.ScheduleID, group.ID, req.Seats, group.Deadline); err != nil {
+		if _, cancelErr := h.repo.CancelGroup(ctx, group.Token, userID); cancelErr != nil {
+			log.Println("group booking cancel:", cancelErr)
+		}
+		groupError(c, err)
+		return
+	}
+
+	group.InviteURL = requestBaseURL(c) + "/orders/groups/" + group.Token
+	c.JSON(http.StatusCreated, group)
+}
+
+// @Summary Get group booking
+// @Description Seats of a group booking and whether they are open, claimed, paid or released.
+// @Tags Group Bookings
+// @Produce json
+// @Param token path string true "Invite token"
+// @Success 200 {object} models.GroupBooking
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/groups/{token} [get]
+func (h *OrderHandler) GetGroup(c *gin.Context) {
+	group, err := h.repo.GetGroup(c.Request.Context(), c.Param("token"), c.GetInt("userID"))
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+	group.InviteURL = requestBaseURL(c) + "/orders/groups/" + group.Token
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Claim group seat
+// @Description Claim one open seat of the group. Each member can claim one seat.
+// @Tags Group Bookings
+// @Accept json
+// @Produce json
+// @Param token path string true "Invite token"
+// @Param body body models.GroupClaimRequest true "Seat"
+// @Success 200 {object} models.GroupBooking
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "seat claimed, already claimed a seat, or group closed"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/groups/{token}/claim [post]
+func (h *OrderHandler) ClaimGroupSeat(c *gin.Context) {
+	var req models.GroupClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	group, err := h.repo.ClaimSeat(c.Request.Context(), c.Param("token"), c.GetInt("userID"), req.Seat)
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Unclaim group seat
+// @Description Give back the claimed seat so another member can take it. Paid seats cannot be unclaimed.
+// @Tags Group Bookings
+// @Produce json
+// @Param token path string true "Invite token"
+// @Success 200 {object} models.GroupBooking
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "no unpaid claim"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/groups/{token}/claim [delete]
+func (h *OrderHandler) UnclaimGroupSeat(c *gin.Context) {
+	group, err := h.repo.UnclaimSeat(c.Request.Context(), c.Param("token"), c.GetInt("userID"))
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, group)
+}
+
+// @Summary Quote group seat
+// @Description Price of the claimed seat with the given ticket type, add-ons, promo code, points and gift card.
+// @Tags Group Bookings
+// @Accept json
+// @Produce json
+// @Param token path string true "Invite token"
+// @Param body body models.GroupPaymentRequest true "Payment options"
+// @Success 200 {object} models.Quote
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "no claimed seat"
+// @Failure 422 {object} map[string]string "ticket type, promo code, gift card, add-on or points cannot be used"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/groups/{token}/quote [post]
+func (h *OrderHandler) QuoteGroupSeat(c *gin.Context) {
+	req, ok := h.groupOrderRequest(c)
+	if !ok {
+		return
+	}
+	quote, err := h.repo.Quote(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+// @Summary Pay group seat
+// @Description Create the member's own order for the claimed seat. The order is linked to the group booking;
+// @Description the group is completed once every seat is paid.
+// @Tags Group Bookings
+// @Accept json
+// @Produce json
+// @Param token path string true "Invite token"
+// @Param body body models.GroupPaymentRequest true "Payment options"
+// @Success 201 {object} models.Order
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "no claimed seat or group closed"
+// @Failure 422 {object} map[string]string "ticket type, promo code, gift card, add-on or points cannot be used"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/groups/{token}/pay [post]
+func (h *OrderHandler) PayGroupSeat(c *gin.Context) {
+	req, ok := h.groupOrderRequest(c)
+	if !ok {
+		return
+	}
+	order, err := h.repo.CreateOrder(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+	h.holds.BookedGroup(c.Request.Context(), req.ScheduleID, req.GroupBookingID, req.Seats)
+	c.JSON(http.StatusCreated, order)
+}
+
+// @Summary Cancel group booking
+// @Description Organiser only. Unpaid seats are released; orders already paid by members are kept.
+// @Tags Group Bookings
+// @Produce json
+// @Param token path string true "Invite token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string "not the organiser"
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string "group closed"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/groups/{token} [delete]
+func (h *OrderHandler) CancelGroup(c *gin.Context) {
+	group, err := h.repo.CancelGroup(c.Request.Context(), c.Param("token"), c.GetInt("userID"))
+	if err != nil {
+		groupError(c, err)
+		return
+	}
+	ReleaseGroupSeats(c.Request.Context(), h.holds, *group)
+	c.JSON(http.StatusOK, gin.H{"message": "group booking cancelled", "released": group.Seats})
+}
+
+// groupOrderRequest membentuk QuoteRequest untuk kursi yang diklaim user
+func (h *OrderHandler) groupOrderRequest(c *gin.Context) (models.QuoteRequest, bool) {
+	var body models.GroupPaymentRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return models.QuoteRequest{}, false
+	}
+	groupID, scheduleID, seat, err := h.repo.ClaimedSeat(c.Request.Context(), c.Param("token"), c.GetInt("userID"))
+	if err != nil {
+		groupError(c, err)
+		return models.QuoteRequest{}, false
+	}
+
+	req := models.QuoteRequest{
+		ScheduleID:     scheduleID,
+		Seats:          []string{seat},
+		Items:          body.Items,
+		PromoCode:      body.PromoCode,
+		RedeemPoints:   body.RedeemPoints,
+		GiftCardCode:   body.GiftCardCode,
+		PaymentMethod:  body.PaymentMethod,
+		GroupBookingID: groupID,
+	}
+	if body.TicketType != "" {
+		req.TicketTypes = map[string]string{seat: body.TicketType}
+	}
+	return req, true
+}
+
+// ReleaseGroupSeats melepas hold kursi group yang belum dibayar (setelah dibatalkan / lewat deadline)
+func ReleaseGroupSeats(ctx context.Context, holds *booking.Holds, group repository.ReleasedGroup) {
+	if len(group.Seats) == 0 {
+		return
+	}
+	if _, err := holds.ReleaseGroup(ctx, group.ScheduleID, group.ID, group.Seats); err != nil {
+		log.Println("group booking release:", err)
+	}
+}
+
+// RunGroupDeadlines menutup group yang lewat deadline dan melepas kursi yang belum dibayar sampai ctx selesai.
+// Status diubah dengan satu UPDATE, jadi tiap group hanya ditutup oleh satu replica.
+func (h *OrderHandler) RunGroupDeadlines(ctx context.Context) {
+	ticker := time.NewTicker(groupSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			groups, err := h.repo.CloseExpiredGroups(ctx)
+			if err != nil {
+				log.Println("group booking deadline:", err)
+				continue
+			}
+			for _, group := range groups {
+				ReleaseGroupSeats(ctx, h.holds, group)
+			}
+		}
+	}
+}
+
+func groupError(c *gin.Context, err error) {
+	var held *booking.SeatHeldError
+	switch {
+	case errors.As(err, &held):
+		c.JSON(http.StatusConflict, gin.H{"error": held.Error(), "seat": held.Seat})
+	case errors.Is(err, repository.ErrGroupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotOrganiser):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrGroupClosed), errors.Is(err, repository.ErrShowStarted),
+		errors.Is(err, repository.ErrSeatBooked), errors.Is(err, repository.ErrSeatNotInGroup),
+		errors.Is(err, repository.ErrSeatClaimed), errors.Is(err, repository.ErrAlreadyClaimed),
+		errors.Is(err, repository.ErrNoClaim):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		orderError(c, err)
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/cristian-yw/Weekly10/internal/media"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// mediaError memetakan error validasi upload ke status HTTP
+func mediaError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, media.ErrTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+	case errors.Is(err, media.ErrUnsupportedType):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// deleteReplacedImage menghapus gambar lama dari storage setelah diganti,
+// kecuali masih dipakai baris lain. Gagal hapus hanya di-log.
+func deleteReplacedImage(ctx context.Context, store *media.Store, inUse func(context.Context, string) (bool, error), oldRef, newRef string) {
+	if oldRef == newRef || !media.IsKey(oldRef) {
+		return
+	}
+	used, err := inUse(ctx, oldRef)
+	if err == nil && !used {
+		err = store.Delete(ctx, oldRef)
+	}
+	if err != nil {
+		log.Printf("delete replaced image %s: %v", oldRef, err)
+	}
+}
+
+// requestBaseURL: scheme://host dari request, untuk URL absolut gambar lokal
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// setMovieImageURLs mengisi poster_url dan backdrop_url
+func setMovieImageURLs(c *gin.Context, store *media.Store, movies []models.TMDBMovie) {
+	base := requestBaseURL(c)
+	for i := range movies {
+		movies[i].PosterURL = store.PosterURL(c.Request.Context(), base, movies[i].PosterPath)
+		movies[i].BackdropURL = store.BackdropURL(c.Request.Context(), base, movies[i].BackdropPath)
+	}
+}
@@ -5,17 +5,19 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/models"
 	"github.com/cristian-yw/Weekly10/internal/repository"
 	"github.com/gin-gonic/gin"
 )
 
 type MovieHandler struct {
-	mr *repository.MovieRepository
+	mr    *repository.MovieRepository
+	media *media.Store
 }
 
-func NewMovieHandler(mr *repository.MovieRepository) *MovieHandler {
-	return &MovieHandler{mr: mr}
+func NewMovieHandler(mr *repository.MovieRepository, media *media.Store) *MovieHandler {
+	return &MovieHandler{mr: mr, media: media}
 }
 
 // @Summary Get Upcoming Movies
@@ -35,6 +37,7 @@ func (h *MovieHandler) GetUpcomingMovies(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
 	}
+	setMovieImageURLs(c, h.media, movies)
 	c.JSON(http.StatusOK, movies)
 }
 
@@ -55,6 +58,7 @@ func (h *MovieHandler) GetPopularMovies(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
 	}
+	setMovieImageURLs(c, h.media, movies)
 	c.JSON(http.StatusOK, movies)
 }
 
@@ -88,6 +92,7 @@ func (h *MovieHandler) GetMoviesWithFilter(c *gin.Context) {
 		return
 	}
 
+	setMovieImageURLs(c, h.media, movies)
 	totalPages := int(math.Ceil(float64(totalRecords) / float64(limit)))
 
 	c.JSON(http.StatusOK, gin.H{
@@ -109,5 +114,6 @@ func (h *MovieHandler) GetAllMovies(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "failed to fetch movies"})
 		return
 	}
+	setMovieImageURLs(c, h.media, movies)
 	c.JSON(http.StatusOK, movies)
 }
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const maxNotificationList = 200
+
+type NotificationHandler struct {
+	repo *repository.NotificationRepository
+}
+
+func NewNotificationHandler(repo *repository.NotificationRepository) *NotificationHandler {
+	return &NotificationHandler{repo: repo}
+}
+
+// @Summary List notifications
+// @Description Outbox of user notifications (order confirmation, password changed, showtime reminder) with their delivery status,
+// @Description newest first. pending = waiting for (another) delivery attempt, failed = gave up after NOTIFY_MAX_ATTEMPTS.
+// @Tags Admin Notifications
+// @Produce json
+// @Param status query string false "pending, sent or failed"
+// @Param user_id query int false "User ID"
+// @Param limit query int false "Max rows (default 50, max 200)"
+// @Success 200 {array} models.Notification
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	status := c.Query("status")
+	if status != "" && status != "pending" && status != "sent" && status != "failed" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "status must be pending, sent or failed"})
+		return
+	}
+	userID, _ := strconv.Atoi(c.Query("user_id"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > maxNotificationList {
+		limit = maxNotificationList
+	}
+
+	list, err := h.repo.List(c.Request.Context(), status, userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// @Summary Retry notification
+// @Description Queue a failed notification for delivery again.
+// @Tags Admin Notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} models.Notification
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "not found or not failed"
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/notifications/{id}/retry [post]
+func (h *NotificationHandler) Retry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid notification id"})
+		return
+	}
+	n, err := h.repo.Retry(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotificationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, n)
+}
@@ -1,19 +1,34 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/cristian-yw/Weekly10/internal/booking"
+	"github.com/cristian-yw/Weekly10/internal/giftcard"
+	"github.com/cristian-yw/Weekly10/internal/media"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/promo"
 	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/cristian-yw/Weekly10/internal/seating"
 	"github.com/gin-gonic/gin"
 )
 
 type OrderHandler struct {
-	repo *repository.OrderRepository
+	repo  *repository.OrderRepository
+	media *media.Store
+	holds *booking.Holds
+	hub   *booking.Hub
+	queue *booking.WaitRoom
 }
 
-func NewOrderHandler(repo *repository.OrderRepository) *OrderHandler {
-	return &OrderHandler{repo: repo}
+func NewOrderHandler(repo *repository.OrderRepository, media *media.Store, holds *booking.Holds, hub *booking.Hub, queue *booking.WaitRoom) *OrderHandler {
+	return &OrderHandler{repo: repo, media: media, holds: holds, hub: hub, queue: queue}
 }
 
 // @Summary     Get Movie Schedules with Filters
@@ -64,16 +79,21 @@ func (h *OrderHandler) GetSchedule(c *gin.Context) {
 }
 
 // @Summary Get Available Seats
-// @Description Get available seats for a specific schedule
+// @Description Get available seats for a specific schedule. The list holds the taken seats (paid or pending orders).
 // @Tags Orders
 // @Produce json
 // @Param scheduleId path int true "Schedule ID"
+// @Param X-Queue-Token header string false "Admission token, required while the waiting room is enabled"
 // @Success 200 {array} models.Seat
+// @Failure 403 {object} map[string]string "waiting room active"
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /orders/seats/{scheduleId} [get]
 func (h *OrderHandler) GetAvailableSeats(c *gin.Context) {
 	scheduleID, _ := strconv.Atoi(c.Param("scheduleId"))
+	if _, ok := h.admission(c, scheduleID); !ok {
+		return
+	}
 	seats, err := h.repo.GetAvailableSeats(c, scheduleID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -82,6 +102,105 @@ func (h *OrderHandler) GetAvailableSeats(c *gin.Context) {
 	c.JSON(http.StatusOK, seats)
 }
 
+// @Summary Suggest best available seats
+// @Description Best contiguous block of free seats in one row, scored by distance to the ideal viewing row
+// @Description (about two thirds back) and to the centre of the row. Wheelchair spaces are only suggested when
+// @Description wheelchair > 0, and the block then contains exactly that many. couple=true suggests whole couple
+// @Description seat pairs only. Seats are not held; hold them with POST /orders/seats/{scheduleId}/hold.
+// @Tags Orders
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Param count query int true "Number of seats (1-10)"
+// @Param wheelchair query int false "Wheelchair spaces needed in the block"
+// @Param couple query bool false "Couple seats only (count must be even)"
+// @Param X-Queue-Token header string false "Admission token, required while the waiting room is enabled"
+// @Success 200 {object} models.SeatSuggestion
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "waiting room active"
+// @Failure 404 {object} map[string]string "schedule not found"
+// @Failure 409 {object} map[string]string "no matching block of free seats"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/seats/{scheduleId}/suggest [post]
+func (h *OrderHandler) SuggestSeats(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule id"})
+		return
+	}
+	req := seating.Request{}
+	if req.Count, err = strconv.Atoi(c.Query("count")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid count"})
+		return
+	}
+	if v := c.Query("wheelchair"); v != "" {
+		if req.Wheelchair, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid wheelchair"})
+			return
+		}
+	}
+	if v := c.Query("couple"); v != "" {
+		if req.Couple, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid couple"})
+			return
+		}
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, ok := h.admission(c, scheduleID); !ok {
+		return
+	}
+
+	seats, err := h.freeSeats(c.Request.Context(), scheduleID, c.GetInt("userID"))
+	if err != nil {
+		orderError(c, err)
+		return
+	}
+
+	suggestion, err := seating.Suggest(seats, req)
+	if errors.Is(err, seating.ErrNoBlock) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	suggestion.ScheduleID = scheduleID
+	c.JSON(http.StatusOK, suggestion)
+}
+
+// @Summary Ticket prices
+// @Description Price of every active ticket type (adult, child, student, senior, ...) for a schedule.
+// @Tags Orders
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Success 200 {array} models.TicketPrice
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/tickets/{scheduleId} [get]
+func (h *OrderHandler) GetTicketPrices(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+	prices, err := h.repo.TicketPrices(c.Request.Context(), scheduleID)
+	if errors.Is(err, repository.ErrScheduleNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, prices)
+}
+
 // @Summary Get Movie Detail
 // @Description Get detailed information of a specific movie
 // @Tags Orders
@@ -108,38 +227,430 @@ func (h *OrderHandler) GetMovieDetail(c *gin.Context) {
 		})
 		return
 	}
+	base := requestBaseURL(c)
+	movie.PosterURL = h.media.PosterURL(c.Request.Context(), base, movie.PosterPath)
+	movie.BackdropURL = h.media.BackdropURL(c.Request.Context(), base, movie.BackdropPath)
 
 	c.JSON(http.StatusOK, movie)
 }
 
 // @Summary Create Order
-// @Description Create a new order including seats selection
+// @Description Create a new order including seats selection and optional food / drink add-ons (items), picked up
+// @Description at the cinema counter. ticket_types maps a seat code to its ticket type (see GET /orders/tickets/{scheduleId});
+// @Description seats without a type are sold as adult. The total is calculated by the server
+// @Description (see POST /orders/quote); an optional promo code, loyalty points and gift card are applied and recorded
+// @Description atomically. Gift cards listed in gift_cards are bought with the order and returned in the response.
 // @Tags Orders
 // @Accept json
 // @Produce json
-// @Param request body models.Order true "Order Request"
+// @Param request body models.QuoteRequest true "Order Request"
+// @Param X-Queue-Token header string false "Admission token, required while the waiting room is enabled"
 // @Success 201 {object} models.Order
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "waiting room active"
+// @Failure 404 {object} map[string]string "schedule not found"
+// @Failure 409 {object} map[string]string "seat held by another user or already booked"
+// @Failure 422 {object} map[string]string "unknown seat or ticket type, promo code or gift card cannot be used, add-on unavailable or out of stock, or not enough points"
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /orders/ [post]
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
-	var req struct {
-		UserID     int      `json:"user_id"`
-		ScheduleID int      `json:"schedule_id"`
-		TotalPrice int      `json:"total_price"`
-		Seats      []string `json:"seats"`
-	}
+	var req models.QuoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	order, err := h.repo.CreateOrder(c, req.UserID, req.ScheduleID, req.TotalPrice, req.Seats)
+	// user selalu diambil dari token, bukan dari body
+	userID := c.GetInt("userID")
+
+	room, ok := h.admission(c, req.ScheduleID)
+	if !ok {
+		return
+	}
+
+	err := h.holds.CheckAvailable(c.Request.Context(), req.ScheduleID, userID, req.Seats)
+	var held *booking.SeatHeldError
+	if errors.As(err, &held) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "seat": held.Seat})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	order, err := h.repo.CreateOrder(c, userID, req)
+	if err != nil {
+		orderError(c, err)
+		return
+	}
+	h.holds.Booked(c.Request.Context(), req.ScheduleID, userID, req.Seats)
+	if room != nil {
+		// slot dilepas supaya user berikutnya di antrian bisa masuk
+		if err := h.queue.Leave(c.Request.Context(), room, userID); err != nil {
+			log.Println("waiting room leave:", err)
+		}
+	}
+
 	c.JSON(http.StatusCreated, order)
 }
+
+// @Summary Hold seats
+// @Description Temporarily hold seats while the user is checking out. Holds expire after SEAT_HOLD_TTL (default 10m)
+// @Description and are pushed to everyone watching GET /orders/seats/{scheduleId}/stream.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Param body body models.SeatHoldRequest true "Seats"
+// @Param X-Queue-Token header string false "Admission token, required while the waiting room is enabled"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string "waiting room active"
+// @Failure 404 {object} map[string]string "schedule not found"
+// @Failure 409 {object} map[string]string "seat held by another user or already booked"
+// @Failure 422 {object} map[string]string "unknown seat"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/seats/{scheduleId}/hold [post]
+func (h *OrderHandler) HoldSeats(c *gin.Context) {
+	scheduleID, req, ok := seatHoldRequest(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.admission(c, scheduleID); !ok {
+		return
+	}
+	if err := h.repo.CheckSeats(c.Request.Context(), scheduleID, req.Seats); err != nil {
+		orderError(c, err)
+		return
+	}
+
+	expiresAt, err := h.holds.Hold(c.Request.Context(), scheduleID, c.GetInt("userID"), req.Seats)
+	var held *booking.SeatHeldError
+	if errors.As(err, &held) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "seat": held.Seat})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"seats": req.Seats, "expires_at": expiresAt})
+}
+
+// @Summary Release held seats
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Param body body models.SeatHoldRequest true "Seats"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/seats/{scheduleId}/hold [delete]
+func (h *OrderHandler) ReleaseSeats(c *gin.Context) {
+	scheduleID, req, ok := seatHoldRequest(c)
+	if !ok {
+		return
+	}
+
+	released, err := h.holds.Release(c.Request.Context(), scheduleID, c.GetInt("userID"), req.Seats)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"released": released})
+}
+
+// @Summary Seat stream token
+// @Description Short-lived token for GET /orders/seats/{scheduleId}/stream. EventSource cannot send the
+// @Description Authorization header, so the token goes in the query string. It is only checked when the stream opens.
+// @Tags Orders
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/seats/{scheduleId}/stream-token [post]
+func (h *OrderHandler) SeatStreamToken(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduleId"})
+		return
+	}
+
+	token, expiresAt, err := middleware.GenerateSeatStreamToken(c.GetInt("userID"), scheduleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"token": token, "expires_at": expiresAt})
+}
+
+// @Summary Stream seat availability
+// @Description Server-Sent Events stream of a schedule's seats. The first "snapshot" event lists booked and held seats,
+// @Description followed by "held", "released" and "booked" events as they happen on any API replica.
+// @Description Authenticated with ?token= from POST /orders/seats/{scheduleId}/stream-token instead of the Authorization header.
+// @Tags Orders
+// @Produce text/event-stream
+// @Param scheduleId path int true "Schedule ID"
+// @Param token query string true "Seat stream token"
+// @Success 200 {object} models.SeatEvent
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/seats/{scheduleId}/stream [get]
+func (h *OrderHandler) StreamSeats(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduleId"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	// subscribe dulu sebelum snapshot supaya tidak ada event yang terlewat
+	events, cancel := h.hub.Subscribe(scheduleID)
+	defer cancel()
+
+	booked, err := h.repo.GetAvailableSeats(ctx, scheduleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	held, err := h.holds.Held(ctx, scheduleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	snapshot := models.SeatEvent{ScheduleID: scheduleID, Type: "snapshot", Seats: []string{}, Held: held, At: time.Now()}
+	for _, s := range booked {
+		snapshot.Seats = append(snapshot.Seats, s.SeatCode)
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // nginx
+	c.SSEvent(snapshot.Type, snapshot)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(25 * time.Second)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return // terlalu lambat, client reconnect dan menerima snapshot baru
+			}
+			c.SSEvent(ev.Type, ev)
+		case <-heartbeat.C:
+			c.Writer.WriteString(": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
+
+func seatHoldRequest(c *gin.Context) (int, models.SeatHoldRequest, bool) {
+	var req models.SeatHoldRequest
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduleId"})
+		return 0, req, false
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, req, false
+	}
+	return scheduleID, req, true
+}
+
+// @Summary Join waiting room
+// @Description Join the waiting room of a schedule (or of its movie). If a slot is free the response carries
+// @Description an admission token to send as X-Queue-Token to the booking endpoints, otherwise the queue position
+// @Description and estimated wait. When no waiting room is enabled the response has enabled=false and admitted=true.
+// @Tags Orders
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Success 200 {object} models.QueueStatus
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/queue/{scheduleId} [post]
+func (h *OrderHandler) JoinQueue(c *gin.Context) {
+	h.queueStatus(c, true)
+}
+
+// @Summary Waiting room status
+// @Description Current queue position. Poll this every few seconds; users that stop polling for a minute
+// @Description lose their place. Returns the admission token once admitted.
+// @Tags Orders
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Success 200 {object} models.QueueStatus
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "not in queue"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/queue/{scheduleId} [get]
+func (h *OrderHandler) QueueStatus(c *gin.Context) {
+	h.queueStatus(c, false)
+}
+
+// @Summary Leave waiting room
+// @Description Leave the queue or give back the admission slot.
+// @Tags Orders
+// @Produce json
+// @Param scheduleId path int true "Schedule ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/queue/{scheduleId} [delete]
+func (h *OrderHandler) LeaveQueue(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduleId"})
+		return
+	}
+	room, err := h.queue.Resolve(c.Request.Context(), scheduleID, h.repo.GetScheduleMovieID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if room != nil {
+		if err := h.queue.Leave(c.Request.Context(), room, c.GetInt("userID")); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "left waiting room"})
+}
+
+func (h *OrderHandler) queueStatus(c *gin.Context, join bool) {
+	scheduleID, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid scheduleId"})
+		return
+	}
+	ctx := c.Request.Context()
+	room, err := h.queue.Resolve(ctx, scheduleID, h.repo.GetScheduleMovieID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if room == nil {
+		c.JSON(http.StatusOK, models.QueueStatus{Scope: "schedule", ScopeID: scheduleID, Admitted: true})
+		return
+	}
+
+	var status *models.QueueStatus
+	if join {
+		status, err = h.queue.Join(ctx, room, c.GetInt("userID"))
+	} else {
+		status, err = h.queue.Status(ctx, room, c.GetInt("userID"))
+	}
+	if errors.Is(err, booking.ErrNotInQueue) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// admission memeriksa X-Queue-Token jika jadwal (atau movienya) memakai waiting room.
+// Mengembalikan waiting room yang berlaku (nil jika tidak ada) dan false jika request sudah dijawab.
+func (h *OrderHandler) admission(c *gin.Context, scheduleID int) (*models.WaitRoomConfig, bool) {
+	ctx := c.Request.Context()
+	room, err := h.queue.Resolve(ctx, scheduleID, h.repo.GetScheduleMovieID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if room == nil {
+		return nil, true
+	}
+
+	ok, err := h.queue.Admitted(ctx, room, c.GetInt("userID"), c.GetHeader("X-Queue-Token"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "waiting room is active, join the queue first",
+			"queue": fmt.Sprintf("/orders/queue/%d", scheduleID),
+		})
+		return nil, false
+	}
+	return room, true
+}
+
+// @Summary Quote order
+// @Description Preview the total of an order, including add-ons, the discount of a promo code, redeemed loyalty points,
+// @Description the part paid by a gift card and the amount left to pay, without booking anything.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Param request body models.QuoteRequest true "Schedule, seats and optional promo code"
+// @Success 200 {object} models.Quote
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "schedule not found"
+// @Failure 409 {object} map[string]string "seat already booked"
+// @Failure 422 {object} map[string]string "unknown seat or ticket type, promo code or gift card cannot be used, add-on unavailable or out of stock, or not enough points"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /orders/quote [post]
+func (h *OrderHandler) Quote(c *gin.Context) {
+	var req models.QuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quote, err := h.repo.Quote(c.Request.Context(), c.GetInt("userID"), req)
+	if err != nil {
+		orderError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, quote)
+}
+
+// orderError memetakan error quote / create order ke status HTTP
+func orderError(c *gin.Context, err error) {
+	var promoErr *promo.Error
+	var giftCardErr *giftcard.Error
+	switch {
+	case errors.As(err, &promoErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": promoErr.Message, "reason": promoErr.Reason})
+	case errors.As(err, &giftCardErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": giftCardErr.Message, "reason": giftCardErr.Reason})
+	case errors.Is(err, repository.ErrUnknownTicketType):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "reason": "ticket_type"})
+	case errors.Is(err, repository.ErrItemUnavailable):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "reason": "item_unavailable"})
+	case errors.Is(err, repository.ErrOutOfStock):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "reason": "out_of_stock"})
+	case errors.Is(err, repository.ErrUnknownSeat):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "reason": "seat"})
+	case errors.Is(err, repository.ErrDuplicateSeat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrSeatBooked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrScheduleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrInsufficientPoints):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "reason": "points"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/cristian-yw/Weekly10/internal/booking"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+const maxWebhookBytes = 64 << 10
+
+type PaymentHandler struct {
+	repo   *repository.PaymentRepository
+	hub    *booking.Hub
+	secret []byte
+}
+
+// NewPaymentHandler membaca PAYMENT_WEBHOOK_SECRET untuk verifikasi signature
+func NewPaymentHandler(repo *repository.PaymentRepository, hub *booking.Hub) *PaymentHandler {
+	return &PaymentHandler{repo: repo, hub: hub, secret: []byte(os.Getenv("PAYMENT_WEBHOOK_SECRET"))}
+}
+
+// @Summary Payment webhook
+// @Description Called by the payment gateway when an order is paid, refunded or failed. The body must be signed:
+// @Description X-Signature = hex(HMAC-SHA256(body, PAYMENT_WEBHOOK_SECRET)). Deliveries are idempotent per event_id,
+// @Description and loyalty points are awarded (or reversed on refund) exactly once per order.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param X-Signature header string true "HMAC-SHA256 signature of the body"
+// @Param body body models.PaymentWebhook true "Payment event"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /payments/webhook [post]
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	if len(h.secret) == 0 {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "payment webhook secret is not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if !h.validSignature(body, c.GetHeader("X-Signature")) {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "invalid signature"})
+		return
+	}
+
+	var ev models.PaymentWebhook
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err := c.ShouldBindJSON(&ev); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	duplicate, freed, err := h.repo.HandleEvent(c.Request.Context(), ev)
+	switch {
+	case errors.Is(err, repository.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+	case errors.Is(err, repository.ErrAmountMismatch):
+		c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{Error: err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	default:
+		if freed != nil && len(freed.Seats) > 0 {
+			// kursi order yang di-refund / gagal kembali dijual (dan ditawarkan ke waitlist)
+			h.hub.Publish(c.Request.Context(), models.SeatEvent{ScheduleID: freed.ScheduleID, Type: "released", Seats: freed.Seats})
+		}
+		c.JSON(http.StatusOK, gin.H{"event_id": ev.EventID, "duplicate": duplicate})
+	}
+}
+
+func (h *PaymentHandler) validSignature(body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, h.secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
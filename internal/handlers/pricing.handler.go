@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/pricing"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type PricingHandler struct {
+	repo *repository.PricingRepository
+}
+
+func NewPricingHandler(repo *repository.PricingRepository) *PricingHandler {
+	return &PricingHandler{repo: repo}
+}
+
+// @Summary List pricing rules
+// @Description All pricing rules in evaluation order (priority, then id), including inactive ones.
+// @Tags Admin Pricing
+// @Produce json
+// @Success 200 {array} models.PricingRule
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/pricing-rules [get]
+func (h *PricingHandler) ListRules(c *gin.Context) {
+	rules, err := h.repo.ListRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Get pricing rule
+// @Tags Admin Pricing
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.PricingRule
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/pricing-rules/{id} [get]
+func (h *PricingHandler) GetRule(c *gin.Context) {
+	id, ok := pricingRuleID(c)
+	if !ok {
+		return
+	}
+	rule, err := h.repo.GetRule(c.Request.Context(), id)
+	if err != nil {
+		pricingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Create pricing rule
+// @Description Rules adjust the schedule price in priority order (lowest first, ties by id). Conditions left empty
+// @Description do not restrict: days_of_week (0 = Sunday), start_from/start_until (HH:MM, may wrap past midnight),
+// @Description premiere_days (shows within N days of the release date), min/max_occupancy (% of seats sold),
+// @Description movie_ids and cinema_ids. adjustment_type percent (+20 = 20% surcharge, -15 = 15% off), fixed
+// @Description (amount added, may be negative) or set (new price). stop_processing skips the rules after it.
+// @Tags Admin Pricing
+// @Accept json
+// @Produce json
+// @Param body body models.PricingRuleRequest true "Pricing rule"
+// @Success 201 {object} models.PricingRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/pricing-rules [post]
+func (h *PricingHandler) CreateRule(c *gin.Context) {
+	var req models.PricingRuleRequest
+	if !bindPricingRule(c, &req) {
+		return
+	}
+	rule, err := h.repo.CreateRule(c.Request.Context(), req)
+	if err != nil {
+		pricingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary Update pricing rule
+// @Description Replaces all settings of the rule. Orders keep the price breakdown they were created with.
+// @Tags Admin Pricing
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param body body models.PricingRuleRequest true "Pricing rule"
+// @Success 200 {object} models.PricingRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/pricing-rules/{id} [put]
+func (h *PricingHandler) UpdateRule(c *gin.Context) {
+	id, ok := pricingRuleID(c)
+	if !ok {
+		return
+	}
+	var req models.PricingRuleRequest
+	if !bindPricingRule(c, &req) {
+		return
+	}
+	rule, err := h.repo.UpdateRule(c.Request.Context(), id, req)
+	if err != nil {
+		pricingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Deactivate pricing rule
+// @Description Rules are kept so the price breakdown of past orders can still be traced.
+// @Tags Admin Pricing
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/pricing-rules/{id} [delete]
+func (h *PricingHandler) DeactivateRule(c *gin.Context) {
+	id, ok := pricingRuleID(c)
+	if !ok {
+		return
+	}
+	if err := h.repo.DeactivateRule(c.Request.Context(), id); err != nil {
+		pricingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "pricing rule deactivated successfully"})
+}
+
+// @Summary Preview schedule price
+// @Description Evaluate every active rule against a schedule and explain, rule by rule, whether it applied
+// @Description (with the price before and after) or why not. Nothing is saved.
+// @Tags Admin Pricing
+// @Produce json
+// @Param schedule_id query int true "Schedule ID"
+// @Param occupancy query int false "Pretend occupancy (0-100) instead of the current one"
+// @Success 200 {object} models.PriceBreakdown
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/pricing-rules/preview [get]
+func (h *PricingHandler) Preview(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Query("schedule_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "schedule_id is required"})
+		return
+	}
+	var occupancy *int
+	if v := c.Query("occupancy"); v != "" {
+		o, err := strconv.Atoi(v)
+		if err != nil || o < 0 || o > 100 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "occupancy must be between 0 and 100"})
+			return
+		}
+		occupancy = &o
+	}
+
+	breakdown, err := h.repo.Preview(c.Request.Context(), scheduleID, occupancy)
+	if err != nil {
+		pricingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// @Summary Order price breakdown
+// @Description The pricing rule evaluation saved when the order was created.
+// @Tags Admin Pricing
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} models.PriceBreakdown
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/orders/{id}/pricing [get]
+func (h *PricingHandler) OrderPricing(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid order id"})
+		return
+	}
+	breakdown, err := h.repo.OrderPricing(c.Request.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "order has no price breakdown"})
+		return
+	}
+	if err != nil {
+		pricingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, breakdown)
+}
+
+func pricingRuleID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid pricing rule id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func bindPricingRule(c *gin.Context, req *models.PricingRuleRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return false
+	}
+	var msg string
+	switch {
+	case req.StartFrom != nil && !pricing.ValidClock(*req.StartFrom),
+		req.StartUntil != nil && !pricing.ValidClock(*req.StartUntil):
+		msg = "start_from and start_until must be HH:MM"
+	case req.MinOccupancy != nil && req.MaxOccupancy != nil && *req.MinOccupancy > *req.MaxOccupancy:
+		msg = "min_occupancy must not be above max_occupancy"
+	case req.AdjustmentType == "percent" && req.AdjustmentValue < -100:
+		msg = "percent adjustment must not be below -100"
+	case req.AdjustmentType == "set" && req.AdjustmentValue < 0:
+		msg = "set adjustment must not be negative"
+	}
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+		return false
+	}
+	return true
+}
+
+func pricingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "pricing rule not found"})
+	case errors.Is(err, repository.ErrScheduleNotFound), errors.Is(err, repository.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+}
@@ -1,30 +1,31 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
-	"path/filepath"
-	"time"
+	"strconv"
 
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/notify"
 	"github.com/cristian-yw/Weekly10/internal/repository"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserHandler struct {
-	repo *repository.UserRepository
+	repo  *repository.UserRepository
+	media *media.Store
 }
 
-func NewUserHandler(repo *repository.UserRepository) *UserHandler {
-	return &UserHandler{repo: repo}
+func NewUserHandler(repo *repository.UserRepository, media *media.Store) *UserHandler {
+	return &UserHandler{repo: repo, media: media}
 }
 
 // @Summary Get User Profile
-// @Description Get logged-in user's profile information
+// @Description Get logged-in user's profile information, including loyalty points balance and tier
 // @Tags Users
 // @Produce json
-// @Success 200 {object} models.UserProfileResponse
+// @Success 200 {object} models.UserProfile
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
 // @Router /user/profile [get]
@@ -35,9 +36,49 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		return
 	}
+	profile.AvatarURL = h.media.URL(c.Request.Context(), profile.AvatarURL, "medium")
+	if profile.Loyalty, err = h.repo.LoyaltySummary(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, profile)
 }
 
+// @Summary Get Loyalty Points
+// @Description Loyalty points ledger of the logged-in user, newest first. Kind: earn, redeem, reverse (refund of an order that earned points) or refund (redeemed points given back).
+// @Tags Users
+// @Produce json
+// @Param page query int false "Page" default(1)
+// @Param limit query int false "Entries per page (max 100)" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /user/points [get]
+func (h *UserHandler) GetPoints(c *gin.Context) {
+	userID := c.GetInt("userID")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx := c.Request.Context()
+	summary, err := h.repo.LoyaltySummary(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	entries, err := h.repo.ListPoints(ctx, userID, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"loyalty": summary, "page": page, "entries": entries})
+}
+
 // @Summary Get Order History
 // @Description Get logged-in user's order history
 // @Tags Users
@@ -66,10 +107,12 @@ func (h *UserHandler) GetHistory(c *gin.Context) {
 // @Param        first_name formData string false "First name"
 // @Param        last_name  formData string false "Last name"
 // @Param        phone      formData string false "Phone number"
-// @Param        avatar     formData file   false "Avatar image"
+// @Param        avatar     formData file   false "Avatar image (jpeg, png or gif)"
+// @Param        language   formData string false "Notification language (en, id)"
 // @Success      200 {object} models.User
 // @Failure      400 {object} map[string]string
 // @Failure      401 {object} map[string]string
+// @Failure      413 {object} map[string]string
 // @Router       /user/profile [patch]
 func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	userID := c.GetInt("userID")
@@ -84,16 +127,33 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	if v := c.PostForm("phone"); v != "" {
 		phone = &v
 	}
+	// bahasa template notifikasi
+	var language *string
+	if v := c.PostForm("language"); v != "" {
+		if !notify.HasLanguage(v) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported language"})
+			return
+		}
+		language = &v
+	}
 
-	// optional avatar file
+	// optional avatar file, yang disimpan di DB adalah key storage
 	var avatarURL *string
+	var oldAvatar string
 	if file, err := c.FormFile("avatar"); err == nil {
-		filename := fmt.Sprintf("avatar_%d_%d%s", userID, time.Now().Unix(), filepath.Ext(file.Filename))
-		path := filepath.Join("uploads", filename)
-		if err := c.SaveUploadedFile(file, path); err == nil {
-			u := "/uploads/" + filename
-			avatarURL = &u
+		current, err := h.repo.GetByID(c.Request.Context(), userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		oldAvatar = current.AvatarURL
+
+		img, err := h.media.SaveUpload(c.Request.Context(), file, "avatars")
+		if err != nil {
+			mediaError(c, err)
+			return
 		}
+		avatarURL = &img.Key
 	}
 
 	if err := h.repo.UpdateProfile(
@@ -103,16 +163,22 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 		lastName,
 		phone,
 		avatarURL,
+		language,
 	); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	if avatarURL != nil {
+		deleteReplacedImage(c.Request.Context(), h.media, h.repo.ImageInUse, oldAvatar, *avatarURL)
+	}
+
 	profile, err := h.repo.GetByID(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	profile.AvatarURL = h.media.URL(c.Request.Context(), profile.AvatarURL, "medium")
 
 	c.JSON(http.StatusOK, profile)
 }
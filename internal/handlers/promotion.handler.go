@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type PromotionHandler struct {
+	repo *repository.PromotionRepository
+}
+
+func NewPromotionHandler(repo *repository.PromotionRepository) *PromotionHandler {
+	return &PromotionHandler{repo: repo}
+}
+
+// @Summary List promotions
+// @Tags Admin Promotions
+// @Produce json
+// @Success 200 {array} models.Promotion
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions [get]
+func (h *PromotionHandler) ListPromotions(c *gin.Context) {
+	promotions, err := h.repo.ListPromotions(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, promotions)
+}
+
+// @Summary Get promotion
+// @Tags Admin Promotions
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} models.Promotion
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [get]
+func (h *PromotionHandler) GetPromotion(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	p, err := h.repo.GetPromotion(c.Request.Context(), id)
+	if err != nil {
+		promotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// @Summary Create promotion
+// @Description Percentage or fixed discount code. Empty movie_ids, cinema_ids and days_of_week (0 = Sunday,
+// @Description checked against the show date) mean no restriction. Codes are case-insensitive.
+// @Tags Admin Promotions
+// @Accept json
+// @Produce json
+// @Param body body models.PromotionRequest true "Promotion"
+// @Success 201 {object} models.Promotion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions [post]
+func (h *PromotionHandler) CreatePromotion(c *gin.Context) {
+	var req models.PromotionRequest
+	if !bindPromotion(c, &req) {
+		return
+	}
+	p, err := h.repo.CreatePromotion(c.Request.Context(), req)
+	if err != nil {
+		promotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// @Summary Update promotion
+// @Description Replaces all settings of the promotion; the usage count is kept.
+// @Tags Admin Promotions
+// @Accept json
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Param body body models.PromotionRequest true "Promotion"
+// @Success 200 {object} models.Promotion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [put]
+func (h *PromotionHandler) UpdatePromotion(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	var req models.PromotionRequest
+	if !bindPromotion(c, &req) {
+		return
+	}
+	p, err := h.repo.UpdatePromotion(c.Request.Context(), id, req)
+	if err != nil {
+		promotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// @Summary Deactivate promotion
+// @Description Promotions are never deleted because redemptions refer to them.
+// @Tags Admin Promotions
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions/{id} [delete]
+func (h *PromotionHandler) DeactivatePromotion(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	if err := h.repo.DeactivatePromotion(c.Request.Context(), id); err != nil {
+		promotionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "promotion deactivated successfully"})
+}
+
+// @Summary List promotion redemptions
+// @Tags Admin Promotions
+// @Produce json
+// @Param id path int true "Promotion ID"
+// @Success 200 {array} models.PromotionRedemption
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/promotions/{id}/redemptions [get]
+func (h *PromotionHandler) ListRedemptions(c *gin.Context) {
+	id, ok := promotionID(c)
+	if !ok {
+		return
+	}
+	redemptions, err := h.repo.ListRedemptions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, redemptions)
+}
+
+func promotionID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid promotion id"})
+		return 0, false
+	}
+	return id, true
+}
+
+func bindPromotion(c *gin.Context, req *models.PromotionRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return false
+	}
+	switch {
+	case req.DiscountType == "percent" && req.DiscountValue > 100:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "percent discount must be between 1 and 100"})
+		return false
+	case req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidUntil.After(*req.ValidFrom):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "valid_until must be after valid_from"})
+		return false
+	}
+	return true
+}
+
+func promotionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "promotion not found"})
+	case errors.Is(err, repository.ErrDuplicateCode):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	}
+}
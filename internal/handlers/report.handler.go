@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/export"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	repo *repository.ReportRepository
+}
+
+func NewReportHandler(repo *repository.ReportRepository) *ReportHandler {
+	return &ReportHandler{repo: repo}
+}
+
+// @Summary Revenue report
+// @Description Revenue, orders and tickets sold of paid orders per period, optionally split by movie, cinema or location.
+// @Tags Admin Reports
+// @Produce json
+// @Produce text/csv
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), default today"
+// @Param granularity query string false "Period" Enums(day, week, month) default(day)
+// @Param by query string false "Split by" Enums(movie, cinema, location)
+// @Param cinema_id query int false "Only this cinema"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} models.Report
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reports/revenue [get]
+func (h *ReportHandler) Revenue(c *gin.Context) {
+	p, ok := reportParams(c)
+	if !ok {
+		return
+	}
+	p.By = c.Query("by")
+	if !repository.RevenueDimensions[p.By] {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "by must be one of movie, cinema, location"})
+		return
+	}
+
+	report, err := h.repo.Revenue(c.Request.Context(), p)
+	writeReport(c, report, err)
+}
+
+// @Summary Occupancy report
+// @Description Tickets sold against the active seat count of the auditorium for every schedule.
+// @Tags Admin Reports
+// @Produce json
+// @Produce text/csv
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), default today"
+// @Param granularity query string false "Period" Enums(day, week, month) default(day)
+// @Param cinema_id query int false "Only this cinema"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} models.Report
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reports/occupancy [get]
+func (h *ReportHandler) Occupancy(c *gin.Context) {
+	p, ok := reportParams(c)
+	if !ok {
+		return
+	}
+	report, err := h.repo.Occupancy(c.Request.Context(), p)
+	writeReport(c, report, err)
+}
+
+// @Summary Top movies report
+// @Description Movies ranked by revenue in every period.
+// @Tags Admin Reports
+// @Produce json
+// @Produce text/csv
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), default today"
+// @Param granularity query string false "Period" Enums(day, week, month) default(day)
+// @Param limit query int false "Movies per period" default(10)
+// @Param cinema_id query int false "Only this cinema"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} models.Report
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reports/top-movies [get]
+func (h *ReportHandler) TopMovies(c *gin.Context) {
+	p, ok := reportParams(c)
+	if !ok {
+		return
+	}
+	p.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if p.Limit <= 0 || p.Limit > 100 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 100"})
+		return
+	}
+
+	report, err := h.repo.TopMovies(c.Request.Context(), p)
+	writeReport(c, report, err)
+}
+
+// @Summary Ticket types report
+// @Description Tickets sold and gross ticket revenue (ticket prices before promo codes and points) per ticket type and period.
+// @Tags Admin Reports
+// @Produce json
+// @Produce text/csv
+// @Param from query string false "Start date (YYYY-MM-DD), default 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), default today"
+// @Param granularity query string false "Period" Enums(day, week, month) default(day)
+// @Param cinema_id query int false "Only this cinema"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} models.Report
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/reports/ticket-types [get]
+func (h *ReportHandler) TicketTypes(c *gin.Context) {
+	p, ok := reportParams(c)
+	if !ok {
+		return
+	}
+	report, err := h.repo.TicketTypes(c.Request.Context(), p)
+	writeReport(c, report, err)
+}
+
+// reportParams membaca parameter bersama; menulis response 400 jika tidak valid
+func reportParams(c *gin.Context) (models.ReportParams, bool) {
+	today := time.Now().Format("2006-01-02")
+	p := models.ReportParams{
+		From:        c.DefaultQuery("from", time.Now().AddDate(0, 0, -30).Format("2006-01-02")),
+		To:          c.DefaultQuery("to", today),
+		Granularity: c.DefaultQuery("granularity", "day"),
+	}
+
+	from, err := time.Parse("2006-01-02", p.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "from must be YYYY-MM-DD"})
+		return p, false
+	}
+	to, err := time.Parse("2006-01-02", p.To)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "to must be YYYY-MM-DD"})
+		return p, false
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "to must not be before from"})
+		return p, false
+	}
+	if !repository.ReportGranularities[p.Granularity] {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "granularity must be one of day, week, month"})
+		return p, false
+	}
+	if v := c.Query("cinema_id"); v != "" {
+		if p.CinemaID, err = strconv.Atoi(v); err != nil || p.CinemaID <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid cinema_id"})
+			return p, false
+		}
+	}
+	if f := c.DefaultQuery("format", "json"); f != "json" && f != "csv" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "format must be json or csv"})
+		return p, false
+	}
+	return p, true
+}
+
+// writeReport membalas dengan JSON, atau file CSV jika format=csv
+func writeReport(c *gin.Context, report *models.Report, err error) {
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if c.Query("format") != "csv" {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s_%s_%s.csv", report.Name, report.Params.Granularity, report.Params.From, report.Params.To)
+	c.Header("Content-Type", export.ContentType("csv"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	w, _ := export.NewWriter(c.Writer, "csv")
+	if err := w.WriteHeader(report.Columns); err != nil {
+		log.Println("report csv:", err)
+		return
+	}
+	values := make([]interface{}, len(report.Columns))
+	for _, row := range report.Rows {
+		for i, col := range report.Columns {
+			values[i] = row[col]
+		}
+		if err := w.WriteRow(values); err != nil {
+			log.Println("report csv:", err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		log.Println("report csv:", err)
+	}
+}
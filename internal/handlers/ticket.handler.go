@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// kode tipe tiket: huruf kecil, angka dan underscore
+var ticketTypeCode = regexp.MustCompile(`^[a-z][a-z0-9_]{0,19}$`)
+
+type TicketHandler struct {
+	repo *repository.TicketRepository
+}
+
+func NewTicketHandler(repo *repository.TicketRepository) *TicketHandler {
+	return &TicketHandler{repo: repo}
+}
+
+// @Summary List ticket types
+// @Tags Admin Tickets
+// @Produce json
+// @Success 200 {array} models.TicketType
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/ticket-types [get]
+func (h *TicketHandler) ListTypes(c *gin.Context) {
+	types, err := h.repo.ListTypes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, types)
+}
+
+// @Summary Create or update ticket type
+// @Description Inactive ticket types can no longer be sold; past orders keep them.
+// @Tags Admin Tickets
+// @Accept json
+// @Produce json
+// @Param code path string true "Ticket type code, e.g. student"
+// @Param body body models.TicketTypeRequest true "Ticket type"
+// @Success 200 {object} models.TicketType
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/ticket-types/{code} [put]
+func (h *TicketHandler) SaveType(c *gin.Context) {
+	code := c.Param("code")
+	if !ticketTypeCode.MatchString(code) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "code must be lowercase letters, digits or underscore"})
+		return
+	}
+	var req models.TicketTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	t, err := h.repo.SaveType(c.Request.Context(), code, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// @Summary List ticket price rules
+// @Tags Admin Tickets
+// @Produce json
+// @Success 200 {array} models.TicketPriceRule
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/ticket-prices [get]
+func (h *TicketHandler) ListPriceRules(c *gin.Context) {
+	rules, err := h.repo.ListPriceRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Create ticket price rule
+// @Description Price of a ticket type for one schedule, one cinema, or every schedule (neither set).
+// @Description The most specific rule wins. Set either price (fixed) or percent (of the schedule price).
+// @Description Ticket types without a rule cost the schedule price.
+// @Tags Admin Tickets
+// @Accept json
+// @Produce json
+// @Param body body models.TicketPriceRuleRequest true "Price rule"
+// @Success 201 {object} models.TicketPriceRule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/ticket-prices [post]
+func (h *TicketHandler) CreatePriceRule(c *gin.Context) {
+	var req models.TicketPriceRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	switch {
+	case (req.Price == nil) == (req.Percent == nil):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "set either price or percent"})
+		return
+	case req.CinemaID != nil && req.ScheduleID != nil:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "set either cinema_id or schedule_id, not both"})
+		return
+	}
+
+	rule, err := h.repo.CreatePriceRule(c.Request.Context(), req)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "ticket type, cinema or schedule not found"})
+	case errors.Is(err, repository.ErrDuplicatePriceRule):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusCreated, rule)
+	}
+}
+
+// @Summary Delete ticket price rule
+// @Tags Admin Tickets
+// @Produce json
+// @Param id path int true "Price rule ID"
+// @Success 200 {object} models.SuccessMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/ticket-prices/{id} [delete]
+func (h *TicketHandler) DeletePriceRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid price rule id"})
+		return
+	}
+	err = h.repo.DeletePriceRule(c.Request.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "price rule not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessMessage{Message: "price rule deleted successfully"})
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/booking"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/cristian-yw/Weekly10/internal/seating"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultWaitlistOfferTTL = 15 * time.Minute
+	waitlistSweepInterval   = 30 * time.Second
+	waitlistLockTTL         = time.Minute
+)
+
+// waitlistOfferTTL membaca WAITLIST_OFFER_TTL (mis. "15m"): lama hold prioritas untuk user waitlist
+func waitlistOfferTTL() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WAITLIST_OFFER_TTL"))
+	if err != nil || d <= 0 {
+		return defaultWaitlistOfferTTL
+	}
+	return d
+}
+
+// @Summary Join waitlist
+// @Description Wait for seats of a sold-out schedule. When seats free up (cancellation, refund or hold expiry)
+// @Description waiting users are served in FIFO order: each gets a priority hold on the freed seats for
+// @Description WAITLIST_OFFER_TTL (default 15m) before they go back on general sale. The TTL starts when the
+// @Description waitlist_offer notification is written. Users that need more seats than are free keep their place
+// @Description while users behind them may be served. Check the offer with GET /schedules/{id}/waitlist
+// @Description and book the offered seats with POST /orders.
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Param body body models.WaitlistRequest false "Number of seats, default 1"
+// @Success 201 {object} models.WaitlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "schedule not found"
+// @Failure 409 {object} map[string]string "seats available, already waitlisted or show started"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/{id}/waitlist [post]
+func (h *OrderHandler) JoinWaitlist(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule id"})
+		return
+	}
+	var req models.WaitlistRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Count == 0 {
+		req.Count = 1
+	}
+	userID := c.GetInt("userID")
+	ctx := c.Request.Context()
+
+	seats, err := h.freeSeats(ctx, scheduleID, userID)
+	if err != nil {
+		waitlistError(c, err)
+		return
+	}
+	if pickSeats(seats, req.Count) != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "seats are available, book them directly"})
+		return
+	}
+
+	entry, err := h.repo.JoinWaitlist(ctx, scheduleID, userID, req.Count)
+	if err != nil {
+		waitlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary Waitlist status
+// @Description Position in the queue, or the offered seats and until when they are held for the user.
+// @Tags Waitlist
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} models.WaitlistEntry
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "not on the waitlist"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/{id}/waitlist [get]
+func (h *OrderHandler) GetWaitlist(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule id"})
+		return
+	}
+	entry, err := h.repo.GetWaitlist(c.Request.Context(), scheduleID, c.GetInt("userID"))
+	if err != nil {
+		waitlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary Leave waitlist
+// @Description Leave the queue. Seats offered to the user are released and offered to the next user.
+// @Tags Waitlist
+// @Produce json
+// @Param id path int true "Schedule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string "not on the waitlist"
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /schedules/{id}/waitlist [delete]
+func (h *OrderHandler) LeaveWaitlist(c *gin.Context) {
+	scheduleID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid schedule id"})
+		return
+	}
+	userID := c.GetInt("userID")
+	entry, err := h.repo.LeaveWaitlist(c.Request.Context(), scheduleID, userID)
+	if err != nil {
+		waitlistError(c, err)
+		return
+	}
+	if len(entry.OfferedSeats) > 0 {
+		// event "released" dari hold ini memicu tawaran ke user berikutnya
+		if _, err := h.holds.Release(c.Request.Context(), scheduleID, userID, entry.OfferedSeats); err != nil {
+			log.Println("waitlist release:", err)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "left the waitlist"})
+}
+
+// RunWaitlist menawarkan kursi yang dilepas (hold kadaluarsa / dilepas, refund, order gagal) ke waitlist
+// sampai ctx selesai. Event bisa terlewat, jadi semua jadwal dengan antrian juga diperiksa berkala.
+func (h *OrderHandler) RunWaitlist(ctx context.Context) {
+	events := h.hub.Watch()
+	ticker := time.NewTicker(waitlistSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev := <-events:
+			if ev.Type == "released" {
+				h.offerWaitlist(ctx, ev.ScheduleID)
+			}
+		case <-ticker.C:
+			if err := h.repo.ExpireWaitlistOffers(ctx); err != nil {
+				log.Println("waitlist expire:", err)
+			}
+			ids, err := h.repo.WaitlistSchedules(ctx)
+			if err != nil {
+				log.Println("waitlist schedules:", err)
+				continue
+			}
+			for _, id := range ids {
+				h.offerWaitlist(ctx, id)
+			}
+		}
+	}
+}
+
+// offerWaitlist memberi hold prioritas ke antrian FIFO selama kursinya cukup.
+// Lock per jadwal memastikan hanya satu replica yang membagi kursi pada satu waktu.
+func (h *OrderHandler) offerWaitlist(ctx context.Context, scheduleID int) {
+	unlock, ok, err := h.holds.Lock(ctx, "waitlist:"+strconv.Itoa(scheduleID), waitlistLockTTL)
+	if err != nil {
+		log.Println("waitlist lock:", err)
+		return
+	}
+	if !ok {
+		return
+	}
+	defer unlock()
+
+	entries, err := h.repo.WaitingEntries(ctx, scheduleID)
+	if err != nil || len(entries) == 0 {
+		if err != nil {
+			log.Println("waitlist entries:", err)
+		}
+		return
+	}
+	// userID 0: semua hold dianggap milik orang lain
+	seats, err := h.freeSeats(ctx, scheduleID, 0)
+	if err != nil {
+		log.Println("waitlist seats:", err)
+		return
+	}
+
+	for _, e := range entries {
+		picked := pickSeats(seats, e.Count)
+		if picked == nil {
+			continue
+		}
+		// hold dulu supaya kursi tidak diambil user lain selama tawaran ditulis
+		ttl := waitlistOfferTTL()
+		err := h.holds.HoldUntil(ctx, scheduleID, e.UserID, picked, time.Now().Add(ttl))
+		var held *booking.SeatHeldError
+		if errors.As(err, &held) {
+			// kursi baru saja diambil user lain, ditawarkan lagi pada event berikutnya
+			return
+		}
+		if err != nil {
+			log.Println("waitlist hold:", err)
+			return
+		}
+		expiresAt, offered, err := h.repo.OfferWaitlist(ctx, e.ID, picked, ttl)
+		if err != nil || !offered {
+			if err != nil {
+				log.Println("waitlist offer:", err)
+			}
+			if _, err := h.holds.Release(ctx, scheduleID, e.UserID, picked); err != nil {
+				log.Println("waitlist release:", err)
+			}
+			continue
+		}
+		// hold disamakan dengan batas tawaran, dihitung sejak notifikasi ditulis
+		if err := h.holds.HoldUntil(ctx, scheduleID, e.UserID, picked, expiresAt); err != nil {
+			log.Println("waitlist hold:", err)
+		}
+		for i := range seats {
+			if slices.Contains(picked, seats[i].Code) {
+				seats[i].Free = false
+			}
+		}
+	}
+}
+
+// freeSeats: denah kursi jadwal dengan kursi terjual dan di-hold selain oleh userID ditandai terisi
+func (h *OrderHandler) freeSeats(ctx context.Context, scheduleID, userID int) ([]seating.Seat, error) {
+	seats, err := h.repo.SeatLayout(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	held, err := h.holds.HeldByOthers(ctx, scheduleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range seats {
+		if slices.Contains(held, seats[i].Code) {
+			seats[i].Free = false
+		}
+	}
+	return seats, nil
+}
+
+// pickSeats memilih blok bersebelahan terbaik; jika tidak ada, kursi standard kosong
+// mana saja sesuai urutan denah. nil jika kursi kosong tidak cukup.
+func pickSeats(seats []seating.Seat, count int) []string {
+	if best, err := seating.Suggest(seats, seating.Request{Count: count}); err == nil {
+		return best.Seats
+	}
+	picked := []string{}
+	for _, s := range seats {
+		if s.Free && (s.Type == "" || s.Type == seating.TypeStandard) {
+			picked = append(picked, s.Code)
+			if len(picked) == count {
+				return picked
+			}
+		}
+	}
+	return nil
+}
+
+func waitlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotWaitlisted):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrAlreadyWaitlisted), errors.Is(err, repository.ErrShowStarted):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		orderError(c, err)
+	}
+}
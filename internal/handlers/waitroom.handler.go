@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cristian-yw/Weekly10/internal/booking"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+type WaitRoomHandler struct {
+	queue *booking.WaitRoom
+}
+
+func NewWaitRoomHandler(queue *booking.WaitRoom) *WaitRoomHandler {
+	return &WaitRoomHandler{queue: queue}
+}
+
+// @Summary Get waiting room
+// @Description Settings of the waiting room of a schedule or movie, with the number of waiting and admitted users.
+// @Tags Admin Waiting Room
+// @Produce json
+// @Param scope path string true "Scope" Enums(schedule, movie)
+// @Param id path int true "Schedule or movie ID"
+// @Success 200 {object} models.WaitRoomStats
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/waitroom/{scope}/{id} [get]
+func (h *WaitRoomHandler) Get(c *gin.Context) {
+	scope, id, ok := waitRoomScope(c)
+	if !ok {
+		return
+	}
+	stats, err := h.queue.Stats(c.Request.Context(), scope, id)
+	if errors.Is(err, redis.Nil) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "waiting room not configured"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// @Summary Configure waiting room
+// @Description Turn the waiting room of a schedule or movie on or off and tune how many users may book at once.
+// @Description A schedule waiting room takes precedence over the one of its movie.
+// @Tags Admin Waiting Room
+// @Accept json
+// @Produce json
+// @Param scope path string true "Scope" Enums(schedule, movie)
+// @Param id path int true "Schedule or movie ID"
+// @Param body body models.WaitRoomConfig true "Settings (scope and scope_id are taken from the path)"
+// @Success 200 {object} models.WaitRoomStats
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/waitroom/{scope}/{id} [put]
+func (h *WaitRoomHandler) Put(c *gin.Context) {
+	scope, id, ok := waitRoomScope(c)
+	if !ok {
+		return
+	}
+	var cfg models.WaitRoomConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	cfg.Scope, cfg.ScopeID = scope, id
+
+	ctx := c.Request.Context()
+	if err := h.queue.SetConfig(ctx, cfg); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	stats, err := h.queue.Stats(ctx, scope, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// @Summary Delete waiting room
+// @Description Remove the settings and drop everyone from the queue.
+// @Tags Admin Waiting Room
+// @Produce json
+// @Param scope path string true "Scope" Enums(schedule, movie)
+// @Param id path int true "Schedule or movie ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/waitroom/{scope}/{id} [delete]
+func (h *WaitRoomHandler) Delete(c *gin.Context) {
+	scope, id, ok := waitRoomScope(c)
+	if !ok {
+		return
+	}
+	if err := h.queue.Delete(c.Request.Context(), scope, id); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "waiting room deleted"})
+}
+
+func waitRoomScope(c *gin.Context) (string, int, bool) {
+	scope := c.Param("scope")
+	if scope != "schedule" && scope != "movie" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: booking.ErrInvalidScope.Error()})
+		return "", 0, false
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "invalid id"})
+		return "", 0, false
+	}
+	return scope, id, true
+}
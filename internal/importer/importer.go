@@ -0,0 +1,257 @@
+// Package importer membaca file import movie + schedule (CSV atau JSON)
+// dan memvalidasi isinya sebelum disimpan oleh repository.
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported import format, use csv or json")
+
+// Kolom CSV. Satu baris = satu schedule; baris dengan tmdb_id sama digabung
+// menjadi satu movie. Kolom schedule boleh kosong untuk movie tanpa jadwal.
+// genres dipisah "|".
+var csvColumns = []string{
+	"tmdb_id", "title", "overview", "release_date", "runtime", "poster_path", "backdrop_path",
+	"genres", "director_tmdb_id", "director_name",
+	"cinema_id", "auditorium_id", "location_id", "time_id", "date", "price",
+}
+
+var scheduleColumns = []string{"cinema_id", "auditorium_id", "location_id", "time_id", "date", "price"}
+
+// Format menentukan format dari nama file jika format tidak diisi
+func Format(format, filename string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+	}
+	switch format = strings.ToLower(format); format {
+	case "csv", "json":
+		return format, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Parse membaca seluruh file. Baris yang tidak bisa di-parse dilaporkan
+// sebagai ImportError; error hanya dikembalikan jika file tidak terbaca sama sekali.
+func Parse(r io.Reader, format string) ([]models.ImportMovie, []models.ImportError, error) {
+	switch format {
+	case "csv":
+		return parseCSV(r)
+	case "json":
+		return parseJSON(r)
+	}
+	return nil, nil, ErrUnsupportedFormat
+}
+
+func parseJSON(r io.Reader) ([]models.ImportMovie, []models.ImportError, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("invalid json: expected an array of movies: %w", err)
+	}
+
+	var movies []models.ImportMovie
+	var errs []models.ImportError
+	for i, item := range raw {
+		row := i + 1
+		var m models.ImportMovie
+		if err := json.Unmarshal(item, &m); err != nil {
+			errs = append(errs, models.ImportError{Row: row, Message: err.Error()})
+			continue
+		}
+		m.Row = row
+		for j := range m.Schedules {
+			m.Schedules[j].Row = row
+		}
+		movies = append(movies, m)
+	}
+	return movies, errs, nil
+}
+
+func parseCSV(r io.Reader) ([]models.ImportMovie, []models.ImportError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"tmdb_id", "title"} {
+		if _, ok := index[required]; !ok {
+			return nil, nil, fmt.Errorf("csv header must contain %s (columns: %s)", required, strings.Join(csvColumns, ","))
+		}
+	}
+
+	var movies []models.ImportMovie
+	var errs []models.ImportError
+	byTMDB := map[int]int{} // tmdb_id -> index di movies
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			errs = append(errs, models.ImportError{Row: line, Message: err.Error()})
+			continue
+		}
+
+		rec := csvRecord{index: index, values: record, row: line}
+		m := models.ImportMovie{
+			Row:          line,
+			TMDBID:       rec.int("tmdb_id"),
+			Title:        rec.get("title"),
+			Overview:     rec.get("overview"),
+			ReleaseDate:  rec.get("release_date"),
+			Runtime:      rec.int("runtime"),
+			PosterPath:   rec.get("poster_path"),
+			BackdropPath: rec.get("backdrop_path"),
+		}
+		for _, g := range strings.Split(rec.get("genres"), "|") {
+			if g = strings.TrimSpace(g); g != "" {
+				m.Genres = append(m.Genres, g)
+			}
+		}
+		if id := rec.int("director_tmdb_id"); id != 0 || rec.get("director_name") != "" {
+			m.Director = &models.PersonRequest{TMDBID: id, Name: rec.get("director_name")}
+		}
+
+		var schedule *models.ImportSchedule
+		if rec.any(scheduleColumns...) {
+			schedule = &models.ImportSchedule{Row: line, ScheduleRequest: models.ScheduleRequest{
+				CinemaID:     rec.int("cinema_id"),
+				AuditoriumID: rec.int("auditorium_id"),
+				LocationID:   rec.int("location_id"),
+				TimeID:       rec.int("time_id"),
+				Date:         rec.get("date"),
+				Price:        rec.int("price"),
+			}}
+		}
+
+		if len(rec.errs) > 0 {
+			errs = append(errs, rec.errs...)
+			continue
+		}
+
+		// baris lanjutan untuk movie yang sama hanya menambah schedule
+		if i, ok := byTMDB[m.TMDBID]; ok && m.TMDBID != 0 {
+			if schedule != nil {
+				movies[i].Schedules = append(movies[i].Schedules, *schedule)
+			}
+			continue
+		}
+		if schedule != nil {
+			m.Schedules = append(m.Schedules, *schedule)
+		}
+		byTMDB[m.TMDBID] = len(movies)
+		movies = append(movies, m)
+	}
+	return movies, errs, nil
+}
+
+type csvRecord struct {
+	index  map[string]int
+	values []string
+	row    int
+	errs   []models.ImportError
+}
+
+func (r *csvRecord) get(column string) string {
+	i, ok := r.index[column]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+func (r *csvRecord) int(column string) int {
+	v := r.get(column)
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		r.errs = append(r.errs, models.ImportError{Row: r.row, Field: column, Message: "must be a number"})
+	}
+	return n
+}
+
+func (r *csvRecord) any(columns ...string) bool {
+	for _, c := range columns {
+		if r.get(c) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate memeriksa field wajib dan format. Referensi ke cinema, studio,
+// lokasi dan jam tayang diperiksa repository terhadap database.
+func Validate(movies []models.ImportMovie) []models.ImportError {
+	var errs []models.ImportError
+	add := func(row int, field, msg string) {
+		errs = append(errs, models.ImportError{Row: row, Field: field, Message: msg})
+	}
+
+	seen := map[int]int{}
+	for _, m := range movies {
+		if m.TMDBID <= 0 {
+			add(m.Row, "tmdb_id", "is required")
+		} else if first, ok := seen[m.TMDBID]; ok {
+			add(m.Row, "tmdb_id", fmt.Sprintf("duplicate of row %d", first))
+		} else {
+			seen[m.TMDBID] = m.Row
+		}
+		if strings.TrimSpace(m.Title) == "" {
+			add(m.Row, "title", "is required")
+		}
+		if m.ReleaseDate != "" && !validDate(m.ReleaseDate) {
+			add(m.Row, "release_date", "must be YYYY-MM-DD")
+		}
+		if m.Runtime < 0 {
+			add(m.Row, "runtime", "must not be negative")
+		}
+		if m.Director != nil && (m.Director.TMDBID <= 0 || m.Director.Name == "") {
+			add(m.Row, "director", "director_tmdb_id and director_name are both required")
+		}
+
+		for _, s := range m.Schedules {
+			if s.CinemaID <= 0 && s.AuditoriumID <= 0 {
+				add(s.Row, "cinema_id", "cinema_id or auditorium_id is required")
+			}
+			if s.LocationID <= 0 {
+				add(s.Row, "location_id", "is required")
+			}
+			if s.TimeID <= 0 {
+				add(s.Row, "time_id", "is required")
+			}
+			if !validDate(s.Date) {
+				add(s.Row, "date", "must be YYYY-MM-DD")
+			}
+			if s.Price <= 0 {
+				add(s.Row, "price", "must be greater than 0")
+			}
+		}
+	}
+	return errs
+}
+
+func validDate(v string) bool {
+	_, err := time.Parse("2006-01-02", v)
+	return err == nil
+}
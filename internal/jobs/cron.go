@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+// Schedule adalah ekspresi cron 5 field: menit jam tanggal bulan hari-dalam-minggu.
+// Mendukung *, angka, range (1-5), list (1,3,5) dan step (*/15, 0-30/10).
+type Schedule struct {
+	minute, hour, dom, month, dow [64]bool
+	domAny, dowAny                bool
+}
+
+var cronBounds = [5][2]int{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 6},  // day of week (0 = Sunday)
+}
+
+func ParseSchedule(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	// "*" dan "*/n" sama-sama tidak membatasi field (aturan OR hanya untuk nilai eksplisit)
+	s := &Schedule{
+		domAny: strings.HasPrefix(fields[2], "*"),
+		dowAny: strings.HasPrefix(fields[4], "*"),
+	}
+	targets := []*[64]bool{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, field := range fields {
+		if err := parseCronField(field, cronBounds[i][0], cronBounds[i][1], targets[i]); err != nil {
+			return nil, fmt.Errorf("cron %q: %w", spec, err)
+		}
+	}
+	return s, nil
+}
+
+func parseCronField(field string, lo, hi int, out *[64]bool) error {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, stepStr, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+			part = base
+		}
+
+		from, to := lo, hi
+		if part != "*" {
+			startStr, endStr, isRange := strings.Cut(part, "-")
+			start, err := strconv.Atoi(startStr)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", part)
+			}
+			from, to = start, start
+			if isRange {
+				if to, err = strconv.Atoi(endStr); err != nil {
+					return fmt.Errorf("invalid range %q", part)
+				}
+			} else if step > 1 {
+				to = hi
+			}
+		}
+		if from < lo || to > hi || from > to {
+			return fmt.Errorf("value %q out of range %d-%d", part, lo, hi)
+		}
+
+		for v := from; v <= to; v += step {
+			out[v] = true
+		}
+	}
+	return nil
+}
+
+// Next mengembalikan waktu (resolusi menit) berikutnya setelah t yang cocok dengan jadwal
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.matches(t) {
+			return t
+		}
+		t = t.Add(time.Minute)
+	}
+	return limit
+}
+
+func (s *Schedule) matches(t time.Time) bool {
+	if !s.minute[t.Minute()] || !s.hour[t.Hour()] || !s.month[int(t.Month())] {
+		return false
+	}
+
+	// aturan cron standar: jika dom dan dow sama-sama dibatasi, cukup salah satu yang cocok
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// RunOnSchedule memanggil fn setiap kali jadwal tercapai sampai ctx selesai
+func RunOnSchedule(ctx context.Context, schedule *Schedule, fn func(context.Context)) {
+	for {
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			fn(ctx)
+		}
+	}
+}
+
+// StartSyncSchedule menjalankan sync TMDB sesuai ekspresi cron. Semua replica boleh
+// menjalankan scheduler ini; lock di SyncRunner memastikan hanya satu yang benar-benar sync.
+func StartSyncSchedule(ctx context.Context, runner *SyncRunner, spec string, opts models.SyncOptions) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+
+	go RunOnSchedule(ctx, schedule, func(ctx context.Context) {
+		job, runningID, err := runner.Start(ctx, opts, "schedule")
+		if err != nil {
+			log.Printf("scheduled tmdb sync skipped: %v %s", err, runningID)
+			return
+		}
+		log.Println("scheduled tmdb sync started:", job.ID)
+	})
+	log.Printf("tmdb sync scheduled (%s) list=%s pages=%d", spec, opts.List, opts.Pages)
+	return nil
+}
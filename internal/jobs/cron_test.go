@@ -0,0 +1,106 @@
+package jobs
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		field   func(*Schedule) *[64]bool
+		want    []int
+		wantErr bool
+	}{
+		{spec: "* * * * *", field: func(s *Schedule) *[64]bool { return &s.hour }, want: seq(0, 23, 1)},
+		{spec: "5 * * * *", field: func(s *Schedule) *[64]bool { return &s.minute }, want: []int{5}},
+		{spec: "0 9-17 * * *", field: func(s *Schedule) *[64]bool { return &s.hour }, want: seq(9, 17, 1)},
+		{spec: "*/15 * * * *", field: func(s *Schedule) *[64]bool { return &s.minute }, want: []int{0, 15, 30, 45}},
+		{spec: "0-30/10 * * * *", field: func(s *Schedule) *[64]bool { return &s.minute }, want: []int{0, 10, 20, 30}},
+		{spec: "10/20 * * * *", field: func(s *Schedule) *[64]bool { return &s.minute }, want: []int{10, 30, 50}},
+		{spec: "0 0 1,15,31 * *", field: func(s *Schedule) *[64]bool { return &s.dom }, want: []int{1, 15, 31}},
+		{spec: "0 0 * 1-3,12 *", field: func(s *Schedule) *[64]bool { return &s.month }, want: []int{1, 2, 3, 12}},
+		{spec: "0 0 * * 1-5/2", field: func(s *Schedule) *[64]bool { return &s.dow }, want: []int{1, 3, 5}},
+		{spec: "* * * *", wantErr: true},
+		{spec: "60 * * * *", wantErr: true},
+		{spec: "0 24 * * *", wantErr: true},
+		{spec: "0 0 0 * *", wantErr: true},
+		{spec: "0 0 * 13 *", wantErr: true},
+		{spec: "0 0 * * 7", wantErr: true},
+		{spec: "5-1 * * * *", wantErr: true},
+		{spec: "*/0 * * * *", wantErr: true},
+		{spec: "a * * * *", wantErr: true},
+		{spec: "1-x * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := ParseSchedule(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSchedule(%q) succeeded, want error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := values(tt.field(s)); !slices.Equal(got, tt.want) {
+				t.Fatalf("values = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2024-01-01 adalah hari Senin
+	from := time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 1, 1, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{"30 9-17 * * *", time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 0", time.Date(2024, 1, 7, 12, 0, 0, 0, time.UTC)},
+		{"0 0 * * 3,5", time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		// dom dan dow sama-sama dibatasi: cukup salah satu yang cocok
+		{"0 0 15 * 5", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)},
+		// step berawalan * dianggap "*" (seperti cron klasik): hanya dow yang menentukan,
+		// dengan OR hasilnya tanggal 3 (hari Rabu)
+		{"0 0 */2 * 4", time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * */3", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Fatalf("Next = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func seq(from, to, step int) []int {
+	var out []int
+	for v := from; v <= to; v += step {
+		out = append(out, v)
+	}
+	return out
+}
+
+func values(field *[64]bool) []int {
+	var out []int
+	for v, ok := range field {
+		if ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
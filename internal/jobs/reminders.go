@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+)
+
+const (
+	defaultShowtimeReminders = "24h,2h"
+
+	jobPollInterval = 30 * time.Second
+	jobBatch        = 50
+	// lease harus lebih lama dari satu batch job
+	jobLease       = 5 * time.Minute
+	jobMaxAttempts = 5
+
+	// job direncanakan sampai planAhead sebelum jatuh tempo; planner yang mati sebentar tidak
+	// membuat pengingat terlewat karena job yang sudah lewat tetap dijadwalkan selama film belum mulai
+	reminderPlanAhead = time.Hour
+	// pengingat yang terlambat lebih dari ini (mis. server mati) tidak dikirim lagi
+	reminderMaxLate = 30 * time.Minute
+)
+
+// ReminderScheduler menjadwalkan dan mengeksekusi pengingat jam tayang lewat table scheduled_jobs.
+// Semua state ada di database sehingga aman di-restart dan dijalankan di banyak replica:
+// job unik per order dan offset, diklaim dengan SKIP LOCKED, dan notifikasinya punya dedup key.
+type ReminderScheduler struct {
+	repo    *repository.JobRepository
+	offsets []time.Duration
+}
+
+// NewReminderSchedulerFromEnv membaca SHOWTIME_REMINDERS, daftar durasi sebelum jam tayang
+// dipisah koma (default "24h,2h"). "off" mematikan pengingat.
+func NewReminderSchedulerFromEnv(repo *repository.JobRepository) (*ReminderScheduler, error) {
+	spec := os.Getenv("SHOWTIME_REMINDERS")
+	if spec == "" {
+		spec = defaultShowtimeReminders
+	}
+	offsets, err := ParseReminderOffsets(spec)
+	if err != nil {
+		return nil, err
+	}
+	return &ReminderScheduler{repo: repo, offsets: offsets}, nil
+}
+
+// ParseReminderOffsets: "24h,2h" -> [24h 2h], "off" -> kosong
+func ParseReminderOffsets(spec string) ([]time.Duration, error) {
+	if strings.TrimSpace(spec) == "off" {
+		return nil, nil
+	}
+	var offsets []time.Duration
+	for _, part := range strings.Split(spec, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("SHOWTIME_REMINDERS: invalid duration %q", part)
+		}
+		if !slices.Contains(offsets, d) {
+			offsets = append(offsets, d)
+		}
+	}
+	return offsets, nil
+}
+
+// Run merencanakan dan mengeksekusi job sampai ctx selesai
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	if len(s.offsets) == 0 {
+		return
+	}
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for {
+		s.plan(ctx)
+		s.runDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReminderScheduler) plan(ctx context.Context) {
+	for _, before := range s.offsets {
+		if _, err := s.repo.PlanShowtimeReminders(ctx, before, reminderPlanAhead); err != nil {
+			log.Printf("plan showtime reminders (%s): %v", before, err)
+		}
+	}
+}
+
+func (s *ReminderScheduler) runDue(ctx context.Context) {
+	for {
+		batch, err := s.repo.ClaimJobs(ctx, jobBatch, jobLease)
+		if err != nil {
+			log.Println("claim jobs:", err)
+			return
+		}
+		for _, job := range batch {
+			s.run(ctx, job)
+		}
+		if len(batch) < jobBatch {
+			return
+		}
+	}
+}
+
+func (s *ReminderScheduler) run(ctx context.Context, job models.ScheduledJob) {
+	var err error
+	switch job.Kind {
+	case repository.JobShowtimeReminder:
+		_, err = s.repo.SendShowtimeReminder(ctx, job, reminderMaxLate)
+	default:
+		err = fmt.Errorf("unknown job kind %q", job.Kind)
+		job.Attempts = jobMaxAttempts
+	}
+	if err == nil {
+		return
+	}
+
+	var retryAt *time.Time
+	if job.Attempts < jobMaxAttempts {
+		at := time.Now().Add(time.Duration(job.Attempts) * time.Minute)
+		retryAt = &at
+	}
+	if err := s.repo.FailJob(ctx, job.ID, err.Error(), retryAt); err != nil {
+		log.Println("fail job:", err)
+	}
+}
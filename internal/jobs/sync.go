@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	syncLockKey   = "sync:tmdb:lock"
+	syncJobPrefix = "sync:tmdb:job:"
+
+	// batas waktu satu sync, sekaligus TTL lock supaya lock tidak pernah
+	// kadaluarsa saat job masih berjalan
+	syncTimeout = time.Hour
+	syncJobTTL  = 7 * 24 * time.Hour
+)
+
+// ErrSyncRunning dikembalikan saat masih ada sync lain yang berjalan (di replica mana pun)
+var ErrSyncRunning = errors.New("another tmdb sync is already running")
+
+// releaseLock hanya menghapus lock jika masih dipegang job yang sama
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// SyncRunner menjalankan sync TMDB di background. Status job disimpan di Redis
+// sehingga bisa dibaca dari replica mana pun, dan lock Redis memastikan hanya
+// satu sync yang berjalan dalam satu waktu.
+type SyncRunner struct {
+	repo *repository.AdminRepository
+	rdb  *redis.Client
+}
+
+func NewSyncRunner(repo *repository.AdminRepository, rdb *redis.Client) *SyncRunner {
+	return &SyncRunner{repo: repo, rdb: rdb}
+}
+
+// Start membuat job baru dan langsung menjalankannya di goroutine.
+// Mengembalikan ErrSyncRunning (beserta ID job yang sedang berjalan) jika lock sudah dipegang.
+func (s *SyncRunner) Start(ctx context.Context, opts models.SyncOptions, trigger string) (*models.SyncJob, string, error) {
+	id, err := newJobID()
+	if err != nil {
+		return nil, "", err
+	}
+
+	ok, err := s.rdb.SetNX(ctx, syncLockKey, id, syncTimeout).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	if !ok {
+		runningID, _ := s.rdb.Get(ctx, syncLockKey).Result()
+		return nil, runningID, ErrSyncRunning
+	}
+
+	job := &models.SyncJob{
+		ID:        id,
+		Trigger:   trigger,
+		Status:    "running",
+		Options:   opts,
+		Result:    models.SyncResult{List: opts.List, Errors: []string{}},
+		StartedAt: time.Now(),
+	}
+	if err := s.save(ctx, job); err != nil {
+		releaseLock.Run(ctx, s.rdb, []string{syncLockKey}, id)
+		return nil, "", err
+	}
+
+	go s.run(job)
+	return job, "", nil
+}
+
+// Get membaca status job dari Redis, redis.Nil jika tidak ditemukan
+func (s *SyncRunner) Get(ctx context.Context, id string) (*models.SyncJob, error) {
+	data, err := s.rdb.Get(ctx, syncJobPrefix+id).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var job models.SyncJob
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+
+	// hasil akhir selalu disimpan sebelum lock dilepas, jadi job "running" yang lock-nya
+	// sudah kadaluarsa (atau dipegang job lain) berarti prosesnya mati di tengah jalan
+	if job.Status == "running" {
+		owner, err := s.rdb.Get(ctx, syncLockKey).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		if owner != job.ID {
+			now := time.Now()
+			job.Status = "failed"
+			job.Error = "sync interrupted: lock expired before the job finished"
+			job.FinishedAt = &now
+			if err := s.save(ctx, &job); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &job, nil
+}
+
+func (s *SyncRunner) run(job *models.SyncJob) {
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+	defer releaseLock.Run(context.Background(), s.rdb, []string{syncLockKey}, job.ID)
+
+	opts := job.Options
+	opts.OnProgress = func(progress models.SyncResult) {
+		job.Result = progress
+		if err := s.save(ctx, job); err != nil {
+			log.Println("sync job progress:", err)
+		}
+	}
+
+	result, err := s.repo.SyncMovies(ctx, opts)
+	if result != nil {
+		job.Result = *result
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Status = "succeeded"
+	if err != nil {
+		job.Status = "failed"
+		job.Error = err.Error()
+	}
+
+	if err := s.save(context.Background(), job); err != nil {
+		log.Println("sync job save:", err)
+	}
+	log.Printf("tmdb sync %s %s: created=%d updated=%d failed=%d",
+		job.ID, job.Status, job.Result.Created, job.Result.Updated, job.Result.Failed)
+}
+
+func (s *SyncRunner) save(ctx context.Context, job *models.SyncJob) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.rdb.Set(ctx, syncJobPrefix+job.ID, data, syncJobTTL).Err()
+}
+
+func newJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
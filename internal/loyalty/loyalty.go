@@ -0,0 +1,78 @@
+// Package loyalty berisi aturan program poin: berapa poin yang didapat dari satu order,
+// berapa nilai poin saat ditukar, dan tier member berdasarkan total belanja bergulir.
+package loyalty
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+type Tier struct {
+	Name string `json:"name"`
+	// MinSpend: total belanja (order paid) dalam Window untuk mencapai tier ini
+	MinSpend int `json:"min_spend"`
+	// Multiplier dikalikan ke poin yang didapat
+	Multiplier float64 `json:"multiplier"`
+}
+
+type Program struct {
+	EarnPer    int           // belanja (Rp) per 1 poin
+	PointValue int           // nilai 1 poin (Rp) saat ditukar
+	Window     time.Duration // periode belanja bergulir untuk tier
+	Tiers      []Tier        // urut dari MinSpend terkecil
+}
+
+// NewProgramFromEnv membaca LOYALTY_EARN_PER (default 1000), LOYALTY_POINT_VALUE (default 10),
+// LOYALTY_WINDOW_DAYS (default 365), LOYALTY_SILVER_SPEND (default 1.000.000)
+// dan LOYALTY_GOLD_SPEND (default 3.000.000)
+func NewProgramFromEnv() *Program {
+	return &Program{
+		EarnPer:    envInt("LOYALTY_EARN_PER", 1000),
+		PointValue: envInt("LOYALTY_POINT_VALUE", 10),
+		Window:     time.Duration(envInt("LOYALTY_WINDOW_DAYS", 365)) * 24 * time.Hour,
+		Tiers: []Tier{
+			{Name: "bronze", MinSpend: 0, Multiplier: 1},
+			{Name: "silver", MinSpend: envInt("LOYALTY_SILVER_SPEND", 1_000_000), Multiplier: 1.25},
+			{Name: "gold", MinSpend: envInt("LOYALTY_GOLD_SPEND", 3_000_000), Multiplier: 1.5},
+		},
+	}
+}
+
+func envInt(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil && v > 0 {
+		return v
+	}
+	return fallback
+}
+
+// TierFor: tier tertinggi yang MinSpend-nya tercapai. next nil jika sudah tier tertinggi.
+func (p *Program) TierFor(spend int) (tier Tier, next *Tier) {
+	tier = p.Tiers[0]
+	for i, t := range p.Tiers {
+		if spend < t.MinSpend {
+			next = &p.Tiers[i]
+			break
+		}
+		tier = t
+	}
+	return tier, next
+}
+
+// Earn: poin dari jumlah yang dibayar, dibulatkan ke bawah
+func (p *Program) Earn(amount int, tier Tier) int {
+	if amount <= 0 {
+		return 0
+	}
+	return int(float64(amount/p.EarnPer) * tier.Multiplier)
+}
+
+// Redeem: potongan dari menukar paling banyak points poin untuk total.
+// Poin yang dipakai tidak lebih dari yang dibutuhkan untuk menutup total.
+func (p *Program) Redeem(points, total int) (used, discount int) {
+	if points <= 0 || total <= 0 {
+		return 0, 0
+	}
+	used = min(points, total/p.PointValue)
+	return used, used * p.PointValue
+}
@@ -0,0 +1,303 @@
+// Package media memvalidasi dan menyimpan gambar upload (poster, backdrop, avatar)
+// beserta variannya (thumbnail, medium, original).
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cristian-yw/Weekly10/internal/storage"
+	"github.com/cristian-yw/Weekly10/internal/tmdb"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type, use jpeg, png or gif")
+	ErrTooLarge        = errors.New("image is too large")
+)
+
+const (
+	defaultMaxBytes = 5 << 20 // 5 MB
+	// defaultMaxPixels: lebar x tinggi maksimal (40 MP). File kecil bisa berisi gambar raksasa
+	// yang memakan memori besar saat di-decode (decompression bomb).
+	defaultMaxPixels = 40_000_000
+)
+
+// lebar tiap varian, 0 = ukuran asli
+var variantWidths = map[string]int{
+	"thumbnail": 200,
+	"medium":    640,
+	"original":  0,
+}
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Image: hasil simpan satu gambar. Key = key varian original di storage.
+type Image struct {
+	Key         string            `json:"key"`
+	URL         string            `json:"url"`
+	Variants    map[string]string `json:"variants"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`
+	Width       int               `json:"width"`
+	Height      int               `json:"height"`
+}
+
+type Store struct {
+	Backend   storage.Storage
+	MaxBytes  int64
+	MaxPixels int64 // 0 = defaultMaxPixels
+
+	// dipakai ImageURL untuk membuat URL absolut
+	PublicBaseURL    string // mis. "https://api.example.com"; kosong = host dari request
+	TMDBImageBaseURL string
+	PosterSize       string // ukuran TMDB, mis. "w500"
+	BackdropSize     string
+}
+
+// NewStoreFromEnv memakai backend dari storage.NewFromEnv dan membaca UPLOAD_MAX_BYTES,
+// UPLOAD_MAX_PIXELS, PUBLIC_BASE_URL, TMDB_IMAGE_BASE_URL, TMDB_IMAGE_SIZE dan TMDB_BACKDROP_SIZE
+func NewStoreFromEnv() *Store {
+	backend, err := storage.NewFromEnv()
+	if err != nil {
+		log.Fatalf("storage config error: %v", err)
+	}
+	maxBytes, _ := strconv.ParseInt(os.Getenv("UPLOAD_MAX_BYTES"), 10, 64)
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+	maxPixels, _ := strconv.ParseInt(os.Getenv("UPLOAD_MAX_PIXELS"), 10, 64)
+	return &Store{
+		Backend:          backend,
+		MaxBytes:         maxBytes,
+		MaxPixels:        maxPixels,
+		PublicBaseURL:    strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"),
+		TMDBImageBaseURL: envOr("TMDB_IMAGE_BASE_URL", tmdb.DefaultImageBaseURL),
+		PosterSize:       envOr("TMDB_IMAGE_SIZE", "w500"),
+		BackdropSize:     envOr("TMDB_BACKDROP_SIZE", "w1280"),
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return strings.TrimRight(v, "/")
+	}
+	return fallback
+}
+
+// SaveUpload memvalidasi file multipart lalu menyimpannya di folder kind (posters, backdrops, avatars)
+func (s *Store) SaveUpload(ctx context.Context, fh *multipart.FileHeader, kind string) (*Image, error) {
+	if fh.Size > s.MaxBytes {
+		return nil, ErrTooLarge
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return s.Save(ctx, f, kind)
+}
+
+// Save membaca gambar dari r, lalu menyimpan varian original, medium dan thumbnail
+// dengan nama berdasarkan hash isi file. Key (yang disimpan di DB) menunjuk ke varian original.
+func (s *Store) Save(ctx context.Context, r io.Reader, kind string) (*Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := extensions[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	// ukuran dicek dari header sebelum decode penuh
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+	maxPixels := s.MaxPixels
+	if maxPixels <= 0 {
+		maxPixels = defaultMaxPixels
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d exceeds %d pixels", ErrTooLarge, cfg.Width, cfg.Height, maxPixels)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:16]) + ext
+
+	img := &Image{
+		Key:         path.Join(kind, "original", name),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Width:       src.Bounds().Dx(),
+		Height:      src.Bounds().Dy(),
+		Variants:    map[string]string{},
+	}
+
+	for variant, width := range variantWidths {
+		body := data
+		if width > 0 && img.Width > width {
+			if body, err = encode(resizeToWidth(src, width), contentType); err != nil {
+				return nil, err
+			}
+		}
+		key := variantKey(img.Key, variant)
+		if err := s.Backend.Put(ctx, key, bytes.NewReader(body), contentType); err != nil {
+			return nil, err
+		}
+		if img.Variants[variant], err = s.Backend.URL(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+	img.URL = img.Variants["original"]
+
+	return img, nil
+}
+
+// legacyUpload: nama file upload admin sebelum ada media.Store, "<unix>_<nama>" (poster)
+// atau "/<unix>_<nama>" (backdrop). Filenya di-serve dari /uploads (pindahkan ke UPLOAD_DIR).
+var legacyUpload = regexp.MustCompile(`^/?[0-9]{10}_[^/]+$`)
+
+// IsKey: true jika ref adalah key storage hasil Save, bukan URL absolut
+// atau path lama (mis. "/uploads/x.jpg", "1700000000_x.jpg" atau path TMDB "/abc.jpg")
+func IsKey(ref string) bool {
+	return ref != "" && !strings.HasPrefix(ref, "/") && !strings.Contains(ref, "://") && !legacyUpload.MatchString(ref)
+}
+
+// URL mengubah nilai dari DB menjadi URL yang bisa dipakai client.
+// variant kosong = original. Nilai yang bukan key dikembalikan apa adanya.
+func (s *Store) URL(ctx context.Context, ref, variant string) string {
+	if !IsKey(ref) {
+		return ref
+	}
+	if variant == "" {
+		variant = "original"
+	}
+	u, err := s.Backend.URL(ctx, variantKey(ref, variant))
+	if err != nil {
+		log.Printf("media url %s: %v", ref, err)
+		return ""
+	}
+	return u
+}
+
+// PosterURL dan BackdropURL menormalkan poster_path/backdrop_path dari DB
+// menjadi URL absolut dengan ukuran yang dikonfigurasi. base dipakai jika
+// PublicBaseURL kosong (biasanya scheme://host dari request).
+func (s *Store) PosterURL(ctx context.Context, base, ref string) string {
+	return s.ImageURL(ctx, base, ref, s.PosterSize)
+}
+
+func (s *Store) BackdropURL(ctx context.Context, base, ref string) string {
+	return s.ImageURL(ctx, base, ref, s.BackdropSize)
+}
+
+// ImageURL mendukung semua asal gambar:
+//   - URL absolut (http/https) dikembalikan apa adanya
+//   - "/uploads/..." (upload lama) di-prefix base
+//   - "<unix>_<nama>" / "/<unix>_<nama>" (upload admin lama) menjadi "/uploads/<unix>_<nama>"
+//   - "/abc.jpg" (path relatif TMDB) diarahkan ke CDN TMDB dengan ukuran size
+//   - key storage diarahkan ke varian yang paling dekat dengan size
+func (s *Store) ImageURL(ctx context.Context, base, ref, size string) string {
+	var u string
+	switch {
+	case ref == "":
+		return ""
+	case strings.Contains(ref, "://"):
+		return ref
+	case strings.HasPrefix(ref, "/uploads/"):
+		u = ref
+	case legacyUpload.MatchString(ref):
+		u = "/uploads/" + strings.TrimPrefix(ref, "/")
+	case strings.HasPrefix(ref, "/"):
+		return s.TMDBImageBaseURL + "/" + size + ref
+	default:
+		u = s.URL(ctx, ref, variantForSize(size))
+	}
+
+	if strings.HasPrefix(u, "/") {
+		if s.PublicBaseURL != "" {
+			base = s.PublicBaseURL
+		}
+		u = strings.TrimRight(base, "/") + u
+	}
+	return u
+}
+
+// variantForSize: "w185" -> thumbnail, "w500" -> medium, "w1280"/"original" -> original
+func variantForSize(size string) string {
+	width, err := strconv.Atoi(strings.TrimPrefix(size, "w"))
+	switch {
+	case err != nil || width > variantWidths["medium"]:
+		return "original"
+	case width > variantWidths["thumbnail"]:
+		return "medium"
+	default:
+		return "thumbnail"
+	}
+}
+
+// Delete menghapus semua varian dari key. Nilai yang bukan key diabaikan.
+func (s *Store) Delete(ctx context.Context, ref string) error {
+	if !IsKey(ref) {
+		return nil
+	}
+	for variant := range variantWidths {
+		if err := s.Backend.Delete(ctx, variantKey(ref, variant)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// variantKey: "posters/original/x.jpg" -> "posters/<variant>/x.jpg"
+func variantKey(key, variant string) string {
+	dir, name := path.Split(key)
+	return path.Join(path.Dir(path.Clean(dir)), variant, name)
+}
+
+func encode(img image.Image, contentType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch contentType {
+	case "image/png":
+		err = png.Encode(&buf, img)
+	case "image/gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85})
+	}
+	return buf.Bytes(), err
+}
@@ -0,0 +1,101 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/cristian-yw/Weekly10/internal/storage"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// withDimensions menulis ulang ukuran di chunk IHDR (beserta CRC-nya) tanpa mengubah data piksel
+func withDimensions(data []byte, w, h uint32) []byte {
+	out := bytes.Clone(data)
+	// signature (8) + length (4) + "IHDR" (4), lalu width dan height
+	binary.BigEndian.PutUint32(out[16:], w)
+	binary.BigEndian.PutUint32(out[20:], h)
+	binary.BigEndian.PutUint32(out[29:], crc32.ChecksumIEEE(out[12:29]))
+	return out
+}
+
+func TestSavePixelLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		maxPixels int64
+		wantErr   error
+	}{
+		{"within limit", encodePNG(t, 50, 40), 2000, nil},
+		{"over limit", encodePNG(t, 50, 41), 2000, ErrTooLarge},
+		{"forged header over default limit", withDimensions(encodePNG(t, 1, 1), 100000, 100000), 0, ErrTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &Store{Backend: storage.NewLocal(t.TempDir(), "/uploads"), MaxBytes: defaultMaxBytes, MaxPixels: tt.maxPixels}
+			img, err := store.Save(context.Background(), bytes.NewReader(tt.data), "posters")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (img.Width != 50 || img.Height != 40) {
+				t.Fatalf("size = %dx%d", img.Width, img.Height)
+			}
+		})
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	store := &Store{Backend: storage.NewLocal(t.TempDir(), "/uploads"), TMDBImageBaseURL: "https://image.tmdb.org/t/p"}
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{"empty", "", ""},
+		{"absolute url", "https://cdn.example.com/a.jpg", "https://cdn.example.com/a.jpg"},
+		{"uploads path", "/uploads/avatar_1.jpg", "http://api.test/uploads/avatar_1.jpg"},
+		{"legacy poster", "1700000000_poster.jpg", "http://api.test/uploads/1700000000_poster.jpg"},
+		{"legacy backdrop", "/1700000000_backdrop one.jpg", "http://api.test/uploads/1700000000_backdrop one.jpg"},
+		{"tmdb path", "/abc123.jpg", "https://image.tmdb.org/t/p/w500/abc123.jpg"},
+		{"tmdb path with digits", "/1700000000.jpg", "https://image.tmdb.org/t/p/w500/1700000000.jpg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := store.ImageURL(context.Background(), "http://api.test", tt.ref, "w500"); got != tt.want {
+				t.Fatalf("ImageURL(%q) = %q, want %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsKey(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want bool
+	}{
+		{"posters/ab/abcdef", true},
+		{"", false},
+		{"/uploads/x.jpg", false},
+		{"/abc.jpg", false},
+		{"https://cdn.example.com/a.jpg", false},
+		{"1700000000_poster.jpg", false},
+		{"/1700000000_backdrop.jpg", false},
+	}
+	for _, tt := range tests {
+		if got := IsKey(tt.ref); got != tt.want {
+			t.Errorf("IsKey(%q) = %v, want %v", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package media
+
+import (
+	"image"
+	"image/color"
+)
+
+// resizeToWidth mengecilkan gambar ke lebar tertentu (tinggi proporsional) dengan
+// rata-rata area (box filter). Gambar yang sudah lebih kecil dikembalikan apa adanya.
+func resizeToWidth(src image.Image, width int) image.Image {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	if width <= 0 || sw <= width {
+		return src
+	}
+	height := max(1, sh*width/sw)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := b.Min.Y + y*sh/height
+		y1 := max(y0+1, b.Min.Y+(y+1)*sh/height)
+		for x := 0; x < width; x++ {
+			x0 := b.Min.X + x*sw/width
+			x1 := max(x0+1, b.Min.X+(x+1)*sw/width)
+
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					bl += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(bl / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -105,3 +105,16 @@ func UserOnly() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// StaffOnly: staff loket / concession, admin juga boleh
+func StaffOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists || (role.(string) != "staff" && role.(string) != "admin") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Staff only"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SeatStreamTokenTTL: token hanya diperiksa saat koneksi dibuka, jadi cukup pendek
+const SeatStreamTokenTTL = 2 * time.Minute
+
+// kunci terpisah supaya token stream tidak bisa dipakai sebagai Bearer token biasa
+var streamKey = append([]byte("seat-stream:"), jwtKey...)
+
+// GenerateSeatStreamToken: token untuk query ?token= di GET /orders/seats/{scheduleId}/stream,
+// karena EventSource tidak bisa mengirim header Authorization.
+func GenerateSeatStreamToken(userID, scheduleID int) (string, time.Time, error) {
+	expiresAt := time.Now().Add(SeatStreamTokenTTL)
+	claims := jwt.RegisteredClaims{
+		Subject:   strconv.Itoa(userID),
+		Audience:  jwt.ClaimStrings{"seats:" + strconv.Itoa(scheduleID)},
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(streamKey)
+	return token, expiresAt, err
+}
+
+// SeatStreamAuth: cek token dari GenerateSeatStreamToken untuk jadwal di path
+func SeatStreamAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := c.Query("token")
+		if tokenString == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing token"})
+			c.Abort()
+			return
+		}
+
+		claims := &jwt.RegisteredClaims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+			return streamKey, nil
+		}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}), jwt.WithAudience("seats:"+c.Param("scheduleId")))
+		if err != nil || !token.Valid {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token invalid"})
+			c.Abort()
+			return
+		}
+		userID, err := strconv.Atoi(claims.Subject)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid claims"})
+			c.Abort()
+			return
+		}
+
+		c.Set("userID", userID)
+		c.Next()
+	}
+}
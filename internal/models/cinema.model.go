@@ -0,0 +1,64 @@
+package models
+
+// CinemaRequest dipakai admin untuk membuat cinema baru
+type CinemaRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Address   string `json:"address"`
+	LogoURL   string `json:"logo_url"`
+	PriceTier string `json:"price_tier" binding:"omitempty,oneof=regular premium vip"`
+}
+
+// CinemaUpdate: hanya field yang dikirim yang akan di-update
+type CinemaUpdate struct {
+	Name      *string `json:"name"`
+	Address   *string `json:"address"`
+	LogoURL   *string `json:"logo_url"`
+	PriceTier *string `json:"price_tier" binding:"omitempty,oneof=regular premium vip"`
+}
+
+type LocationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type ShowTimeRequest struct {
+	StartTime string `json:"start_time" binding:"required" example:"19:30"`
+}
+
+// SeatInventoryRequest: kirim seat_codes langsung, atau rows + seats_per_row
+// untuk generate A1..A10, B1..B10, dst. SeatType berlaku untuk semua kursi di request;
+// kosong = kursi baru "standard", kursi yang sudah ada tidak berubah.
+// Batas ukuran: maksimal 500 seat_codes dan 30 rows x 60 seats_per_row per request.
+type SeatInventoryRequest struct {
+	SeatCodes   []string `json:"seat_codes" binding:"omitempty,max=500,dive,max=10"`
+	Rows        []string `json:"rows" binding:"omitempty,max=30,dive,max=3" example:"A,B,C"`
+	SeatsPerRow int      `json:"seats_per_row" binding:"min=0,max=60"`
+	SeatType    string   `json:"seat_type" binding:"omitempty,oneof=standard wheelchair couple" example:"standard"`
+}
+
+type SeatInventory struct {
+	ID           int    `json:"id"`
+	CinemaID     int    `json:"cinema_id"`
+	AuditoriumID int    `json:"auditorium_id"`
+	SeatCode     string `json:"seat_code"`
+	SeatType     string `json:"seat_type"`
+	IsActive     bool   `json:"is_active"`
+}
+
+// Auditorium = studio di dalam cinema
+type Auditorium struct {
+	ID       int    `json:"id"`
+	CinemaID int    `json:"cinema_id"`
+	Name     string `json:"name"`
+	Format   string `json:"format"`
+	IsActive bool   `json:"is_active"`
+}
+
+type AuditoriumRequest struct {
+	Name   string `json:"name" binding:"required" example:"Studio 1"`
+	Format string `json:"format" binding:"omitempty,oneof=2D 3D IMAX 4DX" example:"2D"`
+}
+
+type AuditoriumUpdate struct {
+	Name   *string `json:"name"`
+	Format *string `json:"format" binding:"omitempty,oneof=2D 3D IMAX 4DX"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+type ConcessionItem struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category"` // food | drink | combo
+	ImageURL    string `json:"image_url"`
+	IsActive    bool   `json:"is_active"`
+}
+
+type ConcessionItemRequest struct {
+	Name        string `json:"name" binding:"required,max=100" example:"Popcorn Combo"`
+	Description string `json:"description"`
+	Category    string `json:"category" binding:"omitempty,oneof=food drink combo"`
+	ImageURL    string `json:"image_url"`
+}
+
+// ConcessionItemUpdate: hanya field yang dikirim yang akan di-update
+type ConcessionItemUpdate struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description"`
+	Category    *string `json:"category" binding:"omitempty,oneof=food drink combo"`
+	ImageURL    *string `json:"image_url"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// CinemaConcession: item di menu satu cinema. Stock nil = tidak dibatasi.
+type CinemaConcession struct {
+	ConcessionItem
+	CinemaID    int  `json:"cinema_id"`
+	Price       int  `json:"price"`
+	Stock       *int `json:"stock"`
+	IsAvailable bool `json:"is_available"`
+}
+
+type CinemaConcessionRequest struct {
+	Price       int   `json:"price" binding:"min=0" example:"55000"`
+	Stock       *int  `json:"stock" binding:"omitempty,min=0"`
+	IsAvailable *bool `json:"is_available"`
+}
+
+// OrderItemRequest: add-on yang dipesan bersama tiket
+type OrderItemRequest struct {
+	ItemID   int `json:"item_id" binding:"required"`
+	Quantity int `json:"quantity" binding:"required,min=1,max=20"`
+}
+
+// OrderItem: add-on di order. Status: pending | fulfilled | cancelled
+type OrderItem struct {
+	ID          int        `json:"id,omitempty"`
+	ItemID      int        `json:"item_id"`
+	Name        string     `json:"name"`
+	UnitPrice   int        `json:"unit_price"`
+	Quantity    int        `json:"quantity"`
+	Total       int        `json:"total"`
+	Status      string     `json:"status,omitempty"`
+	FulfilledAt *time.Time `json:"fulfilled_at,omitempty"`
+}
+
+// PickupOrder: tampilan staff saat pelanggan mengambil add-on
+type PickupOrder struct {
+	OrderID    int         `json:"order_id"`
+	UserID     int         `json:"user_id"`
+	MovieTitle string      `json:"movie_title"`
+	CinemaID   int         `json:"cinema_id"`
+	Status     string      `json:"status"`
+	Items      []OrderItem `json:"items"`
+}
+
+// PickupRequest: item_ids kosong = semua item yang masih pending
+type PickupRequest struct {
+	ItemIDs []int `json:"item_ids"`
+}
+
+type UserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user staff admin"`
+}
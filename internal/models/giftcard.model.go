@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+type GiftCard struct {
+	ID              int             `json:"id"`
+	Code            string          `json:"code"`
+	InitialAmount   int             `json:"initial_amount"`
+	Balance         int             `json:"balance"`
+	Status          string          `json:"status"` // active | voided
+	Source          string          `json:"source"` // admin | purchase
+	PurchaseOrderID *int            `json:"purchase_order_id,omitempty"`
+	RecipientEmail  string          `json:"recipient_email"`
+	Message         string          `json:"message"`
+	ExpiresAt       *time.Time      `json:"expires_at"`
+	VoidReason      string          `json:"void_reason,omitempty"`
+	CreatedAt       time.Time       `json:"created_at"`
+	VoidedAt        *time.Time      `json:"voided_at,omitempty"`
+	Ledger          []GiftCardEntry `json:"ledger,omitempty"`
+}
+
+// GiftCardEntry: satu perubahan saldo. Kind: issue | redeem | refund | void
+type GiftCardEntry struct {
+	ID        int       `json:"id"`
+	OrderID   *int      `json:"order_id"`
+	Kind      string    `json:"kind"`
+	Amount    int       `json:"amount"`
+	CreatedBy *int      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// GiftCardBalance dipakai user untuk cek saldo tanpa melihat riwayat
+type GiftCardBalance struct {
+	Code      string     `json:"code"` // disamarkan
+	Balance   int        `json:"balance"`
+	Status    string     `json:"status"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// GiftCardPurchase: gift card yang dibeli bersama order
+type GiftCardPurchase struct {
+	Amount         int    `json:"amount" binding:"required,min=10000,max=10000000" example:"100000"`
+	RecipientEmail string `json:"recipient_email" binding:"omitempty,email"`
+	Message        string `json:"message" binding:"max=500"`
+}
+
+// GiftCardIssueRequest dipakai admin
+type GiftCardIssueRequest struct {
+	GiftCardPurchase
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type GiftCardVoidRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
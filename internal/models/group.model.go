@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// GroupBookingRequest: organiser menahan kursi untuk teman-temannya.
+// DeadlineMinutes default GROUP_BOOKING_DEADLINE, tidak pernah melewati jam tayang.
+type GroupBookingRequest struct {
+	ScheduleID      int      `json:"schedule_id" binding:"required"`
+	Seats           []string `json:"seats" binding:"required,min=2,max=10"`
+	DeadlineMinutes int      `json:"deadline_minutes" binding:"omitempty,min=5,max=4320" example:"120"`
+}
+
+// GroupBooking: status group: open | completed (semua kursi dibayar) | closed (lewat deadline) | cancelled
+type GroupBooking struct {
+	ID          int         `json:"id"`
+	Token       string      `json:"token,omitempty"`
+	InviteURL   string      `json:"invite_url,omitempty"`
+	OrganiserID int         `json:"organiser_id"`
+	ScheduleID  int         `json:"schedule_id"`
+	MovieTitle  string      `json:"movie_title"`
+	Status      string      `json:"status"`
+	Deadline    time.Time   `json:"deadline"`
+	CreatedAt   time.Time   `json:"created_at"`
+	Seats       []GroupSeat `json:"seats"`
+}
+
+// GroupSeat: status kursi: open | claimed | paid | released. Mine = diklaim user yang melihat.
+type GroupSeat struct {
+	Seat      string     `json:"seat"`
+	Status    string     `json:"status"`
+	Mine      bool       `json:"mine"`
+	ClaimedAt *time.Time `json:"claimed_at,omitempty"`
+	OrderID   *int       `json:"order_id,omitempty"`
+}
+
+type GroupClaimRequest struct {
+	Seat string `json:"seat" binding:"required" example:"C5"`
+}
+
+// GroupPaymentRequest: anggota membayar kursi yang sudah diklaim dengan order sendiri
+type GroupPaymentRequest struct {
+	TicketType    string             `json:"ticket_type" example:"student"` // default adult
+	Items         []OrderItemRequest `json:"items" binding:"omitempty,max=20,dive"`
+	PromoCode     string             `json:"promo_code"`
+	RedeemPoints  int                `json:"redeem_points" binding:"omitempty,min=0"`
+	GiftCardCode  string             `json:"gift_card_code"`
+	PaymentMethod string             `json:"payment_method" binding:"omitempty,max=30"`
+}
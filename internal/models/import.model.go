@@ -0,0 +1,48 @@
+package models
+
+// ImportMovie: satu movie dari file import (CSV atau JSON). tmdb_id wajib
+// karena dipakai sebagai kunci idempotensi.
+type ImportMovie struct {
+	Row          int              `json:"-"` // baris (CSV) atau index (JSON) pertama movie ini
+	TMDBID       int              `json:"tmdb_id"`
+	Title        string           `json:"title"`
+	Overview     string           `json:"overview"`
+	ReleaseDate  string           `json:"release_date"`
+	Runtime      int              `json:"runtime"`
+	PosterPath   string           `json:"poster_path"`
+	BackdropPath string           `json:"backdrop_path"`
+	Genres       []string         `json:"genres"`
+	Director     *PersonRequest   `json:"director"`
+	Schedules    []ImportSchedule `json:"schedules"`
+}
+
+type ImportSchedule struct {
+	Row int `json:"-"`
+	ScheduleRequest
+}
+
+// ImportError: error validasi per baris. Field kosong = error untuk seluruh baris.
+type ImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportReport struct {
+	DryRun           bool          `json:"dry_run"`
+	Applied          bool          `json:"applied"`
+	Movies           int           `json:"movies"`
+	MoviesCreated    int           `json:"movies_created"`
+	MoviesUpdated    int           `json:"movies_updated"`
+	Schedules        int           `json:"schedules"`
+	SchedulesCreated int           `json:"schedules_created"`
+	SchedulesSkipped int           `json:"schedules_skipped"` // sudah ada (re-import)
+	Errors           []ImportError `json:"errors"`
+}
+
+// ExportFilter: from/to (YYYY-MM-DD, inklusif) dan cinema_id opsional
+type ExportFilter struct {
+	From     *string
+	To       *string
+	CinemaID int
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// LoyaltySummary ditampilkan di profile
+type LoyaltySummary struct {
+	PointsBalance int    `json:"points_balance"`
+	Tier          string `json:"tier"`
+	RollingSpend  int    `json:"rolling_spend"`
+	NextTier      string `json:"next_tier,omitempty"`
+	// belanja yang masih dibutuhkan untuk NextTier
+	NextTierSpend int `json:"next_tier_spend,omitempty"`
+}
+
+// PointEntry: satu baris ledger poin. Kind: earn | redeem | reverse | refund
+type PointEntry struct {
+	ID          int       `json:"id"`
+	OrderID     *int      `json:"order_id"`
+	Kind        string    `json:"kind"`
+	Points      int       `json:"points"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PaymentWebhook dikirim payment gateway. Status: paid | refunded | failed
+type PaymentWebhook struct {
+	EventID string `json:"event_id" binding:"required,max=100"`
+	OrderID int    `json:"order_id" binding:"required"`
+	Status  string `json:"status" binding:"required,oneof=paid refunded failed"`
+	Amount  int    `json:"amount"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification: satu pesan di outbox. Status pending | sent | failed (gagal permanen / melewati batas retry).
+type Notification struct {
+	ID            int64           `json:"id"`
+	UserID        int             `json:"user_id"`
+	Template      string          `json:"template" example:"order_created"`
+	Language      string          `json:"language" example:"en"`
+	Recipient     string          `json:"recipient"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	Status        string          `json:"status"`
+	Channel       string          `json:"channel,omitempty"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	SentAt        *time.Time      `json:"sent_at,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// OrderNotification: data order untuk template order_created (dan pengingat jam tayang)
+type OrderNotification struct {
+	OrderID    int      `json:"order_id"`
+	MovieTitle string   `json:"movie_title"`
+	Cinema     string   `json:"cinema"`
+	Date       string   `json:"date"`
+	StartTime  string   `json:"start_time"`
+	Seats      []string `json:"seats"`
+	Tickets    []Ticket `json:"tickets"`
+	Total      int      `json:"total"`
+}
+
+// WaitlistOfferNotification: data untuk template waitlist_offer
+type WaitlistOfferNotification struct {
+	ScheduleID int       `json:"schedule_id"`
+	MovieTitle string    `json:"movie_title"`
+	Cinema     string    `json:"cinema"`
+	Date       string    `json:"date"`
+	StartTime  string    `json:"start_time"`
+	Seats      []string  `json:"seats"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// PasswordChangedNotification: data untuk template password_changed
+type PasswordChangedNotification struct {
+	ChangedAt time.Time `json:"changed_at"`
+}
@@ -6,6 +6,8 @@ type Schedule struct {
 	ID         int    `json:"id"`
 	MovieID    int    `json:"movie_id"`
 	Cinema     string `json:"cinema"`
+	Auditorium string `json:"auditorium"`
+	Format     string `json:"format"`
 	MovieTitle string `json:"movie_title"`
 	Location   string `json:"location"`
 	StartTime  string `json:"start_time"`
@@ -13,10 +15,19 @@ type Schedule struct {
 }
 
 type Seat struct {
-	ID       int    `json:"id"`
-	CinemaID int    `json:"cinema_id"`
-	SeatCode string `json:"seat_code"`
-	IsBooked bool   `json:"is_booked"`
+	ID           int    `json:"id"`
+	CinemaID     int    `json:"cinema_id"`
+	AuditoriumID int    `json:"auditorium_id"`
+	SeatCode     string `json:"seat_code"`
+	IsBooked     bool   `json:"is_booked"`
+}
+
+// SeatSuggestion: blok kursi bersebelahan terbaik untuk satu jadwal. Score makin kecil makin baik.
+type SeatSuggestion struct {
+	ScheduleID int      `json:"schedule_id"`
+	Row        string   `json:"row" example:"E"`
+	Seats      []string `json:"seats" example:"E5,E6"`
+	Score      float64  `json:"score"`
 }
 
 type MovieDetail struct {
@@ -30,23 +41,52 @@ type MovieDetail struct {
 	Director     string     `json:"director"`
 	PosterPath   string     `json:"poster_path"`
 	BackdropPath string     `json:"backdrop_path"`
+	PosterURL    string     `json:"poster_url"`
+	BackdropURL  string     `json:"backdrop_url"`
 	Schedules    []Schedule `json:"schedules"`
 }
 
 type Order struct {
-	ID         int       `json:"id"`
-	UserID     int       `json:"user_id"`
-	ScheduleID int       `json:"schedule_id"`
-	TotalPrice int       `json:"total_price"`
-	Status     string    `json:"status"`
-	OrderDate  time.Time `json:"order_date"`
-	Seats      []string  `json:"seats"`
+	ID             int         `json:"id"`
+	UserID         int         `json:"user_id"`
+	ScheduleID     int         `json:"schedule_id"`
+	Subtotal       int         `json:"subtotal"`
+	Discount       int         `json:"discount"`
+	PromoCode      string      `json:"promo_code,omitempty"`
+	PointsRedeemed int         `json:"points_redeemed"`
+	PointsDiscount int         `json:"points_discount"`
+	PointsEarned   int         `json:"points_earned"`
+	TotalPrice     int         `json:"total_price"`
+	GiftCardAmount int         `json:"gift_card_amount"`
+	AmountDue      int         `json:"amount_due"`
+	Status         string      `json:"status"`
+	OrderDate      time.Time   `json:"order_date"`
+	Seats          []string    `json:"seats"`
+	Tickets        []Ticket    `json:"tickets"`
+	Items          []OrderItem `json:"items"`
+	ItemsTotal     int         `json:"items_total"`
+	GiftCards      []GiftCard  `json:"gift_cards,omitempty"` // gift card yang dibeli
 }
 
 // Schedule2 dipakai untuk input request Add Movie (jadwal baru)
 type Schedule2 struct {
-	CinemaID   int    `json:"cinema_id"`
-	LocationID int    `json:"location_id"`
-	TimeID     int    `json:"time_id"`
-	Date       string `json:"date"`
+	CinemaID     int    `json:"cinema_id"`
+	AuditoriumID int    `json:"auditorium_id"`
+	LocationID   int    `json:"location_id"`
+	TimeID       int    `json:"time_id"`
+	Date         string `json:"date"`
+}
+
+// SeatEvent dikirim ke semua client yang memantau satu jadwal.
+// Type: snapshot | held | released | booked
+type SeatEvent struct {
+	ScheduleID int       `json:"schedule_id"`
+	Type       string    `json:"type"`
+	Seats      []string  `json:"seats"`
+	Held       []string  `json:"held,omitempty"` // hanya untuk snapshot
+	At         time.Time `json:"at"`
+}
+
+type SeatHoldRequest struct {
+	Seats []string `json:"seats" binding:"required,min=1"`
 }
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// PricingRule: aturan harga dinamis. Kondisi yang kosong / nil tidak membatasi.
+// AdjustmentType: percent (AdjustmentValue persen, boleh negatif), fixed (tambah / kurang Rp)
+// atau set (harga menjadi AdjustmentValue).
+type PricingRule struct {
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Description     string    `json:"description"`
+	Priority        int       `json:"priority"`
+	DaysOfWeek      []int     `json:"days_of_week"` // 0 = Minggu
+	StartFrom       *string   `json:"start_from"`   // HH:MM
+	StartUntil      *string   `json:"start_until"`  // HH:MM, eksklusif
+	PremiereDays    *int      `json:"premiere_days"`
+	MinOccupancy    *int      `json:"min_occupancy"`
+	MaxOccupancy    *int      `json:"max_occupancy"`
+	MovieIDs        []int     `json:"movie_ids"`
+	CinemaIDs       []int     `json:"cinema_ids"`
+	AdjustmentType  string    `json:"adjustment_type"`
+	AdjustmentValue int       `json:"adjustment_value"`
+	StopProcessing  bool      `json:"stop_processing"`
+	IsActive        bool      `json:"is_active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// PricingRuleRequest dipakai untuk create dan update (PUT mengganti seluruh pengaturan)
+type PricingRuleRequest struct {
+	Name            string  `json:"name" binding:"required,max=100" example:"Weekend prime time"`
+	Description     string  `json:"description"`
+	Priority        int     `json:"priority" example:"100"`
+	DaysOfWeek      []int   `json:"days_of_week" binding:"omitempty,dive,min=0,max=6" example:"0,6"`
+	StartFrom       *string `json:"start_from" example:"18:00"`
+	StartUntil      *string `json:"start_until" example:"22:00"`
+	PremiereDays    *int    `json:"premiere_days" binding:"omitempty,min=1"`
+	MinOccupancy    *int    `json:"min_occupancy" binding:"omitempty,min=0,max=100"`
+	MaxOccupancy    *int    `json:"max_occupancy" binding:"omitempty,min=0,max=100"`
+	MovieIDs        []int   `json:"movie_ids"`
+	CinemaIDs       []int   `json:"cinema_ids"`
+	AdjustmentType  string  `json:"adjustment_type" binding:"required,oneof=percent fixed set" example:"percent"`
+	AdjustmentValue int     `json:"adjustment_value" example:"20"`
+	StopProcessing  bool    `json:"stop_processing"`
+	IsActive        *bool   `json:"is_active"`
+}
+
+// PriceRuleResult: hasil evaluasi satu rule. Reason diisi jika rule tidak berlaku.
+type PriceRuleResult struct {
+	RuleID      int    `json:"rule_id"`
+	Name        string `json:"name"`
+	Priority    int    `json:"priority"`
+	Adjustment  string `json:"adjustment"` // contoh: +20%, -5000, =45000
+	Applied     bool   `json:"applied"`
+	Reason      string `json:"reason,omitempty"`
+	PriceBefore int    `json:"price_before,omitempty"`
+	PriceAfter  int    `json:"price_after,omitempty"`
+}
+
+// PriceBreakdown: harga jadwal setelah aturan harga beserta data yang dipakai untuk mengevaluasinya.
+// Disimpan di orders.pricing sehingga harga order bisa ditelusuri walaupun rule berubah.
+type PriceBreakdown struct {
+	ScheduleID       int               `json:"schedule_id"`
+	BasePrice        int               `json:"base_price"`
+	Price            int               `json:"price"`
+	ShowDate         string            `json:"show_date"`
+	Weekday          int               `json:"weekday"`
+	StartTime        string            `json:"start_time"`
+	DaysSinceRelease *int              `json:"days_since_release"`
+	OccupancyPct     int               `json:"occupancy_pct"`
+	Rules            []PriceRuleResult `json:"rules"`
+	EvaluatedAt      time.Time         `json:"evaluated_at"`
+}
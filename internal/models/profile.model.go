@@ -9,6 +9,8 @@ type UserProfile struct {
 	LastName  string `json:"last_name"`
 	Phone     string `json:"phone"`
 	AvatarURL string `json:"avatar_url"`
+
+	Loyalty *LoyaltySummary `json:"loyalty,omitempty"`
 }
 
 type UserProfileResponse struct {
@@ -33,6 +35,7 @@ type OrderHistory struct {
 	TotalPrice float64   `json:"total_price"`
 	Status     string    `json:"status"`
 	Date       time.Time `json:"date"`
+	Tickets    []Ticket  `json:"tickets"`
 }
 
 type EditProfileRequest struct {
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+type Promotion struct {
+	ID             int        `json:"id"`
+	Code           string     `json:"code"`
+	Description    string     `json:"description"`
+	DiscountType   string     `json:"discount_type"` // percent | fixed
+	DiscountValue  int        `json:"discount_value"`
+	MaxDiscount    *int       `json:"max_discount"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MaxUses        *int       `json:"max_uses"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user"`
+	MinSeats       int        `json:"min_seats"`
+	MovieIDs       []int      `json:"movie_ids"`
+	CinemaIDs      []int      `json:"cinema_ids"`
+	DaysOfWeek     []int      `json:"days_of_week"` // 0 = Minggu
+	UsedCount      int        `json:"used_count"`
+	IsActive       bool       `json:"is_active"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// PromotionRequest dipakai untuk create dan update (PUT mengganti seluruh pengaturan)
+type PromotionRequest struct {
+	Code           string     `json:"code" binding:"required,max=50" example:"WEEKEND20"`
+	Description    string     `json:"description"`
+	DiscountType   string     `json:"discount_type" binding:"required,oneof=percent fixed"`
+	DiscountValue  int        `json:"discount_value" binding:"required,min=1" example:"20"`
+	MaxDiscount    *int       `json:"max_discount" binding:"omitempty,min=1"`
+	ValidFrom      *time.Time `json:"valid_from"`
+	ValidUntil     *time.Time `json:"valid_until"`
+	MaxUses        *int       `json:"max_uses" binding:"omitempty,min=1"`
+	MaxUsesPerUser *int       `json:"max_uses_per_user" binding:"omitempty,min=1"`
+	MinSeats       int        `json:"min_seats" binding:"omitempty,min=1"`
+	MovieIDs       []int      `json:"movie_ids"`
+	CinemaIDs      []int      `json:"cinema_ids"`
+	DaysOfWeek     []int      `json:"days_of_week" binding:"omitempty,dive,min=0,max=6"`
+	IsActive       *bool      `json:"is_active"`
+}
+
+type PromotionRedemption struct {
+	ID          int       `json:"id"`
+	PromotionID int       `json:"promotion_id"`
+	OrderID     int       `json:"order_id"`
+	UserID      int       `json:"user_id"`
+	Discount    int       `json:"discount"`
+	RedeemedAt  time.Time `json:"redeemed_at"`
+}
+
+// QuoteRequest juga dipakai sebagai body CreateOrder.
+// GiftCardCode dipakai untuk membayar tiket; GiftCards = gift card baru yang ikut dibeli.
+type QuoteRequest struct {
+	ScheduleID    int                `json:"schedule_id" binding:"required"`
+	Seats         []string           `json:"seats" binding:"required,min=1"`
+	TicketTypes   map[string]string  `json:"ticket_types"`                          // kode kursi -> tipe tiket, default adult
+	Items         []OrderItemRequest `json:"items" binding:"omitempty,max=20,dive"` // add-on makanan / minuman
+	PromoCode     string             `json:"promo_code"`
+	RedeemPoints  int                `json:"redeem_points" binding:"omitempty,min=0"` // poin loyalty yang ingin ditukar
+	GiftCardCode  string             `json:"gift_card_code"`
+	GiftCards     []GiftCardPurchase `json:"gift_cards" binding:"omitempty,max=10,dive"`
+	PaymentMethod string             `json:"payment_method" binding:"omitempty,max=30" example:"gateway"` // untuk sisa pembayaran
+	// GroupBookingID diisi server saat anggota group booking membayar kursinya
+	GroupBookingID int `json:"-"`
+}
+
+// Quote: rincian harga yang dihitung server. Total = tiket setelah promo + ItemsTotal - PointsDiscount
+// + GiftCardsTotal, AmountDue = Total - GiftCardAmount. PointsEarned = perkiraan poin setelah order dibayar.
+type Quote struct {
+	ScheduleID     int               `json:"schedule_id"`
+	Seats          []string          `json:"seats"`
+	Tickets        []Ticket          `json:"tickets"`
+	UnitPrice      int               `json:"unit_price"` // harga jadwal setelah aturan harga, sebelum tipe tiket
+	PriceRules     []PriceRuleResult `json:"price_rules"`
+	Subtotal       int               `json:"subtotal"`
+	PromoCode      string            `json:"promo_code,omitempty"`
+	Discount       int               `json:"discount"`
+	Items          []OrderItem       `json:"items"`
+	ItemsTotal     int               `json:"items_total"`
+	PointsRedeemed int               `json:"points_redeemed"`
+	PointsDiscount int               `json:"points_discount"`
+	GiftCardsTotal int               `json:"gift_cards_total"`
+	Total          int               `json:"total"`
+	GiftCardCode   string            `json:"gift_card_code,omitempty"` // disamarkan
+	GiftCardAmount int               `json:"gift_card_amount"`
+	AmountDue      int               `json:"amount_due"`
+	PointsEarned   int               `json:"points_earned"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ReportParams: from/to inklusif (YYYY-MM-DD), granularity = day | week | month
+type ReportParams struct {
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Granularity string `json:"granularity"`
+	CinemaID    int    `json:"cinema_id,omitempty"`
+	By          string `json:"by,omitempty"`    // revenue: movie | cinema | location
+	Limit       int    `json:"limit,omitempty"` // top movies per periode
+}
+
+// Report: hasil agregasi. Columns menjaga urutan kolom untuk export CSV.
+type Report struct {
+	Name        string                   `json:"name"`
+	Params      ReportParams             `json:"params"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Columns     []string                 `json:"columns"`
+	Rows        []map[string]interface{} `json:"rows"`
+}
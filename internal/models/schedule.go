@@ -6,11 +6,22 @@ type Genre struct {
 }
 
 type Cinema struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	LogoURL   string `json:"logo_url"`
+	PriceTier string `json:"price_tier"`
+	IsActive  bool   `json:"is_active"`
 }
 
 type Location struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	IsActive bool   `json:"is_active"`
+}
+
+type ShowTime struct {
+	ID        int    `json:"id"`
+	StartTime string `json:"start_time"`
+	IsActive  bool   `json:"is_active"`
 }
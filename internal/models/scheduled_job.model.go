@@ -0,0 +1,26 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ScheduledJob: satu delayed job. Status pending | done | cancelled (tidak relevan lagi) |
+// expired (terlambat dieksekusi) | failed (melewati batas retry)
+type ScheduledJob struct {
+	ID        int64           `json:"id"`
+	Kind      string          `json:"kind"`
+	DedupKey  string          `json:"dedup_key"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	DueAt     time.Time       `json:"due_at"`
+	RunAt     time.Time       `json:"run_at"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+}
+
+// ShowtimeReminderJob: payload job showtime_reminder
+type ShowtimeReminderJob struct {
+	OrderID int    `json:"order_id"`
+	Before  string `json:"before" example:"24h0m0s"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// TicketType: kategori tiket (adult, child, student, senior, ...)
+type TicketType struct {
+	Code      string `json:"code"`
+	Name      string `json:"name"`
+	SortOrder int    `json:"sort_order"`
+	IsActive  bool   `json:"is_active"`
+}
+
+type TicketTypeRequest struct {
+	Name      string `json:"name" binding:"required,max=50" example:"Student"`
+	SortOrder int    `json:"sort_order"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+// TicketPriceRule: harga satu tipe tiket. ScheduleID lebih spesifik dari CinemaID,
+// keduanya nil = semua jadwal. Salah satu dari Price (harga tetap) atau Percent
+// (persen dari harga jadwal) diisi.
+type TicketPriceRule struct {
+	ID         int       `json:"id"`
+	TicketType string    `json:"ticket_type"`
+	CinemaID   *int      `json:"cinema_id"`
+	ScheduleID *int      `json:"schedule_id"`
+	Price      *int      `json:"price"`
+	Percent    *int      `json:"percent"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type TicketPriceRuleRequest struct {
+	TicketType string `json:"ticket_type" binding:"required" example:"child"`
+	CinemaID   *int   `json:"cinema_id"`
+	ScheduleID *int   `json:"schedule_id"`
+	Price      *int   `json:"price" binding:"omitempty,min=0"`
+	Percent    *int   `json:"percent" binding:"omitempty,min=1,max=1000" example:"75"`
+}
+
+// TicketPrice: harga tipe tiket untuk satu jadwal
+type TicketPrice struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+// Ticket: satu kursi di order beserta tipe dan harganya
+type Ticket struct {
+	Seat  string `json:"seat"`
+	Type  string `json:"type"`
+	Price int    `json:"price"`
+}
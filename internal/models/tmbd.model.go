@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type TMDBMovie struct {
 	ID           int      `json:"id"`
 	TMDBID       *int     `json:"tmdb_id"`
@@ -14,6 +16,8 @@ type TMDBMovie struct {
 	VoteCount    int      `json:"vote_count"`
 	PosterPath   string   `json:"poster_path"`
 	BackdropPath string   `json:"backdrop_path"`
+	PosterURL    string   `json:"poster_url"`
+	BackdropURL  string   `json:"backdrop_url"`
 }
 
 type TMDBGenre struct {
@@ -26,7 +30,79 @@ type TMDBGenreResponse struct {
 }
 
 type TMDBResponse struct {
-	Results []TMDBMovie `json:"results"`
+	Page         int         `json:"page"`
+	TotalPages   int         `json:"total_pages"`
+	TotalResults int         `json:"total_results"`
+	Results      []TMDBMovie `json:"results"`
+}
+
+// TMDBMovieDetail: response /movie/{id}?append_to_response=credits
+type TMDBMovieDetail struct {
+	ID           int         `json:"id"`
+	Title        string      `json:"title"`
+	Overview     string      `json:"overview"`
+	ReleaseDate  string      `json:"release_date"`
+	Runtime      int         `json:"runtime"`
+	Popularity   float64     `json:"popularity"`
+	VoteAverage  float64     `json:"vote_average"`
+	VoteCount    int         `json:"vote_count"`
+	PosterPath   string      `json:"poster_path"`
+	BackdropPath string      `json:"backdrop_path"`
+	Genres       []TMDBGenre `json:"genres"`
+	Credits      TMDBCredits `json:"credits"`
+}
+
+type TMDBCredits struct {
+	Cast []TMDBCast `json:"cast"`
+	Crew []TMDBCrew `json:"crew"`
+}
+
+type TMDBCast struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Character string `json:"character"`
+	Order     int    `json:"order"`
+}
+
+type TMDBCrew struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Job  string `json:"job"`
+}
+
+// SyncOptions: list = popular | upcoming | now_playing | top_rated
+type SyncOptions struct {
+	List      string `json:"list"`
+	Pages     int    `json:"pages"`
+	CastLimit int    `json:"cast_limit"`
+	// MirrorImages: unduh poster & backdrop ke media storage sendiri
+	MirrorImages bool `json:"mirror_images"`
+
+	// OnProgress (opsional) dipanggil setiap selesai memproses satu movie
+	OnProgress func(SyncResult) `json:"-"`
+}
+
+type SyncResult struct {
+	List      string   `json:"list"`
+	Pages     int      `json:"pages"`
+	Total     int      `json:"total"`
+	Processed int      `json:"processed"`
+	Created   int      `json:"created"`
+	Updated   int      `json:"updated"`
+	Failed    int      `json:"failed"`
+	Errors    []string `json:"errors"`
+}
+
+// SyncJob: status sync TMDB yang berjalan di background
+type SyncJob struct {
+	ID         string      `json:"id"`
+	Trigger    string      `json:"trigger"` // manual | schedule
+	Status     string      `json:"status"`  // running | succeeded | failed
+	Options    SyncOptions `json:"options"`
+	Result     SyncResult  `json:"result"`
+	Error      string      `json:"error,omitempty"`
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
 }
 
 // ============================
@@ -48,6 +124,18 @@ type NewMovieRequest struct {
 	Schedules    []ScheduleRequest `json:"schedules"`
 	Director     *PersonRequest    `json:"director"`
 	Casts        []PersonRequest   `json:"casts"`
+
+	// path gambar asli di TMDB, diisi oleh import supaya sync berikutnya tidak mengunduh ulang
+	TMDBPosterPath   string `json:"-"`
+	TMDBBackdropPath string `json:"-"`
+}
+
+// ImportMovieRequest: body opsional untuk import movie dari TMDB
+type ImportMovieRequest struct {
+	Schedules []ScheduleRequest `json:"schedules"`
+	CastLimit int               `json:"cast_limit"`
+	// MirrorImages: unduh poster & backdrop ke media storage sendiri (sama seperti sync)
+	MirrorImages bool `json:"mirror_images"`
 }
 
 type PersonRequest struct {
@@ -55,10 +143,12 @@ type PersonRequest struct {
 	Name   string `json:"name"`
 }
 
+// ScheduleRequest: auditorium_id opsional, jika kosong dipakai studio default cinema
 type ScheduleRequest struct {
-	CinemaID   int    `json:"cinema_id"`
-	LocationID int    `json:"location_id"`
-	TimeID     int    `json:"time_id"`
-	Date       string `json:"date"`
-	Price      int    `json:"price"`
+	CinemaID     int    `json:"cinema_id"`
+	AuditoriumID int    `json:"auditorium_id"`
+	LocationID   int    `json:"location_id"`
+	TimeID       int    `json:"time_id"`
+	Date         string `json:"date"`
+	Price        int    `json:"price"`
 }
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// WaitlistRequest: jumlah kursi yang ditunggu, default 1
+type WaitlistRequest struct {
+	Count int `json:"count" binding:"omitempty,min=1,max=10" example:"2"`
+}
+
+// WaitlistEntry: status waiting | offered | booked | expired | left.
+// Saat offered, OfferedSeats di-hold untuk user sampai OfferExpiresAt; pesan lewat POST /orders.
+type WaitlistEntry struct {
+	ID             int        `json:"id"`
+	ScheduleID     int        `json:"schedule_id"`
+	UserID         int        `json:"-"`
+	Count          int        `json:"count"`
+	Status         string     `json:"status"`
+	Position       int        `json:"position,omitempty"`
+	OfferedSeats   []string   `json:"offered_seats"`
+	OfferedAt      *time.Time `json:"offered_at,omitempty"`
+	OfferExpiresAt *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// WaitRoomConfig: pengaturan antrian untuk satu jadwal atau satu movie
+type WaitRoomConfig struct {
+	Scope   string `json:"scope"` // schedule | movie
+	ScopeID int    `json:"scope_id"`
+	Enabled bool   `json:"enabled"`
+	// jumlah user yang boleh booking bersamaan
+	Capacity int `json:"capacity" binding:"required,min=1"`
+	// masa berlaku admission token, default 600
+	TokenTTLSeconds int `json:"token_ttl_seconds" binding:"omitempty,min=30"`
+	// rata-rata lama satu user booking, dipakai untuk estimasi waktu tunggu, default 180
+	AvgSessionSeconds int `json:"avg_session_seconds" binding:"omitempty,min=1"`
+}
+
+// QueueStatus: posisi user di antrian. Token hanya diisi jika Admitted.
+type QueueStatus struct {
+	Scope                string     `json:"scope"`
+	ScopeID              int        `json:"scope_id"`
+	Enabled              bool       `json:"enabled"`
+	Admitted             bool       `json:"admitted"`
+	Position             int        `json:"position,omitempty"`
+	EstimatedWaitSeconds int        `json:"estimated_wait_seconds,omitempty"`
+	Token                string     `json:"token,omitempty"`
+	ExpiresAt            *time.Time `json:"expires_at,omitempty"`
+}
+
+// WaitRoomStats dipakai admin untuk memantau antrian
+type WaitRoomStats struct {
+	WaitRoomConfig
+	Waiting int64 `json:"waiting"`
+	Active  int64 `json:"active"`
+}
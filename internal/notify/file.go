@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// File menulis setiap pesan sebagai file .eml di Dir, berguna untuk development.
+// Nama file memakai ID pesan sehingga pengiriman ulang menimpa file yang sama.
+type File struct {
+	Dir string
+}
+
+func NewFile(dir string) *File {
+	return &File{Dir: dir}
+}
+
+func (f *File) Name() string { return "file" }
+
+func (f *File) Send(ctx context.Context, m Message) error {
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Join(f.Dir, fmt.Sprintf("%08d-%s.eml", m.ID, m.Template))
+	return os.WriteFile(name, mailBody("", m), 0o644)
+}
@@ -0,0 +1,75 @@
+// Package notify mengirim notifikasi dari outbox (table notifications) lewat channel
+// yang dipilih dengan NOTIFY_CHANNEL: email SMTP, webhook, atau file lokal untuk development.
+// Pesan dirender dari template per bahasa yang di-embed di binary.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Message: notifikasi yang sudah dirender dan siap dikirim
+type Message struct {
+	ID        int64  `json:"id"`
+	UserID    int    `json:"user_id"`
+	Template  string `json:"template"`
+	Language  string `json:"language"`
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	Body      string `json:"body"`
+}
+
+// Channel: cara pengiriman. Send bisa dipanggil lebih dari sekali untuk pesan yang sama
+// (at-least-once), jadi penerima sebaiknya memakai Message.ID untuk dedup.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, m Message) error
+}
+
+// NewChannelFromEnv memilih channel dari NOTIFY_CHANNEL (file | smtp | webhook).
+//
+//	file:    NOTIFY_FILE_DIR (default "notifications"), satu file .eml per pesan
+//	smtp:    SMTP_HOST, SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM
+//	webhook: NOTIFY_WEBHOOK_URL, NOTIFY_WEBHOOK_SECRET (opsional, signature HMAC-SHA256 di X-Signature),
+//	         NOTIFY_WEBHOOK_TIMEOUT (default 10s)
+func NewChannelFromEnv() (Channel, error) {
+	switch driver := os.Getenv("NOTIFY_CHANNEL"); driver {
+	case "", "file":
+		dir := os.Getenv("NOTIFY_FILE_DIR")
+		if dir == "" {
+			dir = "notifications"
+		}
+		return NewFile(dir), nil
+	case "smtp":
+		port := 587
+		if v := os.Getenv("SMTP_PORT"); v != "" {
+			p, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("SMTP_PORT: %w", err)
+			}
+			port = p
+		}
+		return NewSMTP(SMTPOptions{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("SMTP_FROM"),
+		})
+	case "webhook":
+		timeout := 10 * time.Second
+		if v := os.Getenv("NOTIFY_WEBHOOK_TIMEOUT"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("NOTIFY_WEBHOOK_TIMEOUT: %w", err)
+			}
+			timeout = d
+		}
+		return NewWebhook(os.Getenv("NOTIFY_WEBHOOK_URL"), os.Getenv("NOTIFY_WEBHOOK_SECRET"), timeout)
+	default:
+		return nil, fmt.Errorf("unknown NOTIFY_CHANNEL %q", driver)
+	}
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP mengirim email plain text. Auth PLAIN dipakai jika Username diisi
+// (net/smtp hanya mengirim kredensial lewat TLS, kecuali ke localhost).
+type SMTP struct {
+	opts SMTPOptions
+}
+
+func NewSMTP(opts SMTPOptions) (*SMTP, error) {
+	if opts.Host == "" || opts.From == "" {
+		return nil, errors.New("smtp: SMTP_HOST and SMTP_FROM are required")
+	}
+	return &SMTP{opts: opts}, nil
+}
+
+func (s *SMTP) Name() string { return "smtp" }
+
+func (s *SMTP) Send(ctx context.Context, m Message) error {
+	var auth smtp.Auth
+	if s.opts.Username != "" {
+		auth = smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)
+	}
+	addr := net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port))
+	return smtp.SendMail(addr, auth, s.opts.From, []string{m.Recipient}, mailBody(s.opts.From, m))
+}
+
+// mailBody: pesan RFC 5322 sederhana, subject di-encode supaya aman untuk karakter non-ASCII
+func mailBody(from string, m Message) []byte {
+	var b bytes.Buffer
+	if from != "" {
+		fmt.Fprintf(&b, "From: %s\r\n", from)
+	}
+	fmt.Fprintf(&b, "To: %s\r\n", m.Recipient)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "X-Notification-ID: %d\r\n", m.ID)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(m.Body)
+	return b.Bytes()
+}
@@ -0,0 +1,130 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// DefaultLanguage dipakai jika template tidak tersedia dalam bahasa user
+const DefaultLanguage = "en"
+
+// ErrUnknownTemplate: template tidak ada dalam bahasa mana pun, pesan tidak akan pernah bisa dikirim
+var ErrUnknownTemplate = errors.New("unknown notification template")
+
+// templates/<bahasa>/<nama>.tmpl. Baris pertama "Subject: ...", lalu baris kosong, lalu isi pesan.
+//
+//go:embed templates/*/*.tmpl
+var templateFS embed.FS
+
+var templates = mustParseTemplates()
+
+// mustParseTemplates: nama template "<bahasa>/<nama>.tmpl" supaya nama yang sama di bahasa lain tidak bentrok
+func mustParseTemplates() *template.Template {
+	root := template.New("").Funcs(template.FuncMap{
+		"join":     join,
+		"money":    money,
+		"datetime": datetime,
+	})
+	files, err := fs.Glob(templateFS, "templates/*/*.tmpl")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		src, err := templateFS.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+		template.Must(root.New(strings.TrimPrefix(file, "templates/")).Parse(string(src)))
+	}
+	return root
+}
+
+// HasLanguage: ada template untuk bahasa ini
+func HasLanguage(lang string) bool {
+	for _, t := range templates.Templates() {
+		if strings.HasPrefix(t.Name(), lang+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// Render mengisi template dengan payload JSON dan memisahkan subject dari isi pesan
+func Render(name, lang string, payload []byte) (subject, body string, err error) {
+	t := templates.Lookup(lang + "/" + name + ".tmpl")
+	if t == nil {
+		t = templates.Lookup(DefaultLanguage + "/" + name + ".tmpl")
+	}
+	if t == nil {
+		return "", "", fmt.Errorf("%w %q", ErrUnknownTemplate, name)
+	}
+
+	data := map[string]any{}
+	if len(payload) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(payload))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return "", "", err
+		}
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", "", err
+	}
+	head, rest, _ := strings.Cut(out.String(), "\n")
+	subject, ok := strings.CutPrefix(head, "Subject: ")
+	if !ok {
+		return "", "", fmt.Errorf("template %s: first line must be \"Subject: ...\"", t.Name())
+	}
+	return strings.TrimSpace(subject), strings.TrimLeft(rest, "\n"), nil
+}
+
+func join(v any, sep string) string {
+	switch list := v.(type) {
+	case []any:
+		parts := make([]string, len(list))
+		for i, p := range list {
+			parts[i] = fmt.Sprint(p)
+		}
+		return strings.Join(parts, sep)
+	case []string:
+		return strings.Join(list, sep)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// datetime: timestamp RFC 3339 dari payload -> "2006-01-02 15:04 MST"
+func datetime(v any) string {
+	t, err := time.Parse(time.RFC3339, fmt.Sprint(v))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return t.Format("2006-01-02 15:04 MST")
+}
+
+// money: 45000 -> "45.000"
+func money(v any) string {
+	s := fmt.Sprint(v)
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	if neg {
+		return "-" + b.String()
+	}
+	return b.String()
+}
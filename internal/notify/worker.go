@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+)
+
+const (
+	defaultPollInterval = 10 * time.Second
+	defaultMaxAttempts  = 5
+	claimBatch          = 20
+	// lease harus lebih lama dari satu batch pengiriman
+	claimLease = 5 * time.Minute
+
+	retryBase = 30 * time.Second
+	retryMax  = time.Hour
+)
+
+// Worker mengirim notifikasi dari outbox. Aman dijalankan di setiap replica: baris diklaim dengan
+// FOR UPDATE SKIP LOCKED. Gagal kirim dicoba ulang dengan backoff eksponensial sampai MaxAttempts.
+type Worker struct {
+	repo         *repository.NotificationRepository
+	channel      Channel
+	pollInterval time.Duration
+	maxAttempts  int
+}
+
+// NewWorkerFromEnv memakai channel dari NewChannelFromEnv dan membaca NOTIFY_POLL_INTERVAL (default 10s)
+// dan NOTIFY_MAX_ATTEMPTS (default 5)
+func NewWorkerFromEnv(repo *repository.NotificationRepository) (*Worker, error) {
+	channel, err := NewChannelFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	w := &Worker{repo: repo, channel: channel, pollInterval: defaultPollInterval, maxAttempts: defaultMaxAttempts}
+	if d, err := time.ParseDuration(os.Getenv("NOTIFY_POLL_INTERVAL")); err == nil && d > 0 {
+		w.pollInterval = d
+	}
+	if n, err := strconv.Atoi(os.Getenv("NOTIFY_MAX_ATTEMPTS")); err == nil && n > 0 {
+		w.maxAttempts = n
+	}
+	return w, nil
+}
+
+// Run mengirim notifikasi yang jatuh tempo sampai ctx selesai
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+	for {
+		w.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) deliverDue(ctx context.Context) {
+	for {
+		batch, err := w.repo.Claim(ctx, claimBatch, claimLease)
+		if err != nil {
+			log.Println("notification claim:", err)
+			return
+		}
+		for _, n := range batch {
+			w.deliver(ctx, n)
+		}
+		if len(batch) < claimBatch {
+			return
+		}
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, n models.Notification) {
+	subject, body, err := Render(n.Template, n.Language, n.Payload)
+	if err != nil {
+		// template rusak / tidak ada tidak akan berhasil dengan retry
+		w.fail(ctx, n, err, false)
+		return
+	}
+	err = w.channel.Send(ctx, Message{
+		ID:        n.ID,
+		UserID:    n.UserID,
+		Template:  n.Template,
+		Language:  n.Language,
+		Recipient: n.Recipient,
+		Subject:   subject,
+		Body:      body,
+	})
+	if err != nil {
+		w.fail(ctx, n, err, true)
+		return
+	}
+	if err := w.repo.MarkSent(ctx, n.ID, w.channel.Name()); err != nil {
+		log.Println("notification mark sent:", err)
+	}
+}
+
+func (w *Worker) fail(ctx context.Context, n models.Notification, cause error, retry bool) {
+	var retryAt *time.Time
+	if retry && n.Attempts < w.maxAttempts {
+		at := time.Now().Add(backoff(n.Attempts))
+		retryAt = &at
+	}
+	if err := w.repo.MarkFailed(ctx, n.ID, w.channel.Name(), cause.Error(), retryAt); err != nil {
+		log.Println("notification mark failed:", err)
+	}
+}
+
+// backoff: 30s, 1m, 2m, 4m, ... maksimal 1 jam
+func backoff(attempts int) time.Duration {
+	d := retryBase
+	for i := 1; i < attempts && d < retryMax; i++ {
+		d *= 2
+	}
+	return min(d, retryMax)
+}
@@ -0,0 +1,158 @@
+// Package pricing menghitung harga dinamis satu jadwal dari daftar aturan harga.
+// Package ini tidak mengakses database; data jadwal dan tingkat okupansi dikirim oleh pemanggil.
+// Untuk input yang sama hasilnya selalu sama: rule diurutkan berdasarkan priority lalu id.
+package pricing
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+// Show: data jadwal yang dibutuhkan untuk mengevaluasi aturan
+type Show struct {
+	ScheduleID  int
+	MovieID     int
+	CinemaID    int
+	Date        time.Time  // tanggal tayang
+	StartTime   string     // HH:MM
+	ReleaseDate *time.Time // nil jika tidak diketahui
+	// OccupancyPct: kursi terisi (paid / pending) dibanding kursi aktif studio, 0-100
+	OccupancyPct int
+}
+
+// Evaluate menerapkan rule aktif satu per satu ke basePrice. Rule dengan StopProcessing
+// menghentikan evaluasi setelah berlaku. Harga tidak pernah di bawah nol.
+func Evaluate(basePrice int, rules []models.PricingRule, s Show, now time.Time) models.PriceBreakdown {
+	sorted := slices.Clone(rules)
+	slices.SortStableFunc(sorted, func(a, b models.PricingRule) int {
+		if a.Priority != b.Priority {
+			return a.Priority - b.Priority
+		}
+		return a.ID - b.ID
+	})
+
+	b := models.PriceBreakdown{
+		ScheduleID:       s.ScheduleID,
+		BasePrice:        basePrice,
+		Price:            basePrice,
+		ShowDate:         s.Date.Format("2006-01-02"),
+		Weekday:          int(s.Date.Weekday()),
+		StartTime:        s.StartTime,
+		DaysSinceRelease: daysSinceRelease(s),
+		OccupancyPct:     s.OccupancyPct,
+		Rules:            []models.PriceRuleResult{},
+		EvaluatedAt:      now,
+	}
+
+	stopped := false
+	for _, r := range sorted {
+		if !r.IsActive {
+			continue
+		}
+		res := models.PriceRuleResult{RuleID: r.ID, Name: r.Name, Priority: r.Priority, Adjustment: Describe(r)}
+		switch {
+		case stopped:
+			res.Reason = "skipped: an earlier rule stopped processing"
+		default:
+			res.Reason = mismatch(r, s, b.DaysSinceRelease)
+		}
+		if res.Reason == "" {
+			res.Applied = true
+			res.PriceBefore = b.Price
+			b.Price = adjust(b.Price, r)
+			res.PriceAfter = b.Price
+			stopped = r.StopProcessing
+		}
+		b.Rules = append(b.Rules, res)
+	}
+	return b
+}
+
+// Describe: penyesuaian harga dalam bentuk singkat, contoh +20%, -5000, =45000
+func Describe(r models.PricingRule) string {
+	switch r.AdjustmentType {
+	case "percent":
+		return fmt.Sprintf("%+d%%", r.AdjustmentValue)
+	case "set":
+		return fmt.Sprintf("=%d", r.AdjustmentValue)
+	default:
+		return fmt.Sprintf("%+d", r.AdjustmentValue)
+	}
+}
+
+// mismatch: alasan rule tidak berlaku, kosong jika semua kondisi terpenuhi
+func mismatch(r models.PricingRule, s Show, sinceRelease *int) string {
+	switch {
+	case len(r.MovieIDs) > 0 && !slices.Contains(r.MovieIDs, s.MovieID):
+		return "movie not included"
+	case len(r.CinemaIDs) > 0 && !slices.Contains(r.CinemaIDs, s.CinemaID):
+		return "cinema not included"
+	case len(r.DaysOfWeek) > 0 && !slices.Contains(r.DaysOfWeek, int(s.Date.Weekday())):
+		return fmt.Sprintf("show day %s not included", s.Date.Weekday())
+	case !inTimeRange(s.StartTime, r.StartFrom, r.StartUntil):
+		return fmt.Sprintf("start time %s outside %s-%s", s.StartTime, deref(r.StartFrom, "00:00"), deref(r.StartUntil, "24:00"))
+	case r.PremiereDays != nil && (sinceRelease == nil || *sinceRelease < 0 || *sinceRelease >= *r.PremiereDays):
+		return fmt.Sprintf("not within %d days of release", *r.PremiereDays)
+	case r.MinOccupancy != nil && s.OccupancyPct < *r.MinOccupancy:
+		return fmt.Sprintf("occupancy %d%% below %d%%", s.OccupancyPct, *r.MinOccupancy)
+	case r.MaxOccupancy != nil && s.OccupancyPct > *r.MaxOccupancy:
+		return fmt.Sprintf("occupancy %d%% above %d%%", s.OccupancyPct, *r.MaxOccupancy)
+	}
+	return ""
+}
+
+// inTimeRange: start di [from, until). Jika from > until rentang melewati tengah malam,
+// misalnya 22:00-02:00. Jam HH:MM bisa dibandingkan sebagai string.
+func inTimeRange(start string, from, until *string) bool {
+	switch {
+	case from == nil && until == nil:
+		return true
+	case from == nil:
+		return start < *until
+	case until == nil:
+		return start >= *from
+	case *from <= *until:
+		return start >= *from && start < *until
+	default:
+		return start >= *from || start < *until
+	}
+}
+
+// adjust: persen dibulatkan ke bawah ke rupiah penuh supaya hasilnya deterministik
+func adjust(price int, r models.PricingRule) int {
+	switch r.AdjustmentType {
+	case "percent":
+		price += price * r.AdjustmentValue / 100
+	case "fixed":
+		price += r.AdjustmentValue
+	case "set":
+		price = r.AdjustmentValue
+	}
+	return max(price, 0)
+}
+
+func daysSinceRelease(s Show) *int {
+	if s.ReleaseDate == nil {
+		return nil
+	}
+	show := time.Date(s.Date.Year(), s.Date.Month(), s.Date.Day(), 0, 0, 0, 0, time.UTC)
+	release := time.Date(s.ReleaseDate.Year(), s.ReleaseDate.Month(), s.ReleaseDate.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(show.Sub(release).Hours() / 24)
+	return &days
+}
+
+func deref(s *string, fallback string) string {
+	if s == nil {
+		return fallback
+	}
+	return *s
+}
+
+// ValidClock: format jam HH:MM untuk StartFrom / StartUntil
+func ValidClock(s string) bool {
+	t, err := time.Parse("15:04", s)
+	return err == nil && t.Format("15:04") == s
+}
@@ -0,0 +1,159 @@
+package pricing
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// show: jadwal movie 1 di cinema 1; 2024-06-05 hari Rabu, 2024-06-08 hari Sabtu
+func show(date, start string, occupancy int) Show {
+	d, _ := time.Parse("2006-01-02", date)
+	return Show{
+		ScheduleID:   1,
+		MovieID:      1,
+		CinemaID:     1,
+		Date:         d,
+		StartTime:    start,
+		ReleaseDate:  ptr(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)),
+		OccupancyPct: occupancy,
+	}
+}
+
+func rule(id, priority int, typ string, value int, change func(*models.PricingRule)) models.PricingRule {
+	r := models.PricingRule{ID: id, Name: "rule", Priority: priority, AdjustmentType: typ, AdjustmentValue: value, IsActive: true}
+	if change != nil {
+		change(&r)
+	}
+	return r
+}
+
+var (
+	weekend   = rule(1, 10, "percent", 20, func(r *models.PricingRule) { r.DaysOfWeek = []int{0, 6} })
+	matinee   = rule(2, 20, "fixed", -10000, func(r *models.PricingRule) { r.StartUntil = ptr("12:00") })
+	primeTime = rule(3, 20, "fixed", 5000, func(r *models.PricingRule) { r.StartFrom, r.StartUntil = ptr("18:00"), ptr("22:00") })
+	premiere  = rule(4, 30, "percent", 10, func(r *models.PricingRule) { r.PremiereDays = ptr(7) })
+	busy      = rule(5, 40, "fixed", 3000, func(r *models.PricingRule) { r.MinOccupancy = ptr(80) })
+	quiet     = rule(6, 40, "fixed", -3000, func(r *models.PricingRule) { r.MaxOccupancy = ptr(20) })
+)
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []models.PricingRule
+		show    Show
+		price   int
+		applied []int
+	}{
+		{"weekday does not match weekend rule", []models.PricingRule{weekend}, show("2024-06-05", "15:00", 50), 50000, nil},
+		{"weekend", []models.PricingRule{weekend}, show("2024-06-08", "15:00", 50), 60000, []int{1}},
+		{"matinee", []models.PricingRule{matinee, primeTime}, show("2024-06-05", "10:30", 50), 40000, []int{2}},
+		{"prime time", []models.PricingRule{matinee, primeTime}, show("2024-06-05", "18:00", 50), 55000, []int{3}},
+		{"prime time end is exclusive", []models.PricingRule{matinee, primeTime}, show("2024-06-05", "22:00", 50), 50000, nil},
+		{"premiere on release day", []models.PricingRule{premiere}, show("2024-06-01", "15:00", 50), 55000, []int{4}},
+		{"premiere last day", []models.PricingRule{premiere}, show("2024-06-07", "15:00", 50), 55000, []int{4}},
+		{"premiere over", []models.PricingRule{premiere}, show("2024-06-08", "15:00", 50), 50000, nil},
+		{"occupancy at min threshold", []models.PricingRule{busy, quiet}, show("2024-06-05", "15:00", 80), 53000, []int{5}},
+		{"occupancy below min threshold", []models.PricingRule{busy, quiet}, show("2024-06-05", "15:00", 79), 50000, nil},
+		{"occupancy at max threshold", []models.PricingRule{busy, quiet}, show("2024-06-05", "15:00", 20), 47000, []int{6}},
+		{"occupancy above max threshold", []models.PricingRule{busy, quiet}, show("2024-06-05", "15:00", 21), 50000, nil},
+		{
+			name:    "rules stack in priority order",
+			rules:   []models.PricingRule{busy, premiere, primeTime, weekend},
+			show:    show("2024-06-02", "19:00", 90),
+			price:   ((50000+10000)+5000)*110/100 + 3000,
+			applied: []int{1, 3, 4, 5},
+		},
+		{
+			name:    "stop processing skips later rules",
+			rules:   []models.PricingRule{busy, rule(7, 5, "set", 35000, func(r *models.PricingRule) { r.StopProcessing = true }), weekend},
+			show:    show("2024-06-08", "15:00", 90),
+			price:   35000,
+			applied: []int{7},
+		},
+		{
+			name:    "price never below zero",
+			rules:   []models.PricingRule{rule(8, 1, "fixed", -90000, nil)},
+			show:    show("2024-06-05", "15:00", 50),
+			price:   0,
+			applied: []int{8},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := Evaluate(50000, tt.rules, tt.show, time.Time{})
+			if b.Price != tt.price {
+				t.Fatalf("price = %d, want %d", b.Price, tt.price)
+			}
+			var applied []int
+			for _, r := range b.Rules {
+				if r.Applied {
+					applied = append(applied, r.RuleID)
+				} else if r.Reason == "" {
+					t.Fatalf("rule %d not applied without a reason", r.RuleID)
+				}
+			}
+			if !slices.Equal(applied, tt.applied) {
+				t.Fatalf("applied = %v, want %v", applied, tt.applied)
+			}
+		})
+	}
+}
+
+func TestEvaluateOrdering(t *testing.T) {
+	// priority sama: id lebih kecil lebih dulu, urutan input tidak berpengaruh
+	a := rule(10, 1, "set", 40000, nil)
+	b := rule(11, 1, "percent", 50, nil)
+	c := rule(12, 0, "fixed", 1000, nil)
+	s := show("2024-06-05", "15:00", 50)
+
+	want := Evaluate(50000, []models.PricingRule{a, b, c}, s, time.Time{})
+	if want.Price != 60000 {
+		t.Fatalf("price = %d, want 60000", want.Price)
+	}
+	for _, order := range [][]models.PricingRule{{c, b, a}, {b, a, c}, {b, c, a}} {
+		got := Evaluate(50000, order, s, time.Time{})
+		if got.Price != want.Price || ruleIDs(got) != ruleIDs(want) {
+			t.Fatalf("order %v: price %d rules %s, want %d %s", order, got.Price, ruleIDs(got), want.Price, ruleIDs(want))
+		}
+	}
+	if ruleIDs(want) != "[12,10,11]" {
+		t.Fatalf("rules = %s", ruleIDs(want))
+	}
+}
+
+func TestEvaluateAudit(t *testing.T) {
+	inactive := rule(9, 0, "fixed", 1000, func(r *models.PricingRule) { r.IsActive = false })
+	stop := rule(7, 25, "fixed", -5000, func(r *models.PricingRule) { r.StopProcessing = true })
+	evaluatedAt := time.Date(2024, 6, 8, 9, 0, 0, 0, time.UTC)
+
+	b := Evaluate(50000, []models.PricingRule{inactive, weekend, matinee, stop, premiere}, show("2024-06-08", "15:00", 50), evaluatedAt)
+	if b.BasePrice != 50000 || b.Price != 55000 || b.ShowDate != "2024-06-08" || b.Weekday != 6 ||
+		b.StartTime != "15:00" || b.DaysSinceRelease == nil || *b.DaysSinceRelease != 7 || b.OccupancyPct != 50 || !b.EvaluatedAt.Equal(evaluatedAt) {
+		t.Fatalf("breakdown = %+v", b)
+	}
+
+	want := []models.PriceRuleResult{
+		{RuleID: 1, Name: "rule", Priority: 10, Adjustment: "+20%", Applied: true, PriceBefore: 50000, PriceAfter: 60000},
+		{RuleID: 2, Name: "rule", Priority: 20, Adjustment: "-10000", Reason: "start time 15:00 outside 00:00-12:00"},
+		{RuleID: 7, Name: "rule", Priority: 25, Adjustment: "-5000", Applied: true, PriceBefore: 60000, PriceAfter: 55000},
+		{RuleID: 4, Name: "rule", Priority: 30, Adjustment: "+10%", Reason: "skipped: an earlier rule stopped processing"},
+	}
+	if !slices.Equal(b.Rules, want) {
+		t.Fatalf("rules = %+v\nwant %+v", b.Rules, want)
+	}
+}
+
+func ruleIDs(b models.PriceBreakdown) string {
+	var ids []int
+	for _, r := range b.Rules {
+		ids = append(ids, r.RuleID)
+	}
+	data, _ := json.Marshal(ids)
+	return string(data)
+}
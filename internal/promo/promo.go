@@ -0,0 +1,77 @@
+// Package promo memvalidasi kode promo terhadap satu order dan menghitung potongannya.
+// Package ini tidak mengakses database; data pemakaian dikirim oleh pemanggil.
+package promo
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+// Error: kode promo tidak bisa dipakai untuk order ini. Reason bisa dipakai client
+// untuk menampilkan pesan sendiri.
+type Error struct {
+	Reason  string
+	Message string
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func invalid(reason, message string) *Error {
+	return &Error{Reason: reason, Message: message}
+}
+
+var ErrNotFound = invalid("not_found", "promo code not found")
+
+// Order: data order yang dibutuhkan untuk mengevaluasi promo
+type Order struct {
+	MovieID  int
+	CinemaID int
+	ShowDate time.Time // tanggal tayang, dipakai untuk batasan hari
+	Seats    int
+	Subtotal int
+	// UserUses: berapa kali user sudah memakai promo ini
+	UserUses int
+	Now      time.Time
+}
+
+// Normalize: kode promo tidak case-sensitive dan disimpan dalam huruf besar
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Discount memeriksa semua syarat promo lalu mengembalikan potongan (tidak pernah
+// melebihi subtotal). Error selalu bertipe *Error.
+func Discount(p models.Promotion, o Order) (int, error) {
+	switch {
+	case !p.IsActive:
+		return 0, invalid("inactive", "promo code is no longer active")
+	case p.ValidFrom != nil && o.Now.Before(*p.ValidFrom):
+		return 0, invalid("not_started", "promo code is not valid yet")
+	case p.ValidUntil != nil && !o.Now.Before(*p.ValidUntil):
+		return 0, invalid("expired", "promo code has expired")
+	case p.MaxUses != nil && p.UsedCount >= *p.MaxUses:
+		return 0, invalid("exhausted", "promo code has reached its usage limit")
+	case p.MaxUsesPerUser != nil && o.UserUses >= *p.MaxUsesPerUser:
+		return 0, invalid("user_limit", "you have already used this promo code")
+	case o.Seats < p.MinSeats:
+		return 0, invalid("min_seats", "promo code requires more seats")
+	case len(p.MovieIDs) > 0 && !slices.Contains(p.MovieIDs, o.MovieID):
+		return 0, invalid("movie", "promo code is not valid for this movie")
+	case len(p.CinemaIDs) > 0 && !slices.Contains(p.CinemaIDs, o.CinemaID):
+		return 0, invalid("cinema", "promo code is not valid for this cinema")
+	case len(p.DaysOfWeek) > 0 && !slices.Contains(p.DaysOfWeek, int(o.ShowDate.Weekday())):
+		return 0, invalid("day", "promo code is not valid on this day")
+	}
+
+	discount := p.DiscountValue
+	if p.DiscountType == "percent" {
+		discount = o.Subtotal * p.DiscountValue / 100
+		if p.MaxDiscount != nil && discount > *p.MaxDiscount {
+			discount = *p.MaxDiscount
+		}
+	}
+	return min(discount, o.Subtotal), nil
+}
@@ -1,23 +1,35 @@
 package repository
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"log"
 	"strings"
 
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/tmdb"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type AdminRepository struct {
-	DB *pgxpool.Pool
+	DB    *pgxpool.Pool
+	TMDB  *tmdb.Client
+	Media *media.Store // tujuan mirror gambar TMDB (SyncOptions.MirrorImages)
 }
 
-func NewAdminRepository(db *pgxpool.Pool) *AdminRepository {
-	return &AdminRepository{DB: db}
+// ukuran gambar TMDB yang di-mirror, varian lebih kecil dibuat oleh media.Store
+const mirrorImageSize = "w780"
+
+// ErrMovieExists: movie dengan tmdb_id yang sama sudah ada
+var ErrMovieExists = errors.New("movie already exists")
+
+func NewAdminRepository(db *pgxpool.Pool, tmdbClient *tmdb.Client, mediaStore *media.Store) *AdminRepository {
+	return &AdminRepository{DB: db, TMDB: tmdbClient, Media: mediaStore}
 }
 
 func (r *AdminRepository) CreateMovie(ctx context.Context, req models.NewMovieRequest) (int, error) {
@@ -31,13 +43,19 @@ func (r *AdminRepository) CreateMovie(ctx context.Context, req models.NewMovieRe
 	var movieID int
 	err = tx.QueryRow(ctx, `
         INSERT INTO movies (tmdb_id, title, overview, release_date, runtime,
-                            poster_path, backdrop_path, popularity, vote_average, vote_count, created_at, updated_at)
-        VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,NOW(),NOW())
+                            poster_path, backdrop_path, popularity, vote_average, vote_count,
+                            tmdb_poster_path, tmdb_backdrop_path, created_at, updated_at)
+        VALUES ($1,$2,$3,NULLIF($4, '')::date,$5,$6,$7,$8,$9,$10,NULLIF($11, ''),NULLIF($12, ''),NOW(),NOW())
         RETURNING id
     `, req.TMDBID, req.Title, req.Overview, req.ReleaseDate, req.Runtime,
-		req.PosterPath, req.BackdropPath, req.Popularity, req.VoteAverage, req.VoteCount).
+		req.PosterPath, req.BackdropPath, req.Popularity, req.VoteAverage, req.VoteCount,
+		req.TMDBPosterPath, req.TMDBBackdropPath).
 		Scan(&movieID)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return 0, ErrMovieExists
+		}
 		return 0, err
 	}
 
@@ -62,34 +80,51 @@ func (r *AdminRepository) CreateMovie(ctx context.Context, req models.NewMovieRe
 		}
 	}
 
-	// 3. Insert schedules
+	// 3. Insert schedules (auditorium_id kosong -> studio aktif pertama di cinema).
+	// Cinema, studio, lokasi dan jam harus aktif seperti di import; barisnya dikunci FOR SHARE
+	// supaya retire() yang berjalan bersamaan menunggu dan melihat jadwal baru ini.
 	for _, s := range req.Schedules {
-		_, err = tx.Exec(ctx, `
-            INSERT INTO schedules (movie_id, cinema_id, location_id, time_id, date, price)
-            VALUES ($1, $2, $3, $4, $5, $6)
-        `, movieID, s.CinemaID, s.LocationID, s.TimeID, s.Date, s.Price)
+		tag, err := tx.Exec(ctx, `
+            INSERT INTO schedules (movie_id, cinema_id, auditorium_id, location_id, time_id, date, price)
+            SELECT $1, a.cinema_id, a.id, l.id, t.id, $6, $7
+            FROM auditoriums a
+            JOIN cinemas c ON c.id = a.cinema_id AND c.is_active
+            JOIN locations l ON l.id = $4 AND l.is_active
+            JOIN times t ON t.id = $5 AND t.is_active
+            WHERE a.is_active
+              AND ((a.id = $3 AND ($2 = 0 OR a.cinema_id = $2)) OR ($3 = 0 AND a.cinema_id = $2))
+            ORDER BY a.id
+            LIMIT 1
+            FOR SHARE OF a, c, l, t
+        `, movieID, s.CinemaID, s.AuditoriumID, s.LocationID, s.TimeID, s.Date, s.Price)
 		if err != nil {
 			return 0, err
 		}
+		if tag.RowsAffected() == 0 {
+			return 0, fmt.Errorf("no active cinema %d / auditorium %d / location %d / time %d",
+				s.CinemaID, s.AuditoriumID, s.LocationID, s.TimeID)
+		}
 	}
 
-	// 4. Insert director
-	_, err = tx.Exec(ctx, `
-        INSERT INTO persons (tmdb_id, name)
-        VALUES ($1, $2)
-        ON CONFLICT (tmdb_id) DO NOTHING
-    `, req.Director.TMDBID, req.Director.Name)
-	if err != nil {
-		return 0, err
-	}
+	// 4. Insert director (opsional)
+	if req.Director != nil {
+		_, err = tx.Exec(ctx, `
+            INSERT INTO persons (tmdb_id, name)
+            VALUES ($1, $2)
+            ON CONFLICT (tmdb_id) DO NOTHING
+        `, req.Director.TMDBID, req.Director.Name)
+		if err != nil {
+			return 0, err
+		}
 
-	_, err = tx.Exec(ctx, `
-        INSERT INTO movie_casts (movie_id, person_id, role)
-        SELECT $1, id, 'Director' FROM persons WHERE tmdb_id = $2
-        ON CONFLICT DO NOTHING
-    `, movieID, req.Director.TMDBID)
-	if err != nil {
-		return 0, err
+		_, err = tx.Exec(ctx, `
+            INSERT INTO movie_casts (movie_id, person_id, role)
+            SELECT $1, id, 'Director' FROM persons WHERE tmdb_id = $2
+            ON CONFLICT DO NOTHING
+        `, movieID, req.Director.TMDBID)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	// 5. Insert casts
@@ -121,6 +156,66 @@ func (r *AdminRepository) CreateMovie(ctx context.Context, req models.NewMovieRe
 	return movieID, nil
 }
 
+// ImportMovie mengambil detail, credits, genre dan gambar movie dari TMDB
+// lalu menyimpannya lewat CreateMovie (satu transaksi, termasuk schedules opsional)
+func (r *AdminRepository) ImportMovie(ctx context.Context, tmdbID int, req models.ImportMovieRequest) (int, error) {
+	var exists bool
+	if err := r.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM movies WHERE tmdb_id = $1)`, tmdbID).Scan(&exists); err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, ErrMovieExists
+	}
+
+	d, err := r.TMDB.MovieDetail(ctx, tmdbID)
+	if err != nil {
+		return 0, err
+	}
+
+	if req.CastLimit <= 0 {
+		req.CastLimit = 10
+	}
+
+	// gambar melewati jalur yang sama dengan sync (mirror opsional)
+	movie := models.NewMovieRequest{
+		TMDBID:           &d.ID,
+		Title:            d.Title,
+		Overview:         d.Overview,
+		ReleaseDate:      d.ReleaseDate,
+		Runtime:          d.Runtime,
+		PosterPath:       r.syncImage(ctx, "posters", d.PosterPath, "", "", req.MirrorImages),
+		BackdropPath:     r.syncImage(ctx, "backdrops", d.BackdropPath, "", "", req.MirrorImages),
+		Popularity:       d.Popularity,
+		VoteAverage:      d.VoteAverage,
+		VoteCount:        d.VoteCount,
+		Schedules:        req.Schedules,
+		TMDBPosterPath:   d.PosterPath,
+		TMDBBackdropPath: d.BackdropPath,
+	}
+	for _, g := range d.Genres {
+		movie.Genres = append(movie.Genres, g.Name)
+	}
+	for _, crew := range d.Credits.Crew {
+		if crew.Job == "Director" {
+			movie.Director = &models.PersonRequest{TMDBID: crew.ID, Name: crew.Name}
+			break
+		}
+	}
+	for i, cast := range d.Credits.Cast {
+		if i >= req.CastLimit {
+			break
+		}
+		movie.Casts = append(movie.Casts, models.PersonRequest{TMDBID: cast.ID, Name: cast.Name})
+	}
+
+	return r.CreateMovie(ctx, movie)
+}
+
+// SearchTMDB meneruskan pencarian movie ke TMDB
+func (r *AdminRepository) SearchTMDB(ctx context.Context, query string, page int) (*models.TMDBResponse, error) {
+	return r.TMDB.SearchMovies(ctx, query, page)
+}
+
 // GetMovieByID
 func (r *AdminRepository) GetMovieByID(ctx context.Context, id int) (*models.TMDBMovie, error) {
 	row := r.DB.QueryRow(ctx, `
@@ -196,6 +291,22 @@ func (r *AdminRepository) PatchMovie(ctx context.Context, id int, fields map[str
 	return err
 }
 
+// ReplaceMovieImages mengganti poster/backdrop (nil = tidak diubah) dan
+// mengembalikan nilai lama supaya object lama bisa dihapus dari storage
+func (r *AdminRepository) ReplaceMovieImages(ctx context.Context, id int, poster, backdrop *string) (string, string, error) {
+	var oldPoster, oldBackdrop string
+	err := r.DB.QueryRow(ctx, `
+		UPDATE movies m
+		SET poster_path = COALESCE($2, m.poster_path),
+		    backdrop_path = COALESCE($3, m.backdrop_path),
+		    updated_at = NOW()
+		FROM (SELECT id, poster_path, backdrop_path FROM movies WHERE id = $1 FOR UPDATE) old
+		WHERE m.id = old.id
+		RETURNING COALESCE(old.poster_path, ''), COALESCE(old.backdrop_path, '')
+	`, id, poster, backdrop).Scan(&oldPoster, &oldBackdrop)
+	return oldPoster, oldBackdrop, err
+}
+
 // DeleteMovie
 func (r *AdminRepository) DeleteMovie(ctx context.Context, id int) error {
 	_, err := r.DB.Exec(ctx, `DELETE FROM movies WHERE id=$1`, id)
@@ -233,13 +344,14 @@ func (r *AdminRepository) UpsertMovie(m models.TMDBMovie) (int, error) {
 	return movieID, err
 }
 
-// UpsertGenre: insert/update genre by tmdb_id
+// UpsertGenre: insert genre, atau isi tmdb_id jika nama genre sudah ada
+// (genre dari CreateMovie dibuat tanpa tmdb_id)
 func (r *AdminRepository) UpsertGenre(tmdbID int, name string) (int, error) {
 	var genreID int
 	err := r.DB.QueryRow(context.Background(), `
 		INSERT INTO genres (tmdb_id, name)
 		VALUES ($1,$2)
-		ON CONFLICT (tmdb_id) DO UPDATE SET name=EXCLUDED.name
+		ON CONFLICT (name) DO UPDATE SET tmdb_id=EXCLUDED.tmdb_id
 		RETURNING id
 	`, tmdbID, name).Scan(&genreID)
 
@@ -280,94 +392,241 @@ func (r *AdminRepository) LinkMovieCategory(movieID, categoryID int) error {
 }
 
 //
-// -------------------- SYNC & FETCH (existing code) --------------------
+// -------------------- SYNC & FETCH --------------------
 //
 
-func (r *AdminRepository) fetchGenres(apiKey string) (map[int]string, error) {
-	url := fmt.Sprintf("https://api.themoviedb.org/3/genre/movie/list?api_key=%s", apiKey)
+// SyncLists: list TMDB yang boleh di-sync
+var SyncLists = map[string]bool{
+	"popular":     true,
+	"upcoming":    true,
+	"now_playing": true,
+	"top_rated":   true,
+}
+
+// SyncMovies mengambil beberapa halaman dari list TMDB, lalu untuk setiap movie
+// mengambil detail + credits dan menyimpannya (movie, genre, director, cast, category).
+// Movie yang gagal dicatat di result dan tidak menghentikan sync.
+func (r *AdminRepository) SyncMovies(ctx context.Context, opts models.SyncOptions) (*models.SyncResult, error) {
+	if opts.List == "" {
+		opts.List = "popular"
+	}
+	if !SyncLists[opts.List] {
+		return nil, fmt.Errorf("unknown tmdb list %q", opts.List)
+	}
+	if opts.Pages <= 0 {
+		opts.Pages = 1
+	}
+	if opts.CastLimit <= 0 {
+		opts.CastLimit = 10
+	}
 
-	resp, err := http.Get(url)
+	genres, err := r.TMDB.Genres(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	for _, g := range genres {
+		if _, err := r.UpsertGenre(g.ID, g.Name); err != nil {
+			return nil, fmt.Errorf("upsert genre %s: %w", g.Name, err)
+		}
+	}
 
-	var genreResp models.TMDBGenreResponse
-	if err := json.NewDecoder(resp.Body).Decode(&genreResp); err != nil {
+	categoryID, err := r.UpsertCategory(opts.List)
+	if err != nil {
 		return nil, err
 	}
 
-	genreMap := make(map[int]string)
-	for _, g := range genreResp.Genres {
-		genreMap[g.ID] = g.Name
+	result := &models.SyncResult{List: opts.List, Errors: []string{}}
+	for page := 1; page <= opts.Pages; page++ {
+		list, err := r.TMDB.MovieList(ctx, opts.List, page)
+		if err != nil {
+			return result, err
+		}
+		result.Pages = page
+		if page == 1 {
+			// perkiraan total movie, dipakai untuk progress
+			result.Total = min(opts.Pages, list.TotalPages) * len(list.Results)
+		}
+
+		for _, m := range list.Results {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+
+			created, err := r.syncMovie(ctx, m.ID, categoryID, opts)
+			switch {
+			case err != nil:
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("tmdb %d (%s): %v", m.ID, m.Title, err))
+			case created:
+				result.Created++
+			default:
+				result.Updated++
+			}
+			result.Processed++
+
+			if opts.OnProgress != nil {
+				opts.OnProgress(*result)
+			}
+		}
+
+		if page >= list.TotalPages {
+			break
+		}
 	}
-	return genreMap, nil
+
+	return result, nil
 }
 
-// SyncPopular (keputusan: menggunakan upcoming/popular sesuai kebutuhan)
-func (r *AdminRepository) SyncPopular(apiKey string) error {
-	genreMap, err := r.fetchGenres(apiKey)
+// syncMovie: fetch detail satu movie lalu upsert dalam satu transaksi.
+// Return true jika movie baru dibuat, false jika di-update.
+func (r *AdminRepository) syncMovie(ctx context.Context, tmdbID, categoryID int, opts models.SyncOptions) (bool, error) {
+	d, err := r.TMDB.MovieDetail(ctx, tmdbID)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	// NOTE: jika ingin sync popular ganti /movie/upcoming -> /movie/popular
-	url := fmt.Sprintf("https://api.themoviedb.org/3/movie/upcoming?api_key=%s", apiKey)
-	resp, err := http.Get(url)
+	// gambar diunduh sebelum transaksi dibuka supaya transaksi tetap singkat
+	var current struct{ poster, posterSource, backdrop, backdropSource string }
+	err = r.DB.QueryRow(ctx, `
+		SELECT COALESCE(poster_path, ''), COALESCE(tmdb_poster_path, ''),
+		       COALESCE(backdrop_path, ''), COALESCE(tmdb_backdrop_path, '')
+		FROM movies WHERE tmdb_id = $1`, tmdbID,
+	).Scan(&current.poster, &current.posterSource, &current.backdrop, &current.backdropSource)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, err
+	}
+	poster := r.syncImage(ctx, "posters", d.PosterPath, current.poster, current.posterSource, opts.MirrorImages)
+	backdrop := r.syncImage(ctx, "backdrops", d.BackdropPath, current.backdrop, current.backdropSource, opts.MirrorImages)
+
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
-		return err
+		return false, err
 	}
-	defer resp.Body.Close()
+	defer tx.Rollback(ctx)
 
-	var result models.TMDBResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return err
+	var releaseDate *string
+	if d.ReleaseDate != "" {
+		releaseDate = &d.ReleaseDate
 	}
 
-	categoryID, err := r.UpsertCategory("popular")
+	var movieID int
+	var created bool
+	err = tx.QueryRow(ctx, `
+		INSERT INTO movies (
+			tmdb_id, title, overview, release_date, runtime,
+			poster_path, backdrop_path, popularity, vote_average, vote_count,
+			tmdb_poster_path, tmdb_backdrop_path, created_at, updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW(),NOW())
+		ON CONFLICT (tmdb_id) DO UPDATE SET
+			title=EXCLUDED.title,
+			overview=EXCLUDED.overview,
+			release_date=EXCLUDED.release_date,
+			runtime=EXCLUDED.runtime,
+			poster_path=EXCLUDED.poster_path,
+			backdrop_path=EXCLUDED.backdrop_path,
+			popularity=EXCLUDED.popularity,
+			vote_average=EXCLUDED.vote_average,
+			vote_count=EXCLUDED.vote_count,
+			tmdb_poster_path=EXCLUDED.tmdb_poster_path,
+			tmdb_backdrop_path=EXCLUDED.tmdb_backdrop_path,
+			updated_at=NOW()
+		RETURNING id, (xmax = 0)`,
+		d.ID, d.Title, d.Overview, releaseDate, d.Runtime,
+		poster, backdrop, d.Popularity, d.VoteAverage, d.VoteCount,
+		d.PosterPath, d.BackdropPath,
+	).Scan(&movieID, &created)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	for _, m := range result.Results {
-		movieID, err := r.UpsertMovie(m)
-		if err != nil {
-			return err
+	for _, g := range d.Genres {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO movie_genres (movie_id, genre_id)
+			SELECT $1, id FROM genres WHERE tmdb_id = $2
+			ON CONFLICT DO NOTHING`, movieID, g.ID); err != nil {
+			return false, fmt.Errorf("link genre %s: %w", g.Name, err)
 		}
+	}
 
-		// Convert genre IDs to names using genreMap
-		var genreNames []string
-		for _, gid := range m.GenreIDs {
-			if name, ok := genreMap[gid]; ok {
-				genreNames = append(genreNames, name)
-			}
+	for _, crew := range d.Credits.Crew {
+		if crew.Job != "Director" {
+			continue
 		}
+		if err := linkPerson(ctx, tx, movieID, crew.ID, crew.Name, "Director"); err != nil {
+			return false, fmt.Errorf("link director %s: %w", crew.Name, err)
+		}
+		break
+	}
 
-		// Use the same genre handling as CreateMovie
-		for _, gname := range genreNames {
-			var gid int
-			if err := r.DB.QueryRow(context.Background(),
-				`INSERT INTO genres (name)
-				 VALUES ($1)
-				 ON CONFLICT (name) DO UPDATE SET name=EXCLUDED.name
-				 RETURNING id`,
-				gname).Scan(&gid); err != nil {
-				return fmt.Errorf("insert genre %s: %w", gname, err)
-			}
-
-			if _, err := r.DB.Exec(context.Background(),
-				`INSERT INTO movie_genres (movie_id, genre_id)
-				 VALUES ($1,$2)
-				 ON CONFLICT DO NOTHING`,
-				movieID, gid); err != nil {
-				return fmt.Errorf("link genre %s: %w", gname, err)
-			}
+	// TMDB sudah mengurutkan cast berdasarkan billing order
+	for i, cast := range d.Credits.Cast {
+		if i >= opts.CastLimit {
+			break
+		}
+		if err := linkPerson(ctx, tx, movieID, cast.ID, cast.Name, "Actor"); err != nil {
+			return false, fmt.Errorf("link cast %s: %w", cast.Name, err)
 		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO movie_categories (movie_id, category_id)
+		VALUES ($1,$2)
+		ON CONFLICT DO NOTHING`, movieID, categoryID); err != nil {
+		return false, err
+	}
+
+	return created, tx.Commit(ctx)
+}
 
-		if err := r.LinkMovieCategory(movieID, categoryID); err != nil {
-			return err
+// syncImage menentukan nilai poster_path/backdrop_path hasil sync. Gambar yang
+// sudah di-mirror (atau diganti admin) dipertahankan selama path TMDB-nya tidak
+// berubah. Jika mirror gagal, path TMDB tetap dipakai.
+func (r *AdminRepository) syncImage(ctx context.Context, kind, tmdbPath, currentRef, currentSource string, mirror bool) string {
+	if tmdbPath == "" {
+		return ""
+	}
+	if media.IsKey(currentRef) && currentSource == tmdbPath {
+		return currentRef
+	}
+	if !mirror || r.Media == nil {
+		return tmdbPath
+	}
+
+	data, err := r.TMDB.Image(ctx, tmdbPath, mirrorImageSize)
+	if err == nil {
+		var img *media.Image
+		if img, err = r.Media.Save(ctx, bytes.NewReader(data), kind); err == nil {
+			return img.Key
 		}
 	}
+	log.Printf("mirror tmdb image %s: %v", tmdbPath, err)
+	return tmdbPath
+}
+
+// linkPerson: upsert person by tmdb_id lalu hubungkan ke movie dengan role tertentu
+func linkPerson(ctx context.Context, tx pgx.Tx, movieID, tmdbID int, name, role string) error {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO persons (tmdb_id, name)
+		VALUES ($1, $2)
+		ON CONFLICT (tmdb_id) DO UPDATE SET name = EXCLUDED.name`, tmdbID, name); err != nil {
+		return err
+	}
 
+	_, err := tx.Exec(ctx, `
+		INSERT INTO movie_casts (movie_id, person_id, role)
+		SELECT $1, id, $3 FROM persons WHERE tmdb_id = $2
+		ON CONFLICT DO NOTHING`, movieID, tmdbID, role)
+	return err
+}
+
+// SetUserRole mengganti role user (user | staff | admin); berlaku setelah login ulang
+func (r *AdminRepository) SetUserRole(ctx context.Context, userID int, role string) error {
+	tag, err := r.DB.Exec(ctx, `UPDATE users SET role = $2, updated_at = NOW() WHERE id = $1`, userID, role)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
 	return nil
 }
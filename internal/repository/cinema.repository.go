@@ -0,0 +1,476 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrReferenceInUse dikembalikan saat data master masih dipakai jadwal yang akan datang
+var ErrReferenceInUse = errors.New("still used by upcoming schedules")
+
+// ErrAuditoriumRetired: kursi tidak bisa ditambahkan ke auditorium / cinema yang sudah di-retire
+var ErrAuditoriumRetired = errors.New("auditorium is retired")
+
+type CinemaRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewCinemaRepository(db *pgxpool.Pool) *CinemaRepository {
+	return &CinemaRepository{DB: db}
+}
+
+//
+// -------------------- CINEMAS --------------------
+//
+
+// ListCinemas: termasuk cinema yang sudah di-retire (untuk admin)
+func (r *CinemaRepository) ListCinemas(ctx context.Context) ([]models.Cinema, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, name, address, logo_url, price_tier, is_active
+		FROM cinemas
+		ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cinemas := []models.Cinema{}
+	for rows.Next() {
+		var c models.Cinema
+		if err := rows.Scan(&c.ID, &c.Name, &c.Address, &c.LogoURL, &c.PriceTier, &c.IsActive); err != nil {
+			return nil, err
+		}
+		cinemas = append(cinemas, c)
+	}
+	return cinemas, rows.Err()
+}
+
+func (r *CinemaRepository) GetCinema(ctx context.Context, id int) (*models.Cinema, error) {
+	var c models.Cinema
+	err := r.DB.QueryRow(ctx, `
+		SELECT id, name, address, logo_url, price_tier, is_active
+		FROM cinemas
+		WHERE id = $1`, id).
+		Scan(&c.ID, &c.Name, &c.Address, &c.LogoURL, &c.PriceTier, &c.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CinemaRepository) CreateCinema(ctx context.Context, req models.CinemaRequest) (*models.Cinema, error) {
+	if req.PriceTier == "" {
+		req.PriceTier = "regular"
+	}
+
+	c := models.Cinema{
+		Name:      req.Name,
+		Address:   req.Address,
+		LogoURL:   req.LogoURL,
+		PriceTier: req.PriceTier,
+		IsActive:  true,
+	}
+	err := r.DB.QueryRow(ctx, `
+		INSERT INTO cinemas (name, address, logo_url, price_tier)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`,
+		c.Name, c.Address, c.LogoURL, c.PriceTier).Scan(&c.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *CinemaRepository) UpdateCinema(ctx context.Context, id int, req models.CinemaUpdate) (*models.Cinema, error) {
+	setParts := []string{}
+	args := []interface{}{}
+	i := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", i))
+		args = append(args, *req.Name)
+		i++
+	}
+	if req.Address != nil {
+		setParts = append(setParts, fmt.Sprintf("address = $%d", i))
+		args = append(args, *req.Address)
+		i++
+	}
+	if req.LogoURL != nil {
+		setParts = append(setParts, fmt.Sprintf("logo_url = $%d", i))
+		args = append(args, *req.LogoURL)
+		i++
+	}
+	if req.PriceTier != nil {
+		setParts = append(setParts, fmt.Sprintf("price_tier = $%d", i))
+		args = append(args, *req.PriceTier)
+		i++
+	}
+
+	if len(setParts) > 0 {
+		query := fmt.Sprintf(`UPDATE cinemas SET %s WHERE id = $%d`, strings.Join(setParts, ", "), i)
+		args = append(args, id)
+
+		tag, err := r.DB.Exec(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, pgx.ErrNoRows
+		}
+	}
+
+	return r.GetCinema(ctx, id)
+}
+
+// RetireCinema menonaktifkan cinema. Ditolak jika masih ada jadwal hari ini / ke depan.
+func (r *CinemaRepository) RetireCinema(ctx context.Context, id int) error {
+	return r.retire(ctx, "cinemas", "cinema_id", id)
+}
+
+//
+// -------------------- LOCATIONS --------------------
+//
+
+func (r *CinemaRepository) ListLocations(ctx context.Context) ([]models.Location, error) {
+	rows, err := r.DB.Query(ctx, `SELECT id, location, is_active FROM locations ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	locations := []models.Location{}
+	for rows.Next() {
+		var l models.Location
+		if err := rows.Scan(&l.ID, &l.Name, &l.IsActive); err != nil {
+			return nil, err
+		}
+		locations = append(locations, l)
+	}
+	return locations, rows.Err()
+}
+
+func (r *CinemaRepository) CreateLocation(ctx context.Context, name string) (*models.Location, error) {
+	l := models.Location{Name: name, IsActive: true}
+	err := r.DB.QueryRow(ctx, `
+		INSERT INTO locations (location)
+		VALUES ($1)
+		RETURNING id`, name).Scan(&l.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *CinemaRepository) UpdateLocation(ctx context.Context, id int, name string) (*models.Location, error) {
+	var l models.Location
+	err := r.DB.QueryRow(ctx, `
+		UPDATE locations SET location = $1
+		WHERE id = $2
+		RETURNING id, location, is_active`, name, id).Scan(&l.ID, &l.Name, &l.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *CinemaRepository) RetireLocation(ctx context.Context, id int) error {
+	return r.retire(ctx, "locations", "location_id", id)
+}
+
+//
+// -------------------- TIMES --------------------
+//
+
+func (r *CinemaRepository) ListTimes(ctx context.Context) ([]models.ShowTime, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, TO_CHAR(start_time, 'HH24:MI'), is_active
+		FROM times
+		ORDER BY start_time`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	times := []models.ShowTime{}
+	for rows.Next() {
+		var t models.ShowTime
+		if err := rows.Scan(&t.ID, &t.StartTime, &t.IsActive); err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, rows.Err()
+}
+
+func (r *CinemaRepository) CreateTime(ctx context.Context, startTime string) (*models.ShowTime, error) {
+	var t models.ShowTime
+	err := r.DB.QueryRow(ctx, `
+		INSERT INTO times (start_time)
+		VALUES ($1)
+		RETURNING id, TO_CHAR(start_time, 'HH24:MI'), is_active`, startTime).
+		Scan(&t.ID, &t.StartTime, &t.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *CinemaRepository) UpdateTime(ctx context.Context, id int, startTime string) (*models.ShowTime, error) {
+	var t models.ShowTime
+	err := r.DB.QueryRow(ctx, `
+		UPDATE times SET start_time = $1
+		WHERE id = $2
+		RETURNING id, TO_CHAR(start_time, 'HH24:MI'), is_active`, startTime, id).
+		Scan(&t.ID, &t.StartTime, &t.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *CinemaRepository) RetireTime(ctx context.Context, id int) error {
+	return r.retire(ctx, "times", "time_id", id)
+}
+
+//
+// -------------------- AUDITORIUMS --------------------
+//
+
+func (r *CinemaRepository) ListAuditoriums(ctx context.Context, cinemaID int) ([]models.Auditorium, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, cinema_id, name, format, is_active
+		FROM auditoriums
+		WHERE cinema_id = $1
+		ORDER BY name`, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	auditoriums := []models.Auditorium{}
+	for rows.Next() {
+		var a models.Auditorium
+		if err := rows.Scan(&a.ID, &a.CinemaID, &a.Name, &a.Format, &a.IsActive); err != nil {
+			return nil, err
+		}
+		auditoriums = append(auditoriums, a)
+	}
+	return auditoriums, rows.Err()
+}
+
+func (r *CinemaRepository) GetAuditorium(ctx context.Context, id int) (*models.Auditorium, error) {
+	var a models.Auditorium
+	err := r.DB.QueryRow(ctx, `
+		SELECT id, cinema_id, name, format, is_active
+		FROM auditoriums
+		WHERE id = $1`, id).
+		Scan(&a.ID, &a.CinemaID, &a.Name, &a.Format, &a.IsActive)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *CinemaRepository) CreateAuditorium(ctx context.Context, cinemaID int, req models.AuditoriumRequest) (*models.Auditorium, error) {
+	if req.Format == "" {
+		req.Format = "2D"
+	}
+
+	a := models.Auditorium{CinemaID: cinemaID, Name: req.Name, Format: req.Format, IsActive: true}
+	err := r.DB.QueryRow(ctx, `
+		INSERT INTO auditoriums (cinema_id, name, format)
+		SELECT id, $2, $3 FROM cinemas WHERE id = $1 AND is_active
+		RETURNING id`, cinemaID, req.Name, req.Format).Scan(&a.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *CinemaRepository) UpdateAuditorium(ctx context.Context, id int, req models.AuditoriumUpdate) (*models.Auditorium, error) {
+	setParts := []string{}
+	args := []interface{}{}
+	i := 1
+
+	if req.Name != nil {
+		setParts = append(setParts, fmt.Sprintf("name = $%d", i))
+		args = append(args, *req.Name)
+		i++
+	}
+	if req.Format != nil {
+		setParts = append(setParts, fmt.Sprintf("format = $%d", i))
+		args = append(args, *req.Format)
+		i++
+	}
+
+	if len(setParts) > 0 {
+		query := fmt.Sprintf(`UPDATE auditoriums SET %s WHERE id = $%d`, strings.Join(setParts, ", "), i)
+		args = append(args, id)
+
+		tag, err := r.DB.Exec(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, pgx.ErrNoRows
+		}
+	}
+
+	return r.GetAuditorium(ctx, id)
+}
+
+func (r *CinemaRepository) RetireAuditorium(ctx context.Context, id int) error {
+	return r.retire(ctx, "auditoriums", "auditorium_id", id)
+}
+
+//
+// -------------------- SEATS --------------------
+//
+
+func (r *CinemaRepository) ListSeats(ctx context.Context, auditoriumID int) ([]models.SeatInventory, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, cinema_id, auditorium_id, seat_code, seat_type, is_active
+		FROM seats
+		WHERE auditorium_id = $1
+		ORDER BY seat_code`, auditoriumID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seats := []models.SeatInventory{}
+	for rows.Next() {
+		var s models.SeatInventory
+		if err := rows.Scan(&s.ID, &s.CinemaID, &s.AuditoriumID, &s.SeatCode, &s.SeatType, &s.IsActive); err != nil {
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+	return seats, rows.Err()
+}
+
+// AddSeats menambah kursi ke auditorium. Kursi yang sudah ada (termasuk yang di-retire)
+// akan diaktifkan kembali, jadi aman dipanggil berulang. seatType kosong tidak mengubah jenis kursi lama.
+func (r *CinemaRepository) AddSeats(ctx context.Context, auditoriumID int, seatCodes []string, seatType string) ([]models.SeatInventory, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var cinemaID int
+	var active bool
+	if err := tx.QueryRow(ctx, `
+		SELECT a.cinema_id, a.is_active AND c.is_active
+		FROM auditoriums a
+		JOIN cinemas c ON c.id = a.cinema_id
+		WHERE a.id = $1`, auditoriumID).Scan(&cinemaID, &active); err != nil {
+		return nil, err
+	}
+	if !active {
+		return nil, ErrAuditoriumRetired
+	}
+
+	seats := make([]models.SeatInventory, 0, len(seatCodes))
+	for _, code := range seatCodes {
+		s := models.SeatInventory{CinemaID: cinemaID, AuditoriumID: auditoriumID, SeatCode: code, IsActive: true}
+		err := tx.QueryRow(ctx, `
+			INSERT INTO seats (cinema_id, auditorium_id, seat_code, seat_type)
+			VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), 'standard'))
+			ON CONFLICT (auditorium_id, seat_code) DO UPDATE
+			SET is_active = TRUE, seat_type = COALESCE(NULLIF($4, ''), seats.seat_type)
+			RETURNING id, seat_type`, cinemaID, auditoriumID, code, seatType).Scan(&s.ID, &s.SeatType)
+		if err != nil {
+			return nil, fmt.Errorf("insert seat %s: %w", code, err)
+		}
+		seats = append(seats, s)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
+
+// RetireSeat ditolak jika kursi sudah terjual / sedang dipesan (order paid / pending)
+// untuk jadwal hari ini / ke depan
+func (r *CinemaRepository) RetireSeat(ctx context.Context, seatID int) error {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var auditoriumID int
+	var seatCode string
+	if err := tx.QueryRow(ctx, `
+		SELECT auditorium_id, seat_code FROM seats
+		WHERE id = $1
+		FOR UPDATE`, seatID).Scan(&auditoriumID, &seatCode); err != nil {
+		return err
+	}
+
+	var used bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM order_seats os
+			JOIN orders o ON o.id = os.order_id
+			JOIN schedules s ON s.id = o.schedule_id
+			WHERE s.auditorium_id = $1
+			  AND os.seat_code = $2
+			  AND o.status IN ('paid', 'pending')
+			  AND s.date >= CURRENT_DATE
+		)`, auditoriumID, seatCode).Scan(&used); err != nil {
+		return err
+	}
+	if used {
+		return ErrReferenceInUse
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE seats SET is_active = FALSE WHERE id = $1`, seatID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// retire: soft delete baris di table master, setelah memastikan tidak ada
+// jadwal hari ini / ke depan yang masih memakai baris tersebut.
+// Baris dikunci FOR UPDATE supaya insert schedule baru (FK) menunggu sampai selesai.
+func (r *CinemaRepository) retire(ctx context.Context, table, scheduleColumn string, id int) error {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var lockedID int
+	if err := tx.QueryRow(ctx,
+		fmt.Sprintf(`SELECT id FROM %s WHERE id = $1 FOR UPDATE`, table), id).
+		Scan(&lockedID); err != nil {
+		return err
+	}
+
+	var used bool
+	if err := tx.QueryRow(ctx,
+		fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM schedules WHERE %s = $1 AND date >= CURRENT_DATE)`, scheduleColumn), id).
+		Scan(&used); err != nil {
+		return err
+	}
+	if used {
+		return ErrReferenceInUse
+	}
+
+	if _, err := tx.Exec(ctx,
+		fmt.Sprintf(`UPDATE %s SET is_active = FALSE WHERE id = $1`, table), id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrItemUnavailable: item tidak dijual di cinema jadwal tersebut
+	ErrItemUnavailable = errors.New("concession item is not available at this cinema")
+	// ErrOutOfStock: stok item tidak cukup untuk jumlah yang dipesan
+	ErrOutOfStock = errors.New("concession item is out of stock")
+	// ErrNothingToPickup: tidak ada item pending yang bisa diambil
+	ErrNothingToPickup = errors.New("no pending items to pick up")
+)
+
+const concessionColumns = `id, name, description, category, image_url, is_active`
+
+type ConcessionRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewConcessionRepository(db *pgxpool.Pool) *ConcessionRepository {
+	return &ConcessionRepository{DB: db}
+}
+
+func scanConcessionItem(row pgx.Row) (*models.ConcessionItem, error) {
+	var it models.ConcessionItem
+	if err := row.Scan(&it.ID, &it.Name, &it.Description, &it.Category, &it.ImageURL, &it.IsActive); err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
+func (r *ConcessionRepository) ListItems(ctx context.Context) ([]models.ConcessionItem, error) {
+	rows, err := r.DB.Query(ctx, `SELECT `+concessionColumns+` FROM concession_items ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ConcessionItem{}
+	for rows.Next() {
+		it, err := scanConcessionItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *it)
+	}
+	return items, rows.Err()
+}
+
+func (r *ConcessionRepository) CreateItem(ctx context.Context, req models.ConcessionItemRequest) (*models.ConcessionItem, error) {
+	if req.Category == "" {
+		req.Category = "food"
+	}
+	return scanConcessionItem(r.DB.QueryRow(ctx, `
+		INSERT INTO concession_items (name, description, category, image_url)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+concessionColumns, req.Name, req.Description, req.Category, req.ImageURL))
+}
+
+// UpdateItem: hanya field yang tidak nil yang di-update
+func (r *ConcessionRepository) UpdateItem(ctx context.Context, id int, req models.ConcessionItemUpdate) (*models.ConcessionItem, error) {
+	sets := []string{}
+	args := []any{}
+	add := func(column string, value any) {
+		args = append(args, value)
+		sets = append(sets, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if req.Name != nil {
+		add("name", *req.Name)
+	}
+	if req.Description != nil {
+		add("description", *req.Description)
+	}
+	if req.Category != nil {
+		add("category", *req.Category)
+	}
+	if req.ImageURL != nil {
+		add("image_url", *req.ImageURL)
+	}
+	if req.IsActive != nil {
+		add("is_active", *req.IsActive)
+	}
+	if len(sets) == 0 {
+		return scanConcessionItem(r.DB.QueryRow(ctx, `SELECT `+concessionColumns+` FROM concession_items WHERE id = $1`, id))
+	}
+
+	args = append(args, id)
+	return scanConcessionItem(r.DB.QueryRow(ctx, fmt.Sprintf(`
+		UPDATE concession_items SET %s WHERE id = $%d
+		RETURNING `+concessionColumns, strings.Join(sets, ", "), len(args)), args...))
+}
+
+// DeactivateItem: item tidak dihapus karena masih direferensikan order_items
+func (r *ConcessionRepository) DeactivateItem(ctx context.Context, id int) error {
+	tag, err := r.DB.Exec(ctx, `UPDATE concession_items SET is_active = FALSE WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// CinemaMenu: item di satu cinema. all = false hanya item aktif, tersedia dan stoknya belum habis.
+func (r *ConcessionRepository) CinemaMenu(ctx context.Context, cinemaID int, all bool) ([]models.CinemaConcession, error) {
+	query := `
+		SELECT i.id, i.name, i.description, i.category, i.image_url, i.is_active,
+			cc.cinema_id, cc.price, cc.stock, cc.is_available
+		FROM cinema_concessions cc
+		JOIN concession_items i ON i.id = cc.item_id
+		WHERE cc.cinema_id = $1`
+	if !all {
+		query += ` AND i.is_active AND cc.is_available AND (cc.stock IS NULL OR cc.stock > 0)`
+	}
+	query += ` ORDER BY i.category, i.name`
+
+	rows, err := r.DB.Query(ctx, query, cinemaID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	menu := []models.CinemaConcession{}
+	for rows.Next() {
+		var m models.CinemaConcession
+		if err := rows.Scan(&m.ID, &m.Name, &m.Description, &m.Category, &m.ImageURL, &m.IsActive,
+			&m.CinemaID, &m.Price, &m.Stock, &m.IsAvailable); err != nil {
+			return nil, err
+		}
+		menu = append(menu, m)
+	}
+	return menu, rows.Err()
+}
+
+// SetCinemaItem mengatur harga, stok dan ketersediaan item di satu cinema
+func (r *ConcessionRepository) SetCinemaItem(ctx context.Context, cinemaID, itemID int, req models.CinemaConcessionRequest) (*models.CinemaConcession, error) {
+	isAvailable := req.IsAvailable == nil || *req.IsAvailable
+	if _, err := r.DB.Exec(ctx, `
+		INSERT INTO cinema_concessions (cinema_id, item_id, price, stock, is_available)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (cinema_id, item_id) DO UPDATE
+		SET price = EXCLUDED.price, stock = EXCLUDED.stock, is_available = EXCLUDED.is_available`,
+		cinemaID, itemID, req.Price, req.Stock, isAvailable); err != nil {
+		// cinema atau item tidak ada
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, err
+	}
+
+	var m models.CinemaConcession
+	err := r.DB.QueryRow(ctx, `
+		SELECT i.id, i.name, i.description, i.category, i.image_url, i.is_active,
+			cc.cinema_id, cc.price, cc.stock, cc.is_available
+		FROM cinema_concessions cc
+		JOIN concession_items i ON i.id = cc.item_id
+		WHERE cc.cinema_id = $1 AND cc.item_id = $2`, cinemaID, itemID).
+		Scan(&m.ID, &m.Name, &m.Description, &m.Category, &m.ImageURL, &m.IsActive,
+			&m.CinemaID, &m.Price, &m.Stock, &m.IsAvailable)
+	return &m, err
+}
+
+// priceItems menghitung add-on dengan harga cinema jadwal tersebut.
+// Item yang sama digabung; stok hanya diperiksa, pengurangannya di reserveItems.
+func priceItems(ctx context.Context, q querier, cinemaID int, reqs []models.OrderItemRequest) ([]models.OrderItem, int, error) {
+	items := []models.OrderItem{}
+	index := map[int]int{}
+	for _, req := range reqs {
+		if i, ok := index[req.ItemID]; ok {
+			items[i].Quantity += req.Quantity
+			continue
+		}
+		index[req.ItemID] = len(items)
+		items = append(items, models.OrderItem{ItemID: req.ItemID, Quantity: req.Quantity})
+	}
+
+	total := 0
+	for i := range items {
+		it := &items[i]
+		var stock *int
+		err := q.QueryRow(ctx, `
+			SELECT ci.name, cc.price, cc.stock
+			FROM cinema_concessions cc
+			JOIN concession_items ci ON ci.id = cc.item_id
+			WHERE cc.cinema_id = $1 AND cc.item_id = $2 AND ci.is_active AND cc.is_available`,
+			cinemaID, it.ItemID).Scan(&it.Name, &it.UnitPrice, &stock)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, 0, fmt.Errorf("%w: item %d", ErrItemUnavailable, it.ItemID)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if stock != nil && *stock < it.Quantity {
+			return nil, 0, fmt.Errorf("%w: %s", ErrOutOfStock, it.Name)
+		}
+		it.Total = it.UnitPrice * it.Quantity
+		total += it.Total
+	}
+	return items, total, nil
+}
+
+// reserveItems mengurangi stok dan menyimpan add-on order. Pengurangan stok bersyarat
+// sehingga order yang berjalan bersamaan tidak bisa membuat stok negatif.
+func reserveItems(ctx context.Context, q querier, orderID, cinemaID int, items []models.OrderItem) error {
+	for i, it := range items {
+		tag, err := q.Exec(ctx, `
+			UPDATE cinema_concessions SET stock = stock - $3
+			WHERE cinema_id = $1 AND item_id = $2 AND (stock IS NULL OR stock >= $3)`, cinemaID, it.ItemID, it.Quantity)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: %s", ErrOutOfStock, it.Name)
+		}
+		if err := q.QueryRow(ctx, `
+			INSERT INTO order_items (order_id, item_id, name, unit_price, quantity)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, status`, orderID, it.ItemID, it.Name, it.UnitPrice, it.Quantity).
+			Scan(&items[i].ID, &items[i].Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restockItems dipakai saat order di-refund / gagal: item yang belum diambil
+// dibatalkan dan stoknya dikembalikan. Aman dipanggil berkali-kali.
+func restockItems(ctx context.Context, q querier, orderID int) error {
+	_, err := q.Exec(ctx, `
+		WITH cancelled AS (
+			UPDATE order_items SET status = 'cancelled'
+			WHERE order_id = $1 AND status = 'pending'
+			RETURNING item_id, quantity
+		)
+		UPDATE cinema_concessions cc SET stock = cc.stock + c.quantity
+		FROM cancelled c, orders o
+		JOIN schedules s ON s.id = o.schedule_id
+		WHERE o.id = $1 AND cc.cinema_id = s.cinema_id AND cc.item_id = c.item_id AND cc.stock IS NOT NULL`, orderID)
+	return err
+}
+
+// orderItems: add-on satu order
+func orderItems(ctx context.Context, q querier, orderID int) ([]models.OrderItem, error) {
+	rows, err := q.Query(ctx, `
+		SELECT id, item_id, name, unit_price, quantity, unit_price * quantity, status, fulfilled_at
+		FROM order_items
+		WHERE order_id = $1
+		ORDER BY id`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.OrderItem{}
+	for rows.Next() {
+		var it models.OrderItem
+		if err := rows.Scan(&it.ID, &it.ItemID, &it.Name, &it.UnitPrice, &it.Quantity, &it.Total, &it.Status, &it.FulfilledAt); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// PickupOrder: order beserta add-on untuk ditampilkan ke staff
+func (r *ConcessionRepository) PickupOrder(ctx context.Context, orderID int) (*models.PickupOrder, error) {
+	p := models.PickupOrder{OrderID: orderID}
+	err := r.DB.QueryRow(ctx, `
+		SELECT o.user_id, m.title, s.cinema_id, o.status
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedule_id
+		JOIN movies m ON m.id = s.movie_id
+		WHERE o.id = $1`, orderID).Scan(&p.UserID, &p.MovieTitle, &p.CinemaID, &p.Status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.Items, err = orderItems(ctx, r.DB, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Fulfill menandai add-on sudah diambil. itemIDs kosong = semua item pending.
+// Hanya order paid yang bisa diambil.
+func (r *ConcessionRepository) Fulfill(ctx context.Context, orderID, staffID int, itemIDs []int) (*models.PickupOrder, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1 FOR UPDATE`, orderID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if status != "paid" {
+		return nil, ErrNothingToPickup
+	}
+
+	query := `
+		UPDATE order_items SET status = 'fulfilled', fulfilled_at = NOW(), fulfilled_by = $2
+		WHERE order_id = $1 AND status = 'pending'`
+	args := []any{orderID, staffID}
+	if len(itemIDs) > 0 {
+		query += ` AND id = ANY($3)`
+		args = append(args, itemIDs)
+	}
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNothingToPickup
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.PickupOrder(ctx, orderID)
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristian-yw/Weekly10/internal/export"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ExportRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewExportRepository(db *pgxpool.Pool) *ExportRepository {
+	return &ExportRepository{DB: db}
+}
+
+// exportQueries: $1 = from, $2 = to, $3 = cinema_id (0 = semua cinema).
+// Nama kolom hasil query menjadi header file export.
+var exportQueries = map[string]string{
+	// filter tanggal = release_date, filter cinema = punya jadwal di cinema tsb
+	"movies": `
+		SELECT m.id, m.tmdb_id, m.title,
+		       TO_CHAR(m.release_date, 'YYYY-MM-DD') AS release_date,
+		       m.runtime,
+		       (SELECT string_agg(g.name, '|' ORDER BY g.name)
+		          FROM movie_genres mg JOIN genres g ON g.id = mg.genre_id
+		         WHERE mg.movie_id = m.id) AS genres,
+		       m.popularity::float8 AS popularity,
+		       m.vote_average::float8 AS vote_average,
+		       m.vote_count
+		FROM movies m
+		WHERE ($1::date IS NULL OR m.release_date >= $1::date)
+		  AND ($2::date IS NULL OR m.release_date <= $2::date)
+		  AND ($3::int = 0 OR EXISTS (SELECT 1 FROM schedules s WHERE s.movie_id = m.id AND s.cinema_id = $3))
+		ORDER BY m.id`,
+
+	"schedules": `
+		SELECT s.id,
+		       TO_CHAR(s.date, 'YYYY-MM-DD') AS date,
+		       TO_CHAR(t.start_time, 'HH24:MI') AS start_time,
+		       m.id AS movie_id, m.title AS movie,
+		       c.id AS cinema_id, c.name AS cinema,
+		       a.name AS auditorium, a.format,
+		       l.location,
+		       s.price,
+		       (SELECT COUNT(*) FROM order_seats os JOIN orders o ON o.id = os.order_id
+		         WHERE o.schedule_id = s.id AND o.status = 'paid') AS tickets_sold
+		FROM schedules s
+		JOIN movies m ON m.id = s.movie_id
+		JOIN cinemas c ON c.id = s.cinema_id
+		JOIN auditoriums a ON a.id = s.auditorium_id
+		JOIN locations l ON l.id = s.location_id
+		JOIN times t ON t.id = s.time_id
+		WHERE ($1::date IS NULL OR s.date >= $1::date)
+		  AND ($2::date IS NULL OR s.date <= $2::date)
+		  AND ($3::int = 0 OR s.cinema_id = $3)
+		ORDER BY s.date, t.start_time, s.id`,
+
+	"orders": `
+		SELECT o.id,
+		       TO_CHAR(o.order_date, 'YYYY-MM-DD"T"HH24:MI:SS') AS order_date,
+		       o.status,
+		       o.user_id, u.email,
+		       o.schedule_id,
+		       TO_CHAR(s.date, 'YYYY-MM-DD') AS schedule_date,
+		       m.title AS movie,
+		       c.name AS cinema,
+		       l.location,
+		       (SELECT string_agg(os.seat_code, '|' ORDER BY os.seat_code)
+		          FROM order_seats os WHERE os.order_id = o.id) AS seats,
+		       (SELECT COUNT(*) FROM order_seats os WHERE os.order_id = o.id) AS tickets,
+		       (SELECT string_agg(tt.ticket_type || ':' || tt.n, '|' ORDER BY tt.ticket_type)
+		          FROM (SELECT os.ticket_type, COUNT(*) AS n FROM order_seats os
+		                 WHERE os.order_id = o.id GROUP BY os.ticket_type) tt) AS ticket_types,
+		       o.total_price
+		FROM orders o
+		JOIN users u ON u.id = o.user_id
+		JOIN schedules s ON s.id = o.schedule_id
+		JOIN movies m ON m.id = s.movie_id
+		JOIN cinemas c ON c.id = s.cinema_id
+		JOIN locations l ON l.id = s.location_id
+		WHERE ($1::date IS NULL OR o.order_date >= $1::date)
+		  AND ($2::date IS NULL OR o.order_date < $2::date + 1)
+		  AND ($3::int = 0 OR s.cinema_id = $3)
+		ORDER BY o.order_date, o.id`,
+}
+
+// ExportDatasets: nama dataset yang bisa di-export
+func ExportDatasets() []string {
+	return []string{"movies", "schedules", "orders"}
+}
+
+// Export menjalankan query dataset dan menulis setiap baris langsung ke w
+// (rows dibaca satu per satu dari pgx, tidak ditampung di memori)
+func (r *ExportRepository) Export(ctx context.Context, dataset string, filter models.ExportFilter, w export.Writer) error {
+	query, ok := exportQueries[dataset]
+	if !ok {
+		return fmt.Errorf("unknown export dataset %q", dataset)
+	}
+
+	rows, err := r.DB.Query(ctx, query, filter.From, filter.To, filter.CinemaID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	fields := rows.FieldDescriptions()
+	columns := make([]string, len(fields))
+	for i, f := range fields {
+		columns[i] = f.Name
+	}
+	if err := w.WriteHeader(columns); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return err
+		}
+		if err := w.WriteRow(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
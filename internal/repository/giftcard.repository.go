@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/giftcard"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrGiftCardVoided: gift card yang sudah di-void tidak bisa di-void lagi
+var ErrGiftCardVoided = errors.New("gift card is already voided")
+
+const giftCardColumns = `id, code, initial_amount, status, source, purchase_order_id, recipient_email,
+	message, expires_at, void_reason, created_at, voided_at`
+
+type GiftCardRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewGiftCardRepository(db *pgxpool.Pool) *GiftCardRepository {
+	return &GiftCardRepository{DB: db}
+}
+
+func scanGiftCard(row pgx.Row) (*models.GiftCard, error) {
+	var g models.GiftCard
+	err := row.Scan(&g.ID, &g.Code, &g.InitialAmount, &g.Status, &g.Source, &g.PurchaseOrderID, &g.RecipientEmail,
+		&g.Message, &g.ExpiresAt, &g.VoidReason, &g.CreatedAt, &g.VoidedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// findGiftCard mencari gift card beserta saldonya; lock = FOR UPDATE.
+// pgx.ErrNoRows diganti giftcard.ErrNotFound.
+func findGiftCard(ctx context.Context, q querier, code string, lock bool) (*models.GiftCard, error) {
+	query := `SELECT ` + giftCardColumns + ` FROM gift_cards WHERE code = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	g, err := scanGiftCard(q.QueryRow(ctx, query, giftcard.Normalize(code)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, giftcard.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := q.QueryRow(ctx, `SELECT COALESCE(SUM(amount), 0) FROM gift_card_ledger WHERE gift_card_id = $1`, g.ID).
+		Scan(&g.Balance); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// usableGiftCard: gift card aktif, belum kadaluarsa dan masih ada saldo
+func usableGiftCard(ctx context.Context, q querier, code string, lock bool) (*models.GiftCard, error) {
+	g, err := findGiftCard(ctx, q, code, lock)
+	switch {
+	case err != nil:
+		return nil, err
+	case g.Status == "voided":
+		return nil, giftcard.ErrVoided
+	case g.ExpiresAt != nil && !time.Now().Before(*g.ExpiresAt):
+		return nil, giftcard.ErrExpired
+	case g.Balance <= 0:
+		return nil, giftcard.ErrEmpty
+	}
+	return g, nil
+}
+
+// issueGiftCard membuat gift card baru beserta entri ledger 'issue'.
+// orderID diisi untuk gift card yang dibeli lewat order.
+func issueGiftCard(ctx context.Context, q querier, source string, issuedBy int, orderID *int,
+	req models.GiftCardPurchase, expiresAt *time.Time) (*models.GiftCard, error) {
+	var g *models.GiftCard
+	// kode acak 80 bit, bentrok hampir tidak mungkin tetapi tetap dicoba ulang
+	for attempt := 0; ; attempt++ {
+		code, err := giftcard.NewCode()
+		if err != nil {
+			return nil, err
+		}
+		g, err = scanGiftCard(q.QueryRow(ctx, `
+			INSERT INTO gift_cards (code, initial_amount, source, purchase_order_id, issued_by, recipient_email, message, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (code) DO NOTHING
+			RETURNING `+giftCardColumns,
+			code, req.Amount, source, orderID, issuedBy, req.RecipientEmail, req.Message, expiresAt))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, pgx.ErrNoRows) || attempt == 2 {
+			return nil, err
+		}
+	}
+
+	if _, err := q.Exec(ctx, `
+		INSERT INTO gift_card_ledger (gift_card_id, order_id, kind, amount, created_by)
+		VALUES ($1, $2, 'issue', $3, $4)`, g.ID, orderID, g.InitialAmount, issuedBy); err != nil {
+		return nil, err
+	}
+	g.Balance = g.InitialAmount
+	return g, nil
+}
+
+// redeemGiftCard mengurangi saldo untuk order dan mencatat pembayarannya
+func redeemGiftCard(ctx context.Context, q querier, giftCardID, orderID, userID, amount int) error {
+	if _, err := q.Exec(ctx, `
+		INSERT INTO gift_card_ledger (gift_card_id, order_id, kind, amount, created_by)
+		VALUES ($1, $2, 'redeem', $3, $4)`, giftCardID, orderID, -amount, userID); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, `
+		INSERT INTO order_payments (order_id, method, gift_card_id, amount)
+		VALUES ($1, 'gift_card', $2, $3)`, orderID, giftCardID, amount)
+	return err
+}
+
+// refundGiftCards dipakai saat order di-refund / gagal: saldo yang dipakai dikembalikan
+// dan gift card yang dibeli lewat order tersebut di-void. Aman dipanggil berkali-kali.
+func refundGiftCards(ctx context.Context, q querier, orderID int) error {
+	if _, err := q.Exec(ctx, `
+		INSERT INTO gift_card_ledger (gift_card_id, order_id, kind, amount)
+		SELECT gift_card_id, order_id, 'refund', -amount
+		FROM gift_card_ledger
+		WHERE order_id = $1 AND kind = 'redeem'
+		ON CONFLICT (gift_card_id, order_id, kind) WHERE order_id IS NOT NULL DO NOTHING`, orderID); err != nil {
+		return err
+	}
+
+	// sisa saldo gift card yang dibeli dihapus; yang sudah terpakai tidak bisa ditarik
+	if _, err := q.Exec(ctx, `
+		INSERT INTO gift_card_ledger (gift_card_id, order_id, kind, amount)
+		SELECT id, $1, 'void', -balance
+		FROM (
+			SELECT g.id, (SELECT COALESCE(SUM(l.amount), 0) FROM gift_card_ledger l WHERE l.gift_card_id = g.id) AS balance
+			FROM gift_cards g
+			WHERE g.purchase_order_id = $1 AND g.status = 'active'
+		) b
+		WHERE balance > 0
+		ON CONFLICT (gift_card_id, order_id, kind) WHERE order_id IS NOT NULL DO NOTHING`, orderID); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, `
+		UPDATE gift_cards SET status = 'voided', void_reason = 'order refunded', voided_at = NOW()
+		WHERE purchase_order_id = $1 AND status = 'active'`, orderID)
+	return err
+}
+
+// Issue: gift card yang diterbitkan admin
+func (r *GiftCardRepository) Issue(ctx context.Context, adminID int, req models.GiftCardIssueRequest) (*models.GiftCard, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	g, err := issueGiftCard(ctx, tx, "admin", adminID, nil, req.GiftCardPurchase, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return g, tx.Commit(ctx)
+}
+
+// Lookup: gift card beserta saldo dan seluruh ledger
+func (r *GiftCardRepository) Lookup(ctx context.Context, code string) (*models.GiftCard, error) {
+	g, err := findGiftCard(ctx, r.DB, code, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, order_id, kind, amount, created_by, created_at
+		FROM gift_card_ledger
+		WHERE gift_card_id = $1
+		ORDER BY created_at, id`, g.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	g.Ledger = []models.GiftCardEntry{}
+	for rows.Next() {
+		var e models.GiftCardEntry
+		if err := rows.Scan(&e.ID, &e.OrderID, &e.Kind, &e.Amount, &e.CreatedBy, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		g.Ledger = append(g.Ledger, e)
+	}
+	return g, rows.Err()
+}
+
+// Balance: saldo untuk user, kode disamarkan
+func (r *GiftCardRepository) Balance(ctx context.Context, code string) (*models.GiftCardBalance, error) {
+	g, err := findGiftCard(ctx, r.DB, code, false)
+	if err != nil {
+		return nil, err
+	}
+	return &models.GiftCardBalance{Code: giftcard.Mask(g.Code), Balance: g.Balance, Status: g.Status, ExpiresAt: g.ExpiresAt}, nil
+}
+
+// Void menonaktifkan gift card dan menghapus sisa saldonya lewat entri ledger 'void'
+func (r *GiftCardRepository) Void(ctx context.Context, code string, adminID int, reason string) (*models.GiftCard, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	g, err := findGiftCard(ctx, tx, code, true)
+	if err != nil {
+		return nil, err
+	}
+	if g.Status == "voided" {
+		return nil, ErrGiftCardVoided
+	}
+
+	if g.Balance != 0 {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO gift_card_ledger (gift_card_id, kind, amount, created_by)
+			VALUES ($1, 'void', $2, $3)`, g.ID, -g.Balance, adminID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE gift_cards SET status = 'voided', void_reason = $2, voided_at = NOW()
+		WHERE id = $1`, g.ID, reason); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.Lookup(ctx, g.Code)
+}
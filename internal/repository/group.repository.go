@@ -0,0 +1,366 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrGroupNotFound  = errors.New("group booking not found")
+	ErrGroupClosed    = errors.New("group booking is no longer open")
+	ErrNotOrganiser   = errors.New("only the organiser can do this")
+	ErrShowStarted    = errors.New("schedule has already started")
+	ErrSeatBooked     = errors.New("seat is already booked")
+	ErrSeatNotInGroup = errors.New("seat is not part of this group booking")
+	ErrSeatClaimed    = errors.New("seat is already claimed by another member")
+	ErrAlreadyClaimed = errors.New("you already claimed a seat in this group booking")
+	ErrNoClaim        = errors.New("claim a seat first")
+)
+
+// ReleasedGroup: group booking yang ditutup beserta kursi yang belum dibayar
+type ReleasedGroup struct {
+	ID         int
+	ScheduleID int
+	Seats      []string
+}
+
+func newGroupToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// CreateGroup menyimpan group booking. Deadline dipotong ke jam tayang, dan kursi
+// yang sudah terjual ditolak. Hold kursi di Redis diatur oleh pemanggil.
+func (r *OrderRepository) CreateGroup(ctx context.Context, organiserID int, req models.GroupBookingRequest, deadline time.Time) (*models.GroupBooking, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, `
+		SELECT LEAST($2::timestamptz, (s.date + t.start_time)::timestamptz)
+		FROM schedules s
+		JOIN times t ON t.id = s.time_id
+		WHERE s.id = $1`, req.ScheduleID, deadline).Scan(&deadline)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !deadline.After(time.Now()) {
+		return nil, ErrShowStarted
+	}
+
+	if err := checkSeats(ctx, tx, req.ScheduleID, req.Seats); err != nil {
+		return nil, err
+	}
+
+	token, err := newGroupToken()
+	if err != nil {
+		return nil, err
+	}
+	var groupID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO group_bookings (token, organiser_id, schedule_id, deadline)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`, token, organiserID, req.ScheduleID, deadline).Scan(&groupID); err != nil {
+		return nil, err
+	}
+	for _, seat := range req.Seats {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO group_booking_seats (group_id, seat_code)
+			VALUES ($1, $2)
+			ON CONFLICT DO NOTHING`, groupID, seat); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.GetGroup(ctx, token, organiserID)
+}
+
+// GetGroup: group booking beserta status setiap kursi dari sudut pandang viewerID
+func (r *OrderRepository) GetGroup(ctx context.Context, token string, viewerID int) (*models.GroupBooking, error) {
+	g := models.GroupBooking{Token: token}
+	err := r.DB.QueryRow(ctx, `
+		SELECT g.id, g.organiser_id, g.schedule_id, m.title, g.status, g.deadline, g.created_at
+		FROM group_bookings g
+		JOIN schedules s ON s.id = g.schedule_id
+		JOIN movies m ON m.id = s.movie_id
+		WHERE g.token = $1`, token).
+		Scan(&g.ID, &g.OrganiserID, &g.ScheduleID, &g.MovieTitle, &g.Status, &g.Deadline, &g.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.Query(ctx, `
+		SELECT seat_code, claimed_by, claimed_at, order_id
+		FROM group_booking_seats
+		WHERE group_id = $1
+		ORDER BY seat_code`, g.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	g.Seats = []models.GroupSeat{}
+	for rows.Next() {
+		var s models.GroupSeat
+		var claimedBy *int
+		if err := rows.Scan(&s.Seat, &claimedBy, &s.ClaimedAt, &s.OrderID); err != nil {
+			return nil, err
+		}
+		s.Mine = claimedBy != nil && *claimedBy == viewerID
+		switch {
+		case s.OrderID != nil:
+			s.Status = "paid"
+		case g.Status != "open":
+			s.Status = "released"
+		case claimedBy != nil:
+			s.Status = "claimed"
+		default:
+			s.Status = "open"
+		}
+		// order milik anggota lain tidak ditampilkan
+		if !s.Mine {
+			s.OrderID = nil
+		}
+		g.Seats = append(g.Seats, s)
+	}
+	return &g, rows.Err()
+}
+
+// lockOpenGroup mengunci group booking yang masih open dan belum lewat deadline
+func lockOpenGroup(ctx context.Context, q querier, groupID int) error {
+	var status string
+	var deadline time.Time
+	err := q.QueryRow(ctx, `SELECT status, deadline FROM group_bookings WHERE id = $1 FOR UPDATE`, groupID).
+		Scan(&status, &deadline)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrGroupNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if status != "open" || !deadline.After(time.Now()) {
+		return ErrGroupClosed
+	}
+	return nil
+}
+
+func (r *OrderRepository) groupID(ctx context.Context, token string) (int, error) {
+	var id int
+	err := r.DB.QueryRow(ctx, `SELECT id FROM group_bookings WHERE token = $1`, token).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrGroupNotFound
+	}
+	return id, err
+}
+
+// ClaimSeat: satu kursi per anggota; klaim ulang kursi yang sama tidak berpengaruh
+func (r *OrderRepository) ClaimSeat(ctx context.Context, token string, userID int, seat string) (*models.GroupBooking, error) {
+	groupID, err := r.groupID(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockOpenGroup(ctx, tx, groupID); err != nil {
+		return nil, err
+	}
+	var claimedBy *int
+	err = tx.QueryRow(ctx, `
+		SELECT claimed_by FROM group_booking_seats WHERE group_id = $1 AND seat_code = $2`, groupID, seat).Scan(&claimedBy)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrSeatNotInGroup
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case claimedBy != nil && *claimedBy == userID:
+		return r.GetGroup(ctx, token, userID)
+	case claimedBy != nil:
+		return nil, ErrSeatClaimed
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE group_booking_seats SET claimed_by = $3, claimed_at = NOW()
+		WHERE group_id = $1 AND seat_code = $2`, groupID, seat, userID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrAlreadyClaimed
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return r.GetGroup(ctx, token, userID)
+}
+
+// UnclaimSeat melepas klaim anggota yang belum dibayar
+func (r *OrderRepository) UnclaimSeat(ctx context.Context, token string, userID int) (*models.GroupBooking, error) {
+	groupID, err := r.groupID(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := r.DB.Exec(ctx, `
+		UPDATE group_booking_seats SET claimed_by = NULL, claimed_at = NULL
+		WHERE group_id = $1 AND claimed_by = $2 AND order_id IS NULL`, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, ErrNoClaim
+	}
+	return r.GetGroup(ctx, token, userID)
+}
+
+// ClaimedSeat: group dan kursi yang diklaim user dan belum dibayar
+func (r *OrderRepository) ClaimedSeat(ctx context.Context, token string, userID int) (groupID, scheduleID int, seat string, err error) {
+	err = r.DB.QueryRow(ctx, `
+		SELECT g.id, g.schedule_id, gs.seat_code
+		FROM group_bookings g
+		JOIN group_booking_seats gs ON gs.group_id = g.id
+		WHERE g.token = $1 AND gs.claimed_by = $2 AND gs.order_id IS NULL`, token, userID).
+		Scan(&groupID, &scheduleID, &seat)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := r.groupID(ctx, token); err != nil {
+			return 0, 0, "", err
+		}
+		return 0, 0, "", ErrNoClaim
+	}
+	return groupID, scheduleID, seat, err
+}
+
+// checkGroupClaim dipakai CreateOrder: group masih open dan semua kursi diklaim userID dan belum dibayar
+func checkGroupClaim(ctx context.Context, q querier, groupID, userID int, seats []string) error {
+	if err := lockOpenGroup(ctx, q, groupID); err != nil {
+		return err
+	}
+	for _, seat := range seats {
+		var claimedBy, orderID *int
+		err := q.QueryRow(ctx, `
+			SELECT claimed_by, order_id FROM group_booking_seats
+			WHERE group_id = $1 AND seat_code = $2`, groupID, seat).Scan(&claimedBy, &orderID)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrSeatNotInGroup
+		case err != nil:
+			return err
+		case orderID != nil:
+			return fmt.Errorf("%w: %s", ErrSeatBooked, seat)
+		case claimedBy == nil || *claimedBy != userID:
+			return ErrNoClaim
+		}
+	}
+	return nil
+}
+
+// markGroupPaid menghubungkan kursi dengan order; group selesai jika semua kursi sudah dibayar
+func markGroupPaid(ctx context.Context, q querier, groupID, orderID int, seats []string) error {
+	if _, err := q.Exec(ctx, `
+		UPDATE group_booking_seats SET order_id = $2
+		WHERE group_id = $1 AND seat_code = ANY($3)`, groupID, orderID, seats); err != nil {
+		return err
+	}
+	_, err := q.Exec(ctx, `
+		UPDATE group_bookings SET status = 'completed', closed_at = NOW()
+		WHERE id = $1 AND NOT EXISTS (
+			SELECT 1 FROM group_booking_seats WHERE group_id = $1 AND order_id IS NULL
+		)`, groupID)
+	return err
+}
+
+// CancelGroup: organiser membatalkan group, kursi yang belum dibayar dikembalikan untuk dilepas
+func (r *OrderRepository) CancelGroup(ctx context.Context, token string, organiserID int) (*ReleasedGroup, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	g := ReleasedGroup{}
+	var owner int
+	var status string
+	err = tx.QueryRow(ctx, `
+		SELECT id, schedule_id, organiser_id, status FROM group_bookings WHERE token = $1 FOR UPDATE`, token).
+		Scan(&g.ID, &g.ScheduleID, &owner, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case owner != organiserID:
+		return nil, ErrNotOrganiser
+	case status != "open":
+		return nil, ErrGroupClosed
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE group_bookings SET status = 'cancelled', closed_at = NOW() WHERE id = $1`, g.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(array_agg(seat_code ORDER BY seat_code), '{}')
+		FROM group_booking_seats
+		WHERE group_id = $1 AND order_id IS NULL`, g.ID).Scan(&g.Seats); err != nil {
+		return nil, err
+	}
+	return &g, tx.Commit(ctx)
+}
+
+// CloseExpiredGroups menutup group yang lewat deadline. UPDATE ... RETURNING memastikan
+// setiap group hanya ditutup oleh satu replica.
+func (r *OrderRepository) CloseExpiredGroups(ctx context.Context) ([]ReleasedGroup, error) {
+	rows, err := r.DB.Query(ctx, `
+		WITH closed AS (
+			UPDATE group_bookings SET status = 'closed', closed_at = NOW()
+			WHERE status = 'open' AND deadline <= NOW()
+			RETURNING id, schedule_id
+		)
+		SELECT c.id, c.schedule_id,
+		       COALESCE(array_agg(gs.seat_code ORDER BY gs.seat_code) FILTER (WHERE gs.order_id IS NULL), '{}')
+		FROM closed c
+		JOIN group_booking_seats gs ON gs.group_id = c.id
+		GROUP BY c.id, c.schedule_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []ReleasedGroup
+	for rows.Next() {
+		var g ReleasedGroup
+		if err := rows.Scan(&g.ID, &g.ScheduleID, &g.Seats); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// importRefs: data referensi aktif untuk validasi schedule import
+type importRefs struct {
+	auditoriumCinema map[int]int // auditorium_id -> cinema_id
+	cinemaDefault    map[int]int // cinema_id -> studio aktif pertama
+	locations        map[int]bool
+	times            map[int]bool
+}
+
+// ImportMovies memvalidasi referensi setiap schedule lalu menyimpan semua movie
+// dalam satu transaksi. Movie di-upsert berdasarkan tmdb_id dan schedule yang
+// sudah ada dilewati, jadi file yang sama aman di-import ulang.
+// Dry run menjalankan transaksi yang sama lalu rollback, sehingga angka di
+// report sama persis dengan hasil commit.
+func (r *AdminRepository) ImportMovies(ctx context.Context, movies []models.ImportMovie, dryRun bool) (*models.ImportReport, error) {
+	report := &models.ImportReport{DryRun: dryRun, Movies: len(movies), Errors: []models.ImportError{}}
+
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	refs, err := loadImportRefs(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range movies {
+		for j := range movies[i].Schedules {
+			report.Schedules++
+			if e := refs.resolve(&movies[i].Schedules[j]); e != nil {
+				report.Errors = append(report.Errors, *e)
+			}
+		}
+	}
+	if len(report.Errors) > 0 {
+		return report, nil
+	}
+
+	for _, m := range movies {
+		created, schedulesCreated, err := importMovie(ctx, tx, m)
+		if err != nil {
+			report.Errors = append(report.Errors, models.ImportError{Row: m.Row, Message: err.Error()})
+			return report, nil
+		}
+		if created {
+			report.MoviesCreated++
+		} else {
+			report.MoviesUpdated++
+		}
+		report.SchedulesCreated += schedulesCreated
+	}
+	report.SchedulesSkipped = report.Schedules - report.SchedulesCreated
+
+	if dryRun {
+		return report, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	report.Applied = true
+	return report, nil
+}
+
+func loadImportRefs(ctx context.Context, tx pgx.Tx) (*importRefs, error) {
+	refs := &importRefs{
+		auditoriumCinema: map[int]int{},
+		cinemaDefault:    map[int]int{},
+		locations:        map[int]bool{},
+		times:            map[int]bool{},
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT a.id, a.cinema_id
+		FROM auditoriums a
+		JOIN cinemas c ON c.id = a.cinema_id
+		WHERE a.is_active AND c.is_active
+		ORDER BY a.id`)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var id, cinemaID int
+		if err := rows.Scan(&id, &cinemaID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		refs.auditoriumCinema[id] = cinemaID
+		if _, ok := refs.cinemaDefault[cinemaID]; !ok {
+			refs.cinemaDefault[cinemaID] = id
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for table, target := range map[string]map[int]bool{"locations": refs.locations, "times": refs.times} {
+		ids, err := tx.Query(ctx, `SELECT id FROM `+table+` WHERE is_active`)
+		if err != nil {
+			return nil, err
+		}
+		for ids.Next() {
+			var id int
+			if err := ids.Scan(&id); err != nil {
+				ids.Close()
+				return nil, err
+			}
+			target[id] = true
+		}
+		ids.Close()
+		if err := ids.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return refs, nil
+}
+
+// resolve memeriksa referensi schedule dan mengisi auditorium default jika kosong
+func (refs *importRefs) resolve(s *models.ImportSchedule) *models.ImportError {
+	fail := func(field, format string, args ...interface{}) *models.ImportError {
+		return &models.ImportError{Row: s.Row, Field: field, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if s.AuditoriumID != 0 {
+		cinemaID, ok := refs.auditoriumCinema[s.AuditoriumID]
+		if !ok {
+			return fail("auditorium_id", "auditorium %d not found or inactive", s.AuditoriumID)
+		}
+		if s.CinemaID != 0 && s.CinemaID != cinemaID {
+			return fail("auditorium_id", "auditorium %d does not belong to cinema %d", s.AuditoriumID, s.CinemaID)
+		}
+		s.CinemaID = cinemaID
+	} else {
+		auditoriumID, ok := refs.cinemaDefault[s.CinemaID]
+		if !ok {
+			return fail("cinema_id", "cinema %d not found, inactive or has no active auditorium", s.CinemaID)
+		}
+		s.AuditoriumID = auditoriumID
+	}
+	if !refs.locations[s.LocationID] {
+		return fail("location_id", "location %d not found or inactive", s.LocationID)
+	}
+	if !refs.times[s.TimeID] {
+		return fail("time_id", "time %d not found or inactive", s.TimeID)
+	}
+	return nil
+}
+
+// importMovie: upsert satu movie beserta genre, director dan schedule-nya
+func importMovie(ctx context.Context, tx pgx.Tx, m models.ImportMovie) (bool, int, error) {
+	var movieID int
+	var created bool
+	err := tx.QueryRow(ctx, `
+		INSERT INTO movies (tmdb_id, title, overview, release_date, runtime,
+		                    poster_path, backdrop_path, created_at, updated_at)
+		VALUES ($1,$2,$3,NULLIF($4, '')::date,$5,$6,$7,NOW(),NOW())
+		ON CONFLICT (tmdb_id) DO UPDATE SET
+			title=EXCLUDED.title,
+			overview=COALESCE(NULLIF(EXCLUDED.overview, ''), movies.overview),
+			release_date=COALESCE(EXCLUDED.release_date, movies.release_date),
+			runtime=COALESCE(NULLIF(EXCLUDED.runtime, 0), movies.runtime),
+			poster_path=COALESCE(NULLIF(EXCLUDED.poster_path, ''), movies.poster_path),
+			backdrop_path=COALESCE(NULLIF(EXCLUDED.backdrop_path, ''), movies.backdrop_path),
+			updated_at=NOW()
+		RETURNING id, (xmax = 0)`,
+		m.TMDBID, m.Title, m.Overview, m.ReleaseDate, m.Runtime, m.PosterPath, m.BackdropPath,
+	).Scan(&movieID, &created)
+	if err != nil {
+		return false, 0, err
+	}
+
+	for _, g := range m.Genres {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO genres (name) VALUES ($1)
+			ON CONFLICT (name) DO NOTHING`, g); err != nil {
+			return false, 0, err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO movie_genres (movie_id, genre_id)
+			SELECT $1, id FROM genres WHERE name = $2
+			ON CONFLICT DO NOTHING`, movieID, g); err != nil {
+			return false, 0, err
+		}
+	}
+
+	if m.Director != nil {
+		if err := linkPerson(ctx, tx, movieID, m.Director.TMDBID, m.Director.Name, "Director"); err != nil {
+			return false, 0, err
+		}
+	}
+
+	schedulesCreated := 0
+	for _, s := range m.Schedules {
+		tag, err := tx.Exec(ctx, `
+			INSERT INTO schedules (movie_id, cinema_id, auditorium_id, location_id, time_id, date, price)
+			SELECT $1, $2, $3, $4, $5, $6, $7
+			WHERE NOT EXISTS (
+				SELECT 1 FROM schedules
+				WHERE movie_id = $1 AND auditorium_id = $3 AND time_id = $5 AND date = $6
+			)`, movieID, s.CinemaID, s.AuditoriumID, s.LocationID, s.TimeID, s.Date, s.Price)
+		if err != nil {
+			return false, 0, fmt.Errorf("schedule row %d: %w", s.Row, err)
+		}
+		schedulesCreated += int(tag.RowsAffected())
+	}
+
+	return created, schedulesCreated, nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// JobShowtimeReminder: pengingat jam tayang untuk satu order
+const JobShowtimeReminder = "showtime_reminder"
+
+const jobColumns = `id, kind, dedup_key, payload, due_at, run_at, status, attempts, COALESCE(last_error, '')`
+
+// JobRepository: delayed job di table scheduled_jobs
+type JobRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewJobRepository(db *pgxpool.Pool) *JobRepository {
+	return &JobRepository{DB: db}
+}
+
+// PlanShowtimeReminders menjadwalkan pengingat `before` sebelum jam tayang untuk order paid yang
+// pengingatnya jatuh tempo dalam planAhead. Pengingat yang waktunya sebelum order dibuat dilewati.
+// Dedup key membuat pemanggilan berulang (dari replica mana pun) aman.
+func (r *JobRepository) PlanShowtimeReminders(ctx context.Context, before, planAhead time.Duration) (int64, error) {
+	tag, err := r.DB.Exec(ctx, `
+		INSERT INTO scheduled_jobs (kind, dedup_key, payload, due_at, run_at)
+		SELECT $1,
+		       $1::text || ':' || o.id || ':' || $2::text,
+		       jsonb_build_object('order_id', o.id, 'before', $2::text),
+		       show.starts_at - $3 * INTERVAL '1 second',
+		       show.starts_at - $3 * INTERVAL '1 second'
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedule_id
+		JOIN times t ON t.id = s.time_id
+		CROSS JOIN LATERAL (SELECT (s.date + t.start_time)::timestamptz AS starts_at) show
+		WHERE o.status = 'paid'
+		  AND show.starts_at > NOW()
+		  AND show.starts_at - $3 * INTERVAL '1 second' <= NOW() + $4 * INTERVAL '1 second'
+		  AND show.starts_at - $3 * INTERVAL '1 second' > o.order_date
+		ON CONFLICT (dedup_key) DO NOTHING`,
+		JobShowtimeReminder, before.String(), int64(before.Seconds()), int64(planAhead.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ClaimJobs mengambil job yang jatuh tempo. run_at dimajukan sebesar lease sehingga replica lain
+// tidak mengambilnya; jika worker mati, job diambil lagi setelah lease habis (at-least-once).
+func (r *JobRepository) ClaimJobs(ctx context.Context, limit int, lease time.Duration) ([]models.ScheduledJob, error) {
+	rows, err := r.DB.Query(ctx, `
+		UPDATE scheduled_jobs
+		SET run_at = NOW() + $2 * INTERVAL '1 second', attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM scheduled_jobs
+			WHERE status = 'pending' AND run_at <= NOW()
+			ORDER BY run_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+jobColumns, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.ScheduledJob{}
+	for rows.Next() {
+		var j models.ScheduledJob
+		if err := rows.Scan(&j.ID, &j.Kind, &j.DedupKey, &j.Payload, &j.DueAt, &j.RunAt, &j.Status,
+			&j.Attempts, &j.LastError); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// FailJob mencatat error. retryAt nil = gagal permanen.
+func (r *JobRepository) FailJob(ctx context.Context, id int64, reason string, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := r.DB.Exec(ctx, `
+			UPDATE scheduled_jobs SET status = 'failed', last_error = $2, finished_at = NOW()
+			WHERE id = $1`, id, reason)
+		return err
+	}
+	_, err := r.DB.Exec(ctx, `
+		UPDATE scheduled_jobs SET last_error = $2, run_at = $3
+		WHERE id = $1`, id, reason, *retryAt)
+	return err
+}
+
+// SendShowtimeReminder menulis pengingat ke outbox notifikasi dan menandai job selesai dalam satu
+// transaksi. Job dibatalkan jika order tidak paid lagi atau film sudah mulai, dan kadaluarsa jika
+// terlambat lebih dari maxLate (mis. server mati lama). Mengembalikan status akhir job.
+func (r *JobRepository) SendShowtimeReminder(ctx context.Context, job models.ScheduledJob, maxLate time.Duration) (string, error) {
+	var p models.ShowtimeReminderJob
+	if err := json.Unmarshal(job.Payload, &p); err != nil {
+		return "", err
+	}
+
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	var status string
+	var startsAt time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT o.user_id, o.status, (s.date + t.start_time)::timestamptz
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedule_id
+		JOIN times t ON t.id = s.time_id
+		WHERE o.id = $1`, p.OrderID).Scan(&userID, &status, &startsAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		status = ""
+	} else if err != nil {
+		return "", err
+	}
+
+	result := "done"
+	switch {
+	case status != "paid" || !startsAt.After(time.Now()):
+		result = "cancelled"
+	case time.Since(job.DueAt) > maxLate:
+		result = "expired"
+	default:
+		n, err := orderNotification(ctx, tx, p.OrderID)
+		if err != nil {
+			return "", err
+		}
+		if err := enqueueNotification(ctx, tx, userID, JobShowtimeReminder, job.DedupKey, n); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE scheduled_jobs SET status = $2, finished_at = NOW(), last_error = NULL
+		WHERE id = $1`, job.ID, result); err != nil {
+		return "", err
+	}
+	return result, tx.Commit(ctx)
+}
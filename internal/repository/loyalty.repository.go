@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/loyalty"
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+// ErrInsufficientPoints: poin yang ingin ditukar melebihi saldo
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+func pointsBalance(ctx context.Context, q querier, userID int) (int, error) {
+	var balance int
+	err := q.QueryRow(ctx, `SELECT COALESCE(SUM(points), 0) FROM point_ledger WHERE user_id = $1`, userID).Scan(&balance)
+	return balance, err
+}
+
+// rollingSpend: total order paid user dalam window, tanpa order excludeOrderID
+func rollingSpend(ctx context.Context, q querier, userID int, window time.Duration, excludeOrderID int) (int, error) {
+	var spend int
+	err := q.QueryRow(ctx, `
+		SELECT COALESCE(SUM(total_price), 0)
+		FROM orders
+		WHERE user_id = $1 AND status = 'paid' AND order_date >= $2 AND id <> $3`,
+		userID, time.Now().Add(-window), excludeOrderID).Scan(&spend)
+	return spend, err
+}
+
+// currentTier: tier user berdasarkan belanja sebelum order excludeOrderID
+func currentTier(ctx context.Context, q querier, program *loyalty.Program, userID, excludeOrderID int) (loyalty.Tier, error) {
+	spend, err := rollingSpend(ctx, q, userID, program.Window, excludeOrderID)
+	if err != nil {
+		return loyalty.Tier{}, err
+	}
+	tier, _ := program.TierFor(spend)
+	return tier, nil
+}
+
+// insertPoints menambah satu baris ledger. Karena unik per (order_id, kind),
+// pemanggilan ulang untuk order yang sama tidak berpengaruh; false jika sudah pernah dicatat.
+func insertPoints(ctx context.Context, q querier, userID, orderID int, kind string, points int, description string) (bool, error) {
+	tag, err := q.Exec(ctx, `
+		INSERT INTO point_ledger (user_id, order_id, kind, points, description)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (order_id, kind) WHERE order_id IS NOT NULL DO NOTHING`,
+		userID, orderID, kind, points, description)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// awardPoints memberi poin untuk order yang sudah paid, tepat sekali per order.
+// Mengembalikan poin yang dicatat untuk order tersebut.
+func awardPoints(ctx context.Context, q querier, program *loyalty.Program, orderID int) (int, error) {
+	var userID, total int
+	var status string
+	// gift card yang dibeli tidak mendapat poin, poin diberikan saat gift card dipakai
+	if err := q.QueryRow(ctx, `SELECT user_id, total_price - gift_cards_total, status FROM orders WHERE id = $1`, orderID).
+		Scan(&userID, &total, &status); err != nil {
+		return 0, err
+	}
+	if status != "paid" {
+		return 0, nil
+	}
+	tier, err := currentTier(ctx, q, program, userID, orderID)
+	if err != nil {
+		return 0, err
+	}
+
+	points := program.Earn(total, tier)
+	if points <= 0 {
+		return 0, nil
+	}
+	if _, err := insertPoints(ctx, q, userID, orderID, "earn", points,
+		fmt.Sprintf("order #%d (%s)", orderID, tier.Name)); err != nil {
+		return 0, err
+	}
+
+	// bisa berbeda dari points jika sudah pernah diberikan sebelumnya
+	err = q.QueryRow(ctx, `SELECT points FROM point_ledger WHERE order_id = $1 AND kind = 'earn'`, orderID).Scan(&points)
+	return points, err
+}
+
+// reversePoints dipakai saat refund: poin yang didapat ditarik kembali dan
+// poin yang ditukar dikembalikan. Aman dipanggil berkali-kali.
+func reversePoints(ctx context.Context, q querier, orderID int) error {
+	var userID, redeemed int
+	if err := q.QueryRow(ctx, `SELECT user_id, points_redeemed FROM orders WHERE id = $1`, orderID).Scan(&userID, &redeemed); err != nil {
+		return err
+	}
+
+	if _, err := q.Exec(ctx, `
+		INSERT INTO point_ledger (user_id, order_id, kind, points, description)
+		SELECT user_id, order_id, 'reverse', -points, 'refund order #' || order_id
+		FROM point_ledger
+		WHERE order_id = $1 AND kind = 'earn'
+		ON CONFLICT (order_id, kind) WHERE order_id IS NOT NULL DO NOTHING`, orderID); err != nil {
+		return err
+	}
+	if redeemed > 0 {
+		if _, err := insertPoints(ctx, q, userID, orderID, "refund", redeemed,
+			fmt.Sprintf("refund order #%d", orderID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoyaltySummary: saldo poin dan tier user
+func (r *UserRepository) LoyaltySummary(ctx context.Context, userID int) (*models.LoyaltySummary, error) {
+	balance, err := pointsBalance(ctx, r.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := rollingSpend(ctx, r.DB, userID, r.Loyalty.Window, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	tier, next := r.Loyalty.TierFor(spend)
+	summary := &models.LoyaltySummary{PointsBalance: balance, Tier: tier.Name, RollingSpend: spend}
+	if next != nil {
+		summary.NextTier = next.Name
+		summary.NextTierSpend = next.MinSpend - spend
+	}
+	return summary, nil
+}
+
+// ListPoints: riwayat ledger poin, terbaru dulu
+func (r *UserRepository) ListPoints(ctx context.Context, userID, limit, offset int) ([]models.PointEntry, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, order_id, kind, points, description, created_at
+		FROM point_ledger
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.PointEntry{}
+	for rows.Next() {
+		var e models.PointEntry
+		if err := rows.Scan(&e.ID, &e.OrderID, &e.Kind, &e.Points, &e.Description, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// imageInUse: nama file upload berbasis hash isi, jadi satu key bisa dipakai
+// beberapa user/movie sekaligus. Object hanya boleh dihapus jika tidak dipakai lagi.
+func imageInUse(ctx context.Context, db *pgxpool.Pool, key string) (bool, error) {
+	var used bool
+	err := db.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM users WHERE avatar_url = $1)
+		    OR EXISTS (SELECT 1 FROM movies WHERE poster_path = $1 OR backdrop_path = $1)
+	`, key).Scan(&used)
+	return used, err
+}
+
+func (r *UserRepository) ImageInUse(ctx context.Context, key string) (bool, error) {
+	return imageInUse(ctx, r.DB, key)
+}
+
+func (r *AdminRepository) ImageInUse(ctx context.Context, key string) (bool, error) {
+	return imageInUse(ctx, r.DB, key)
+}
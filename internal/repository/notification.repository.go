@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrNotificationNotFound: notifikasi tidak ada atau belum gagal (untuk retry)
+var ErrNotificationNotFound = errors.New("notification not found")
+
+const notificationColumns = `id, user_id, template, language, recipient, payload, status, COALESCE(channel, ''),
+	attempts, COALESCE(last_error, ''), next_attempt_at, sent_at, created_at`
+
+type NotificationRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewNotificationRepository(db *pgxpool.Pool) *NotificationRepository {
+	return &NotificationRepository{DB: db}
+}
+
+func scanNotification(row pgx.Row) (*models.Notification, error) {
+	var n models.Notification
+	if err := row.Scan(&n.ID, &n.UserID, &n.Template, &n.Language, &n.Recipient, &n.Payload, &n.Status,
+		&n.Channel, &n.Attempts, &n.LastError, &n.NextAttemptAt, &n.SentAt, &n.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func collectNotifications(rows pgx.Rows) ([]models.Notification, error) {
+	defer rows.Close()
+	list := []models.Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *n)
+	}
+	return list, rows.Err()
+}
+
+// Claim mengambil notifikasi yang jatuh tempo untuk dikirim. next_attempt_at dimajukan sebesar lease
+// sehingga replica lain tidak mengambilnya; jika worker mati di tengah jalan, pesan dikirim ulang
+// setelah lease habis (at-least-once).
+func (r *NotificationRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]models.Notification, error) {
+	rows, err := r.DB.Query(ctx, `
+		UPDATE notifications
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 second', attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM notifications
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+notificationColumns, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	return collectNotifications(rows)
+}
+
+func (r *NotificationRepository) MarkSent(ctx context.Context, id int64, channel string) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE notifications
+		SET status = 'sent', channel = $2, sent_at = NOW(), last_error = NULL
+		WHERE id = $1`, id, channel)
+	return err
+}
+
+// MarkFailed mencatat error pengiriman. retryAt nil = gagal permanen.
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id int64, channel, reason string, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := r.DB.Exec(ctx, `
+			UPDATE notifications SET status = 'failed', channel = $2, last_error = $3
+			WHERE id = $1`, id, channel, reason)
+		return err
+	}
+	_, err := r.DB.Exec(ctx, `
+		UPDATE notifications SET channel = $2, last_error = $3, next_attempt_at = $4
+		WHERE id = $1`, id, channel, reason, *retryAt)
+	return err
+}
+
+// List untuk admin, terbaru dulu. status / userID kosong = semua.
+func (r *NotificationRepository) List(ctx context.Context, status string, userID, limit int) ([]models.Notification, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR user_id = $2)
+		ORDER BY id DESC
+		LIMIT $3`, status, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return collectNotifications(rows)
+}
+
+// Retry mengantrikan ulang notifikasi yang gagal
+func (r *NotificationRepository) Retry(ctx context.Context, id int64) (*models.Notification, error) {
+	n, err := scanNotification(r.DB.QueryRow(ctx, `
+		UPDATE notifications
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status = 'failed'
+		RETURNING `+notificationColumns, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotificationNotFound
+	}
+	return n, err
+}
+
+// enqueueNotification menulis notifikasi ke outbox di transaksi pemanggil.
+// Alamat dan bahasa diambil dari data user saat ini. Notifikasi dengan dedupKey yang sama
+// hanya ditulis sekali; dedupKey kosong = tanpa dedup.
+func enqueueNotification(ctx context.Context, q querier, userID int, template, dedupKey string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	_, err = q.Exec(ctx, `
+		INSERT INTO notifications (user_id, template, language, recipient, payload, dedup_key)
+		SELECT id, $2, language, email, $3, NULLIF($4, '')
+		FROM users
+		WHERE id = $1
+		ON CONFLICT (dedup_key) DO NOTHING`, userID, template, data, dedupKey)
+	return err
+}
+
+// orderNotification: data order untuk template notifikasi, termasuk tiket per kursi
+func orderNotification(ctx context.Context, q querier, orderID int) (*models.OrderNotification, error) {
+	n := &models.OrderNotification{OrderID: orderID}
+	err := q.QueryRow(ctx, `
+		SELECT m.title, c.name, TO_CHAR(s.date, 'YYYY-MM-DD'), TO_CHAR(t.start_time, 'HH24:MI'), o.total_price,
+		       COALESCE((SELECT array_agg(os.seat_code ORDER BY os.seat_code) FROM order_seats os WHERE os.order_id = o.id), '{}')
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedule_id
+		JOIN movies m ON m.id = s.movie_id
+		JOIN cinemas c ON c.id = s.cinema_id
+		JOIN times t ON t.id = s.time_id
+		WHERE o.id = $1`, orderID).Scan(&n.MovieTitle, &n.Cinema, &n.Date, &n.StartTime, &n.Total, &n.Seats)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(ctx, `
+		SELECT seat_code, ticket_type, price
+		FROM order_seats
+		WHERE order_id = $1
+		ORDER BY seat_code`, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	n.Tickets = []models.Ticket{}
+	for rows.Next() {
+		var t models.Ticket
+		if err := rows.Scan(&t.Seat, &t.Type, &t.Price); err != nil {
+			return nil, err
+		}
+		n.Tickets = append(n.Tickets, t)
+	}
+	return n, rows.Err()
+}
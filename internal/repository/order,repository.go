@@ -2,20 +2,24 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/cristian-yw/Weekly10/internal/loyalty"
 	"github.com/cristian-yw/Weekly10/internal/models"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type OrderRepository struct {
-	DB *pgxpool.Pool
+	DB      *pgxpool.Pool
+	Loyalty *loyalty.Program
 }
 
 func NewOrderRepository(db *pgxpool.Pool) *OrderRepository {
-	return &OrderRepository{DB: db}
+	return &OrderRepository{DB: db, Loyalty: loyalty.NewProgramFromEnv()}
 }
 
 // 1. Get Schedule
@@ -28,10 +32,11 @@ func (r *OrderRepository) GetSchedule(
 
 	// Base query
 	query := `
-		SELECT s.id, m.title, c.name, l.location, t.start_time, s.date
+		SELECT s.id, m.title, c.name, a.name, a.format, l.location, t.start_time, s.date
 		FROM schedules s
 		JOIN movies m ON m.id = s.movie_id
 		JOIN cinemas c ON c.id = s.cinema_id
+		JOIN auditoriums a ON a.id = s.auditorium_id
 		JOIN locations l ON l.id = s.location_id
 		JOIN times t ON t.id = s.time_id
 		WHERE m.id = $1`
@@ -71,7 +76,7 @@ func (r *OrderRepository) GetSchedule(
 	var schedules []models.Schedule
 	for rows.Next() {
 		var s models.Schedule
-		if err := rows.Scan(&s.ID, &s.MovieTitle, &s.Cinema, &s.Location, &s.StartTime, &s.Date); err != nil {
+		if err := rows.Scan(&s.ID, &s.MovieTitle, &s.Cinema, &s.Auditorium, &s.Format, &s.Location, &s.StartTime, &s.Date); err != nil {
 			return nil, err
 		}
 		schedules = append(schedules, s)
@@ -82,17 +87,18 @@ func (r *OrderRepository) GetSchedule(
 	return schedules, nil
 }
 
-// 2. Get Available Seat
+// 2. Get Available Seat: kursi yang sudah terisi (order paid / pending), sama dengan SeatLayout
+// dan checkSeats supaya daftar kursi, saran kursi dan validasi order memakai aturan yang sama
 func (r *OrderRepository) GetAvailableSeats(ctx context.Context, scheduleID int) ([]models.Seat, error) {
 	rows, err := r.DB.Query(ctx, `
-		SELECT DISTINCT s.id, s.cinema_id, s.seat_code, true as is_booked
+		SELECT DISTINCT s.id, s.cinema_id, s.auditorium_id, s.seat_code, true as is_booked
 FROM seats s
 JOIN order_seats os ON s.seat_code = os.seat_code
 JOIN orders o ON o.id = os.order_id
 JOIN schedules sch ON sch.id = o.schedule_id
 WHERE o.schedule_id = $1
-  AND o.status = 'paid'
-  AND s.cinema_id = sch.cinema_id
+  AND o.status IN ('paid', 'pending')
+  AND s.auditorium_id = sch.auditorium_id
 ORDER BY s.seat_code;
 
 	`, scheduleID)
@@ -104,7 +110,7 @@ ORDER BY s.seat_code;
 	var seats []models.Seat
 	for rows.Next() {
 		var seat models.Seat
-		if err := rows.Scan(&seat.ID, &seat.CinemaID, &seat.SeatCode, &seat.IsBooked); err != nil {
+		if err := rows.Scan(&seat.ID, &seat.CinemaID, &seat.AuditoriumID, &seat.SeatCode, &seat.IsBooked); err != nil {
 			return nil, err
 		}
 		seats = append(seats, seat)
@@ -170,46 +176,174 @@ func (r *OrderRepository) GetMovieDetail(ctx context.Context, movieID int) (*mod
 }
 
 // 4. Create Order
-func (r *OrderRepository) CreateOrder(ctx context.Context, userID, scheduleID, totalPrice int, seats []string) (*models.Order, error) {
+// Total dihitung ulang di server (lihat quoteOrder). Promo dikunci, dicatat dan
+// used_count dinaikkan di transaksi yang sama dengan order, begitu juga stok add-on,
+// poin, saldo gift card, gift card baru yang ikut dibeli dan notifikasi konfirmasi. Untuk group booking
+// (req.GroupBookingID) kursi harus sudah diklaim user dan ditandai lunas di transaksi ini.
+func (r *OrderRepository) CreateOrder(ctx context.Context, userID int, req models.QuoteRequest) (*models.Order, error) {
 	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback(ctx)
 
+	// kunci jadwal supaya order lain untuk jadwal yang sama menunggu sampai order ini
+	// selesai, lalu checkSeats (lewat quoteOrder) melihat kursi yang baru dibayar
+	var locked int
+	err = tx.QueryRow(ctx, `SELECT id FROM schedules WHERE id = $1 FOR UPDATE`, req.ScheduleID).Scan(&locked)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GroupBookingID != 0 {
+		if err := checkGroupClaim(ctx, tx, req.GroupBookingID, userID, req.Seats); err != nil {
+			return nil, err
+		}
+	}
+
+	priced, err := quoteOrder(ctx, tx, r.Loyalty, userID, req, true)
+	if err != nil {
+		return nil, err
+	}
+	quote, promotion := priced.Quote, priced.Promotion
+	pricingJSON, err := json.Marshal(priced.Pricing)
+	if err != nil {
+		return nil, err
+	}
+
 	// 1. Insert ke orders
 	var orderID int
 	err = tx.QueryRow(ctx, `
-		INSERT INTO orders (user_id, schedule_id, total_price, status, order_date)
-		VALUES ($1, $2, $3, 'paid', NOW())
+		INSERT INTO orders (user_id, schedule_id, subtotal, discount, items_total, points_redeemed, points_discount, gift_cards_total, total_price, pricing, group_booking_id, status, order_date)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, 0), 'paid', NOW())
 		RETURNING id
-	`, userID, scheduleID, totalPrice).Scan(&orderID)
+	`, userID, req.ScheduleID, quote.Subtotal, quote.Discount, quote.ItemsTotal, quote.PointsRedeemed, quote.PointsDiscount,
+		quote.GiftCardsTotal, quote.Total, pricingJSON, req.GroupBookingID).Scan(&orderID)
 	if err != nil {
 		return nil, err
 	}
 
-	// 2. Insert ke order_seats
-	for _, seatCode := range seats {
+	// 2. Insert ke order_seats beserta tipe tiket dan harganya
+	for _, t := range quote.Tickets {
 		_, err := tx.Exec(ctx, `
-			INSERT INTO order_seats (order_id, seat_code)
-			VALUES ($1, $2)
-		`, orderID, seatCode)
+			INSERT INTO order_seats (order_id, seat_code, ticket_type, price)
+			VALUES ($1, $2, $3, $4)
+		`, orderID, t.Seat, t.Type, t.Price)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	if req.GroupBookingID != 0 {
+		if err := markGroupPaid(ctx, tx, req.GroupBookingID, orderID, req.Seats); err != nil {
+			return nil, err
+		}
+	}
+	if err := markWaitlistBooked(ctx, tx, req.ScheduleID, userID); err != nil {
+		return nil, err
+	}
+
+	// 3. Add-on: stok dikurangi, diambil di konter dengan menunjukkan order
+	if err := reserveItems(ctx, tx, orderID, priced.CinemaID, quote.Items); err != nil {
+		return nil, err
+	}
+
+	// 4. Catat pemakaian promo
+	if promotion != nil {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO promotion_redemptions (promotion_id, order_id, user_id, discount)
+			VALUES ($1, $2, $3, $4)
+		`, promotion.ID, orderID, userID, quote.Discount); err != nil {
+			return nil, err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE promotions SET used_count = used_count + 1 WHERE id = $1`, promotion.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Poin loyalty: yang ditukar dikurangi, order langsung paid sehingga poin langsung diberikan
+	if quote.PointsRedeemed > 0 {
+		if _, err := insertPoints(ctx, tx, userID, orderID, "redeem", -quote.PointsRedeemed,
+			fmt.Sprintf("order #%d", orderID)); err != nil {
+			return nil, err
+		}
+	}
+	earned, err := awardPoints(ctx, tx, r.Loyalty, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 6. Pembayaran: gift card lalu metode lain untuk sisanya
+	if priced.GiftCard != nil && quote.GiftCardAmount > 0 {
+		if err := redeemGiftCard(ctx, tx, priced.GiftCard.ID, orderID, userID, quote.GiftCardAmount); err != nil {
+			return nil, err
+		}
+	}
+	if quote.AmountDue > 0 {
+		method := req.PaymentMethod
+		if method == "" {
+			method = "gateway"
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO order_payments (order_id, method, amount)
+			VALUES ($1, $2, $3)
+		`, orderID, method, quote.AmountDue); err != nil {
+			return nil, err
+		}
+	}
+
+	// 7. Gift card yang dibeli langsung aktif karena order sudah paid
+	var giftCards []models.GiftCard
+	for _, purchase := range req.GiftCards {
+		g, err := issueGiftCard(ctx, tx, "purchase", userID, &orderID, purchase, nil)
+		if err != nil {
+			return nil, err
+		}
+		giftCards = append(giftCards, *g)
+	}
+
+	// 8. Konfirmasi order masuk outbox, dikirim worker notifikasi setelah commit
+	confirmation, err := orderNotification(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := enqueueNotification(ctx, tx, userID, "order_created", "", confirmation); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return nil, err
 	}
 
 	return &models.Order{
-		ID:         orderID,
-		UserID:     userID,
-		ScheduleID: scheduleID,
-		TotalPrice: totalPrice,
-		Status:     "paid",
-		OrderDate:  time.Now(),
-		Seats:      seats,
+		ID:             orderID,
+		UserID:         userID,
+		ScheduleID:     req.ScheduleID,
+		Subtotal:       quote.Subtotal,
+		Discount:       quote.Discount,
+		PromoCode:      quote.PromoCode,
+		PointsRedeemed: quote.PointsRedeemed,
+		PointsDiscount: quote.PointsDiscount,
+		PointsEarned:   earned,
+		TotalPrice:     quote.Total,
+		GiftCardAmount: quote.GiftCardAmount,
+		AmountDue:      quote.AmountDue,
+		Status:         "paid",
+		OrderDate:      time.Now(),
+		Seats:          req.Seats,
+		Tickets:        quote.Tickets,
+		Items:          quote.Items,
+		ItemsTotal:     quote.ItemsTotal,
+		GiftCards:      giftCards,
 	}, nil
 }
+
+// GetScheduleMovieID: movie dari satu jadwal, dipakai waiting room per movie
+func (r *OrderRepository) GetScheduleMovieID(ctx context.Context, scheduleID int) (int, error) {
+	var movieID int
+	err := r.DB.QueryRow(ctx, `SELECT movie_id FROM schedules WHERE id = $1`, scheduleID).Scan(&movieID)
+	return movieID, err
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cristian-yw/Weekly10/internal/loyalty"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrOrderNotFound  = errors.New("order not found")
+	ErrAmountMismatch = errors.New("amount does not match order total")
+)
+
+// FreedSeats: kursi yang kembali tersedia karena order di-refund / gagal
+type FreedSeats struct {
+	ScheduleID int
+	Seats      []string
+}
+
+type PaymentRepository struct {
+	DB      *pgxpool.Pool
+	Loyalty *loyalty.Program
+}
+
+func NewPaymentRepository(db *pgxpool.Pool) *PaymentRepository {
+	return &PaymentRepository{DB: db, Loyalty: loyalty.NewProgramFromEnv()}
+}
+
+// HandleEvent memproses satu event payment gateway dalam satu transaksi.
+// Event dengan event_id yang sama hanya diproses sekali (duplicate = true untuk pengiriman ulang),
+// dan ledger poin unik per order sehingga poin tidak pernah diberikan dua kali.
+// freed diisi jika kursi order kembali tersedia (refund / gagal).
+func (r *PaymentRepository) HandleEvent(ctx context.Context, ev models.PaymentWebhook) (duplicate bool, freed *FreedSeats, err error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return false, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	var total int
+	err = tx.QueryRow(ctx, `SELECT status, total_price FROM orders WHERE id = $1 FOR UPDATE`, ev.OrderID).Scan(&status, &total)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	if ev.Status == "paid" && ev.Amount != 0 && ev.Amount != total {
+		return false, nil, ErrAmountMismatch
+	}
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return false, nil, err
+	}
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO payment_events (event_id, order_id, status, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (event_id) DO NOTHING`, ev.EventID, ev.OrderID, ev.Status, payload)
+	if err != nil {
+		return false, nil, err
+	}
+	if tag.RowsAffected() == 0 {
+		return true, nil, nil
+	}
+
+	next, apply, err := orderTransition(status, ev.Status)
+	if err != nil {
+		return false, nil, err
+	}
+	switch {
+	case !apply:
+		// event terlambat / diputar ulang, status order tidak berubah
+	case next == "paid":
+		if _, err := tx.Exec(ctx, `UPDATE orders SET status = 'paid' WHERE id = $1`, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if _, err := awardPoints(ctx, tx, r.Loyalty, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+	case next == "refunded":
+		if _, err := tx.Exec(ctx, `UPDATE orders SET status = 'refunded' WHERE id = $1`, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if err := reversePoints(ctx, tx, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if err := refundGiftCards(ctx, tx, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if err := restockItems(ctx, tx, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if status != "refunded" && status != "failed" {
+			if freed, err = orderSeats(ctx, tx, ev.OrderID); err != nil {
+				return false, nil, err
+			}
+		}
+	case next == "failed":
+		if _, err := tx.Exec(ctx, `UPDATE orders SET status = 'failed' WHERE id = $1`, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		// poin, saldo gift card dan stok add-on order yang gagal dikembalikan
+		if err := reversePoints(ctx, tx, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if err := refundGiftCards(ctx, tx, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if err := restockItems(ctx, tx, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+		if freed, err = orderSeats(ctx, tx, ev.OrderID); err != nil {
+			return false, nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, nil, err
+	}
+	return false, freed, nil
+}
+
+// orderTransition: status order setelah event payment, apply = false jika event diabaikan.
+// paid dan failed hanya berlaku untuk order pending: order failed sudah mengembalikan poin,
+// gift card, add-on dan kursinya, jadi event paid yang terlambat tidak boleh menghidupkannya lagi.
+// refunded selalu diproses (pengembaliannya idempoten).
+func orderTransition(status, event string) (string, bool, error) {
+	switch event {
+	case "paid", "failed":
+		return event, status == "pending", nil
+	case "refunded":
+		return event, true, nil
+	default:
+		return "", false, fmt.Errorf("unknown payment status %q", event)
+	}
+}
+
+// orderSeats: jadwal dan kursi satu order
+func orderSeats(ctx context.Context, q querier, orderID int) (*FreedSeats, error) {
+	freed := &FreedSeats{}
+	err := q.QueryRow(ctx, `
+		SELECT o.schedule_id, COALESCE(array_agg(os.seat_code ORDER BY os.seat_code) FILTER (WHERE os.seat_code IS NOT NULL), '{}')
+		FROM orders o
+		LEFT JOIN order_seats os ON os.order_id = o.id
+		WHERE o.id = $1
+		GROUP BY o.schedule_id`, orderID).Scan(&freed.ScheduleID, &freed.Seats)
+	return freed, err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import "testing"
+
+func TestOrderTransition(t *testing.T) {
+	tests := []struct {
+		status, event string
+		next          string
+		apply         bool
+	}{
+		{"pending", "paid", "paid", true},
+		{"pending", "failed", "failed", true},
+		{"pending", "refunded", "refunded", true},
+		{"paid", "paid", "paid", false},
+		{"paid", "failed", "failed", false},
+		{"paid", "refunded", "refunded", true},
+		{"failed", "paid", "paid", false},
+		{"failed", "failed", "failed", false},
+		{"refunded", "paid", "paid", false},
+		{"refunded", "failed", "failed", false},
+	}
+	for _, tt := range tests {
+		next, apply, err := orderTransition(tt.status, tt.event)
+		if err != nil {
+			t.Fatalf("%s + %s: %v", tt.status, tt.event, err)
+		}
+		if next != tt.next || apply != tt.apply {
+			t.Errorf("%s + %s = %s, %v; want %s, %v", tt.status, tt.event, next, apply, tt.next, tt.apply)
+		}
+	}
+
+	if _, _, err := orderTransition("pending", "chargeback"); err == nil {
+		t.Error("unknown event: want error")
+	}
+}
+
+// event paid yang terlambat / diputar ulang setelah failed tidak menghidupkan order lagi
+func TestOrderTransitionFailedThenPaid(t *testing.T) {
+	status := "pending"
+	for _, event := range []string{"failed", "paid", "paid"} {
+		next, apply, err := orderTransition(status, event)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if apply {
+			status = next
+		}
+	}
+	if status != "failed" {
+		t.Fatalf("status = %s, want failed", status)
+	}
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/pricing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const pricingRuleColumns = `id, name, description, priority, days_of_week,
+	TO_CHAR(start_from, 'HH24:MI'), TO_CHAR(start_until, 'HH24:MI'), premiere_days,
+	min_occupancy, max_occupancy, movie_ids, cinema_ids, adjustment_type, adjustment_value,
+	stop_processing, is_active, created_at, updated_at`
+
+type PricingRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewPricingRepository(db *pgxpool.Pool) *PricingRepository {
+	return &PricingRepository{DB: db}
+}
+
+func scanPricingRule(row pgx.Row) (*models.PricingRule, error) {
+	var p models.PricingRule
+	err := row.Scan(&p.ID, &p.Name, &p.Description, &p.Priority, &p.DaysOfWeek,
+		&p.StartFrom, &p.StartUntil, &p.PremiereDays,
+		&p.MinOccupancy, &p.MaxOccupancy, &p.MovieIDs, &p.CinemaIDs, &p.AdjustmentType, &p.AdjustmentValue,
+		&p.StopProcessing, &p.IsActive, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// listPricingRules: onlyActive = true untuk evaluasi harga
+func listPricingRules(ctx context.Context, q querier, onlyActive bool) ([]models.PricingRule, error) {
+	query := `SELECT ` + pricingRuleColumns + ` FROM pricing_rules`
+	if onlyActive {
+		query += ` WHERE is_active`
+	}
+	rows, err := q.Query(ctx, query+` ORDER BY priority, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.PricingRule{}
+	for rows.Next() {
+		p, err := scanPricingRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *p)
+	}
+	return rules, rows.Err()
+}
+
+// scheduleShow: data jadwal untuk pricing beserta harga dasarnya (schedules.price).
+// Okupansi menghitung kursi terisi (order paid / pending), sama dengan daftar kursi.
+func scheduleShow(ctx context.Context, q querier, scheduleID int) (pricing.Show, int, error) {
+	s := pricing.Show{ScheduleID: scheduleID}
+	var price, seats, sold int
+	err := q.QueryRow(ctx, `
+		SELECT s.price, s.movie_id, s.cinema_id, s.date, TO_CHAR(t.start_time, 'HH24:MI'), m.release_date,
+		       (SELECT COUNT(*) FROM seats se WHERE se.auditorium_id = s.auditorium_id AND se.is_active),
+		       (SELECT COUNT(*) FROM order_seats os JOIN orders o ON o.id = os.order_id
+		         WHERE o.schedule_id = s.id AND o.status IN ('paid', 'pending'))
+		FROM schedules s
+		JOIN times t ON t.id = s.time_id
+		JOIN movies m ON m.id = s.movie_id
+		WHERE s.id = $1`, scheduleID).
+		Scan(&price, &s.MovieID, &s.CinemaID, &s.Date, &s.StartTime, &s.ReleaseDate, &seats, &sold)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return s, 0, ErrScheduleNotFound
+	}
+	if err != nil {
+		return s, 0, err
+	}
+	if seats > 0 {
+		s.OccupancyPct = min(100*sold/seats, 100)
+	}
+	return s, price, nil
+}
+
+// schedulePrice: harga jadwal setelah aturan harga dinamis
+func schedulePrice(ctx context.Context, q querier, scheduleID int) (pricing.Show, models.PriceBreakdown, error) {
+	show, price, err := scheduleShow(ctx, q, scheduleID)
+	if err != nil {
+		return show, models.PriceBreakdown{}, err
+	}
+	rules, err := listPricingRules(ctx, q, true)
+	if err != nil {
+		return show, models.PriceBreakdown{}, err
+	}
+	return show, pricing.Evaluate(price, rules, show, time.Now()), nil
+}
+
+func (r *PricingRepository) ListRules(ctx context.Context) ([]models.PricingRule, error) {
+	return listPricingRules(ctx, r.DB, false)
+}
+
+func (r *PricingRepository) GetRule(ctx context.Context, id int) (*models.PricingRule, error) {
+	return scanPricingRule(r.DB.QueryRow(ctx, `SELECT `+pricingRuleColumns+` FROM pricing_rules WHERE id = $1`, id))
+}
+
+func (r *PricingRepository) CreateRule(ctx context.Context, req models.PricingRuleRequest) (*models.PricingRule, error) {
+	return scanPricingRule(r.DB.QueryRow(ctx, `
+		INSERT INTO pricing_rules (name, description, priority, days_of_week, start_from, start_until, premiere_days,
+			min_occupancy, max_occupancy, movie_ids, cinema_ids, adjustment_type, adjustment_value,
+			stop_processing, is_active)
+		VALUES ($1, $2, $3, $4, $5::time, $6::time, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING `+pricingRuleColumns, pricingRuleArgs(req)...))
+}
+
+// UpdateRule mengganti seluruh pengaturan rule
+func (r *PricingRepository) UpdateRule(ctx context.Context, id int, req models.PricingRuleRequest) (*models.PricingRule, error) {
+	return scanPricingRule(r.DB.QueryRow(ctx, `
+		UPDATE pricing_rules SET
+			name = $1, description = $2, priority = $3, days_of_week = $4, start_from = $5::time,
+			start_until = $6::time, premiere_days = $7, min_occupancy = $8, max_occupancy = $9,
+			movie_ids = $10, cinema_ids = $11, adjustment_type = $12, adjustment_value = $13,
+			stop_processing = $14, is_active = $15, updated_at = NOW()
+		WHERE id = $16
+		RETURNING `+pricingRuleColumns, append(pricingRuleArgs(req), id)...))
+}
+
+// DeactivateRule: rule tidak dihapus supaya tetap bisa ditelusuri dari orders.pricing
+func (r *PricingRepository) DeactivateRule(ctx context.Context, id int) error {
+	tag, err := r.DB.Exec(ctx, `UPDATE pricing_rules SET is_active = FALSE, updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Preview mengevaluasi seluruh rule aktif untuk satu jadwal tanpa menyimpan apa pun.
+// occupancy != nil mengganti okupansi saat ini, untuk mencoba surge pricing.
+func (r *PricingRepository) Preview(ctx context.Context, scheduleID int, occupancy *int) (*models.PriceBreakdown, error) {
+	show, price, err := scheduleShow(ctx, r.DB, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if occupancy != nil {
+		show.OccupancyPct = *occupancy
+	}
+	rules, err := listPricingRules(ctx, r.DB, true)
+	if err != nil {
+		return nil, err
+	}
+	b := pricing.Evaluate(price, rules, show, time.Now())
+	return &b, nil
+}
+
+// OrderPricing: hasil evaluasi harga yang tersimpan saat order dibuat
+func (r *PricingRepository) OrderPricing(ctx context.Context, orderID int) (*models.PriceBreakdown, error) {
+	var data []byte
+	err := r.DB.QueryRow(ctx, `SELECT pricing FROM orders WHERE id = $1`, orderID).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		// order dibuat sebelum aturan harga ada
+		return nil, pgx.ErrNoRows
+	}
+	var b models.PriceBreakdown
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func pricingRuleArgs(req models.PricingRuleRequest) []any {
+	isActive := req.IsActive == nil || *req.IsActive
+	return []any{
+		req.Name, req.Description, req.Priority, nonNilInts(req.DaysOfWeek), req.StartFrom, req.StartUntil, req.PremiereDays,
+		req.MinOccupancy, req.MaxOccupancy, nonNilInts(req.MovieIDs), nonNilInts(req.CinemaIDs),
+		req.AdjustmentType, req.AdjustmentValue, req.StopProcessing, isActive,
+	}
+}
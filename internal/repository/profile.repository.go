@@ -4,17 +4,21 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/cristian-yw/Weekly10/internal/loyalty"
 	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserRepository struct {
-	DB *pgxpool.Pool
+	DB      *pgxpool.Pool
+	Loyalty *loyalty.Program
 }
 
 func NewUserRepository(db *pgxpool.Pool) *UserRepository {
-	return &UserRepository{DB: db}
+	return &UserRepository{DB: db, Loyalty: loyalty.NewProgramFromEnv()}
 }
 
 func (r *UserRepository) GetProfile(ctx context.Context, userID int) (models.UserProfile, error) {
@@ -43,20 +47,48 @@ func (r *UserRepository) GetHistory(ctx context.Context, userID int) ([]models.O
 	defer rows.Close()
 
 	var history []models.OrderHistory
+	index := map[int]int{}
 	for rows.Next() {
 		var h models.OrderHistory
 		if err := rows.Scan(&h.OrderID, &h.MovieTitle, &h.TotalPrice, &h.Status, &h.Date); err != nil {
 			return nil, err
 		}
+		h.Tickets = []models.Ticket{}
+		index[h.OrderID] = len(history)
 		history = append(history, h)
 	}
-	return history, nil
+	rows.Close()
+
+	// kursi beserta tipe tiketnya
+	seatRows, err := r.DB.Query(ctx, `
+		SELECT os.order_id, os.seat_code, os.ticket_type, COALESCE(os.price, 0)
+		FROM order_seats os
+		JOIN orders o ON o.id = os.order_id
+		WHERE o.user_id = $1
+		ORDER BY os.seat_code
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer seatRows.Close()
+	for seatRows.Next() {
+		var orderID int
+		var t models.Ticket
+		if err := seatRows.Scan(&orderID, &t.Seat, &t.Type, &t.Price); err != nil {
+			return nil, err
+		}
+		if i, ok := index[orderID]; ok {
+			history[i].Tickets = append(history[i].Tickets, t)
+		}
+	}
+	return history, seatRows.Err()
 }
 func (r *UserRepository) UpdateProfile(
 	ctx context.Context,
 	id int,
 	firstName, lastName, phone *string,
 	avatarURL *string,
+	language *string,
 ) error {
 	setParts := []string{}
 	args := []interface{}{}
@@ -82,6 +114,11 @@ func (r *UserRepository) UpdateProfile(
 		args = append(args, *avatarURL)
 		i++
 	}
+	if language != nil {
+		setParts = append(setParts, fmt.Sprintf("language = $%d", i))
+		args = append(args, *language)
+		i++
+	}
 
 	// Jika tidak ada field yang dikirim, jangan update apa pun
 	if len(setParts) == 0 {
@@ -130,11 +167,23 @@ func (r *UserRepository) GetPasswordHash(ctx context.Context, userID int) (strin
 	return hash, err
 }
 
-// Update password
+// Update password, notifikasi password_changed ditulis ke outbox di transaksi yang sama
 func (r *UserRepository) UpdatePassword(ctx context.Context, userID int, newHashed string) error {
-	_, err := r.DB.Exec(ctx,
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
 		"UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2",
 		newHashed, userID,
-	)
-	return err
+	); err != nil {
+		return err
+	}
+	if err := enqueueNotification(ctx, tx, userID, "password_changed", "",
+		models.PasswordChangedNotification{ChangedAt: time.Now()}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
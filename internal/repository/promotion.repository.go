@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/promo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrDuplicateCode: kode promo sudah dipakai promo lain
+var ErrDuplicateCode = errors.New("promo code already exists")
+
+const promotionColumns = `id, code, description, discount_type, discount_value, max_discount,
+	valid_from, valid_until, max_uses, max_uses_per_user, min_seats,
+	movie_ids, cinema_ids, days_of_week, used_count, is_active, created_at`
+
+type PromotionRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewPromotionRepository(db *pgxpool.Pool) *PromotionRepository {
+	return &PromotionRepository{DB: db}
+}
+
+func scanPromotion(row pgx.Row) (*models.Promotion, error) {
+	var p models.Promotion
+	err := row.Scan(&p.ID, &p.Code, &p.Description, &p.DiscountType, &p.DiscountValue, &p.MaxDiscount,
+		&p.ValidFrom, &p.ValidUntil, &p.MaxUses, &p.MaxUsesPerUser, &p.MinSeats,
+		&p.MovieIDs, &p.CinemaIDs, &p.DaysOfWeek, &p.UsedCount, &p.IsActive, &p.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *PromotionRepository) ListPromotions(ctx context.Context) ([]models.Promotion, error) {
+	rows, err := r.DB.Query(ctx, `SELECT `+promotionColumns+` FROM promotions ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []models.Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, *p)
+	}
+	return promotions, rows.Err()
+}
+
+func (r *PromotionRepository) GetPromotion(ctx context.Context, id int) (*models.Promotion, error) {
+	return scanPromotion(r.DB.QueryRow(ctx, `SELECT `+promotionColumns+` FROM promotions WHERE id = $1`, id))
+}
+
+func (r *PromotionRepository) CreatePromotion(ctx context.Context, req models.PromotionRequest) (*models.Promotion, error) {
+	args := promotionArgs(req)
+	p, err := scanPromotion(r.DB.QueryRow(ctx, `
+		INSERT INTO promotions (code, description, discount_type, discount_value, max_discount,
+			valid_from, valid_until, max_uses, max_uses_per_user, min_seats,
+			movie_ids, cinema_ids, days_of_week, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING `+promotionColumns, args...))
+	return p, duplicateCode(err)
+}
+
+// UpdatePromotion mengganti seluruh pengaturan; used_count tidak berubah
+func (r *PromotionRepository) UpdatePromotion(ctx context.Context, id int, req models.PromotionRequest) (*models.Promotion, error) {
+	args := append(promotionArgs(req), id)
+	p, err := scanPromotion(r.DB.QueryRow(ctx, `
+		UPDATE promotions SET
+			code = $1, description = $2, discount_type = $3, discount_value = $4, max_discount = $5,
+			valid_from = $6, valid_until = $7, max_uses = $8, max_uses_per_user = $9, min_seats = $10,
+			movie_ids = $11, cinema_ids = $12, days_of_week = $13, is_active = $14
+		WHERE id = $15
+		RETURNING `+promotionColumns, args...))
+	return p, duplicateCode(err)
+}
+
+// DeactivatePromotion: promo tidak dihapus karena masih direferensikan redemption
+func (r *PromotionRepository) DeactivatePromotion(ctx context.Context, id int) error {
+	tag, err := r.DB.Exec(ctx, `UPDATE promotions SET is_active = FALSE WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PromotionRepository) ListRedemptions(ctx context.Context, promotionID int) ([]models.PromotionRedemption, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, promotion_id, order_id, user_id, discount, redeemed_at
+		FROM promotion_redemptions
+		WHERE promotion_id = $1
+		ORDER BY redeemed_at DESC`, promotionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	redemptions := []models.PromotionRedemption{}
+	for rows.Next() {
+		var rd models.PromotionRedemption
+		if err := rows.Scan(&rd.ID, &rd.PromotionID, &rd.OrderID, &rd.UserID, &rd.Discount, &rd.RedeemedAt); err != nil {
+			return nil, err
+		}
+		redemptions = append(redemptions, rd)
+	}
+	return redemptions, rows.Err()
+}
+
+func promotionArgs(req models.PromotionRequest) []any {
+	if req.MinSeats <= 0 {
+		req.MinSeats = 1
+	}
+	isActive := req.IsActive == nil || *req.IsActive
+	return []any{
+		promo.Normalize(req.Code), req.Description, req.DiscountType, req.DiscountValue, req.MaxDiscount,
+		req.ValidFrom, req.ValidUntil, req.MaxUses, req.MaxUsesPerUser, req.MinSeats,
+		nonNilInts(req.MovieIDs), nonNilInts(req.CinemaIDs), nonNilInts(req.DaysOfWeek), isActive,
+	}
+}
+
+// nonNilInts: kolom array NOT NULL, nil slice akan dikirim sebagai NULL oleh pgx
+func nonNilInts(v []int) []int {
+	if v == nil {
+		return []int{}
+	}
+	return v
+}
+
+func duplicateCode(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrDuplicateCode
+	}
+	return err
+}
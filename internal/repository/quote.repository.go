@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/giftcard"
+	"github.com/cristian-yw/Weekly10/internal/loyalty"
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/promo"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrScheduleNotFound: jadwal pada quote / order tidak ada
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// querier dipenuhi *pgxpool.Pool dan pgx.Tx, sehingga perhitungan harga yang sama
+// dipakai untuk preview (tanpa transaksi) dan CreateOrder (di dalam transaksi)
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// pricedOrder: hasil quoteOrder beserta promo dan gift card yang dipakai (nil jika tidak ada)
+type pricedOrder struct {
+	Quote     *models.Quote
+	CinemaID  int
+	Pricing   models.PriceBreakdown // disimpan di orders.pricing
+	Promotion *models.Promotion
+	GiftCard  *models.GiftCard
+}
+
+// Quote menghitung total order tanpa menyimpan apa pun
+func (r *OrderRepository) Quote(ctx context.Context, userID int, req models.QuoteRequest) (*models.Quote, error) {
+	priced, err := quoteOrder(ctx, r.DB, r.Loyalty, userID, req, false)
+	if err != nil {
+		return nil, err
+	}
+	return priced.Quote, nil
+}
+
+// quoteOrder: harga jadwal setelah aturan harga dinamis (lihat package pricing), lalu harga
+// tiket per kursi sesuai tipe tiketnya (lihat ticketPrices), dikurangi promo,
+// ditambah add-on makanan / minuman (harga cinema jadwal tersebut) lalu dikurangi poin
+// loyalty, dibayar sebagian atau seluruhnya dengan gift card. Gift card baru
+// yang ikut dibeli ditambahkan ke total tetapi tidak bisa dibayar dengan gift card / poin.
+// lock = true mengunci baris promo, user dan gift card (FOR UPDATE) supaya batas pemakaian
+// dan saldo tidak terlewati oleh order yang berjalan bersamaan.
+func quoteOrder(ctx context.Context, q querier, program *loyalty.Program, userID int, req models.QuoteRequest, lock bool) (*pricedOrder, error) {
+	show, breakdown, err := schedulePrice(ctx, q, req.ScheduleID)
+	if err != nil {
+		return nil, err
+	}
+	cinemaID := show.CinemaID
+	if err := checkSeats(ctx, q, req.ScheduleID, req.Seats); err != nil {
+		return nil, err
+	}
+
+	tickets, subtotal, err := priceTickets(ctx, q, req.ScheduleID, cinemaID, breakdown.Price, req.Seats, req.TicketTypes)
+	if err != nil {
+		return nil, err
+	}
+	quote := &models.Quote{
+		ScheduleID: req.ScheduleID,
+		Seats:      req.Seats,
+		Tickets:    tickets,
+		UnitPrice:  breakdown.Price,
+		PriceRules: appliedRules(breakdown),
+		Subtotal:   subtotal,
+	}
+	quote.Total = quote.Subtotal
+
+	promotion, err := applyPromotion(ctx, q, quote, userID, req, show.MovieID, cinemaID, show.Date, lock)
+	if err != nil {
+		return nil, err
+	}
+	quote.Items, quote.ItemsTotal, err = priceItems(ctx, q, cinemaID, req.Items)
+	if err != nil {
+		return nil, err
+	}
+	quote.Total += quote.ItemsTotal
+
+	if err := applyPoints(ctx, q, program, quote, userID, req.RedeemPoints, lock); err != nil {
+		return nil, err
+	}
+	card, err := applyGiftCard(ctx, q, quote, req.GiftCardCode, lock)
+	if err != nil {
+		return nil, err
+	}
+	for _, gc := range req.GiftCards {
+		quote.GiftCardsTotal += gc.Amount
+	}
+	quote.Total += quote.GiftCardsTotal
+	quote.AmountDue = quote.Total - quote.GiftCardAmount
+
+	tier, err := currentTier(ctx, q, program, userID, 0)
+	if err != nil {
+		return nil, err
+	}
+	quote.PointsEarned = program.Earn(quote.Total-quote.GiftCardsTotal, tier)
+	return &pricedOrder{Quote: quote, CinemaID: cinemaID, Pricing: breakdown, Promotion: promotion, GiftCard: card}, nil
+}
+
+// appliedRules: hanya rule yang berlaku, untuk ditampilkan ke user
+func appliedRules(b models.PriceBreakdown) []models.PriceRuleResult {
+	applied := []models.PriceRuleResult{}
+	for _, r := range b.Rules {
+		if r.Applied {
+			applied = append(applied, r)
+		}
+	}
+	return applied
+}
+
+func applyPromotion(ctx context.Context, q querier, quote *models.Quote, userID int, req models.QuoteRequest,
+	movieID, cinemaID int, showDate time.Time, lock bool) (*models.Promotion, error) {
+	code := promo.Normalize(req.PromoCode)
+	if code == "" {
+		return nil, nil
+	}
+
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE code = $1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	p, err := scanPromotion(q.QueryRow(ctx, query, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, promo.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var userUses int
+	if err := q.QueryRow(ctx, `
+		SELECT COUNT(*) FROM promotion_redemptions
+		WHERE promotion_id = $1 AND user_id = $2`, p.ID, userID).Scan(&userUses); err != nil {
+		return nil, err
+	}
+
+	discount, err := promo.Discount(*p, promo.Order{
+		MovieID:  movieID,
+		CinemaID: cinemaID,
+		ShowDate: showDate,
+		Seats:    len(req.Seats),
+		Subtotal: quote.Subtotal,
+		UserUses: userUses,
+		Now:      time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	quote.PromoCode = p.Code
+	quote.Discount = discount
+	quote.Total -= discount
+	return p, nil
+}
+
+// applyPoints menukar paling banyak redeem poin; ditolak jika melebihi saldo
+func applyPoints(ctx context.Context, q querier, program *loyalty.Program, quote *models.Quote, userID, redeem int, lock bool) error {
+	if redeem <= 0 {
+		return nil
+	}
+	if lock {
+		// saldo dihitung dari ledger, jadi baris user yang dikunci
+		if _, err := q.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+			return err
+		}
+	}
+	balance, err := pointsBalance(ctx, q, userID)
+	if err != nil {
+		return err
+	}
+	if redeem > balance {
+		return ErrInsufficientPoints
+	}
+
+	quote.PointsRedeemed, quote.PointsDiscount = program.Redeem(redeem, quote.Total)
+	quote.Total -= quote.PointsDiscount
+	return nil
+}
+
+// applyGiftCard membayar total tiket dan add-on dengan saldo gift card sebanyak mungkin
+func applyGiftCard(ctx context.Context, q querier, quote *models.Quote, code string, lock bool) (*models.GiftCard, error) {
+	if code == "" {
+		return nil, nil
+	}
+	card, err := usableGiftCard(ctx, q, code, lock)
+	if err != nil {
+		return nil, err
+	}
+	quote.GiftCardCode = giftcard.Mask(card.Code)
+	quote.GiftCardAmount = min(card.Balance, quote.Total)
+	return card, nil
+}
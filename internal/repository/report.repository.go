@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+type ReportRepository struct {
+	DB  *pgxpool.Pool
+	rdb *redis.Client
+}
+
+func NewReportRepository(db *pgxpool.Pool, rdb *redis.Client) *ReportRepository {
+	return &ReportRepository{DB: db, rdb: rdb}
+}
+
+// agregat berat cukup segar untuk dashboard
+const reportCacheTTL = 10 * time.Minute
+
+// ReportGranularities dan RevenueDimensions: nilai yang valid untuk parameter report
+var (
+	ReportGranularities = map[string]bool{"day": true, "week": true, "month": true}
+	RevenueDimensions   = map[string]bool{"": true, "movie": true, "cinema": true, "location": true}
+)
+
+// kolom, join dan group by tambahan untuk revenue per dimensi
+var revenueDimensions = map[string]struct{ columns, join, group string }{
+	"":         {},
+	"movie":    {"m.id AS movie_id, m.title AS movie,", "JOIN movies m ON m.id = o.movie_id", ", m.id, m.title"},
+	"cinema":   {"c.id AS cinema_id, c.name AS cinema,", "JOIN cinemas c ON c.id = o.cinema_id", ", c.id, c.name"},
+	"location": {"l.id AS location_id, l.location,", "JOIN locations l ON l.id = o.location_id", ", l.id, l.location"},
+}
+
+// paidOrders: order lunas dalam rentang tanggal beserta jumlah tiket dan info jadwal.
+// $1 = from, $2 = to, $4 = cinema_id (0 = semua)
+const paidOrders = `
+	WITH o AS (
+		SELECT o.id, o.total_price, o.order_date,
+		       s.movie_id, s.cinema_id, s.location_id,
+		       (SELECT COUNT(*) FROM order_seats os WHERE os.order_id = o.id) AS tickets
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedule_id
+		WHERE o.status = 'paid'
+		  AND o.order_date >= $1::date AND o.order_date < $2::date + 1
+		  AND ($4::int = 0 OR s.cinema_id = $4)
+	)`
+
+// Revenue: pendapatan, jumlah order dan tiket per periode (dan per movie/cinema/location)
+func (r *ReportRepository) Revenue(ctx context.Context, p models.ReportParams) (*models.Report, error) {
+	dim, ok := revenueDimensions[p.By]
+	if !ok {
+		return nil, fmt.Errorf("unknown revenue dimension %q", p.By)
+	}
+	query := paidOrders + fmt.Sprintf(`
+		SELECT TO_CHAR(date_trunc($3::text, o.order_date), 'YYYY-MM-DD') AS period,
+		       %s
+		       COUNT(*)::int AS orders,
+		       COALESCE(SUM(o.tickets), 0)::int AS tickets,
+		       COALESCE(SUM(o.total_price), 0)::bigint AS revenue
+		FROM o
+		%s
+		GROUP BY 1 %s
+		ORDER BY 1, revenue DESC`, dim.columns, dim.join, dim.group)
+
+	return r.cached(ctx, "revenue", p, query, p.From, p.To, p.Granularity, p.CinemaID)
+}
+
+// Occupancy: tiket terjual dibanding jumlah kursi aktif studio untuk setiap jadwal
+func (r *ReportRepository) Occupancy(ctx context.Context, p models.ReportParams) (*models.Report, error) {
+	query := `
+		SELECT TO_CHAR(date_trunc($3::text, s.date::timestamp), 'YYYY-MM-DD') AS period,
+		       s.id AS schedule_id,
+		       TO_CHAR(s.date, 'YYYY-MM-DD') AS date,
+		       TO_CHAR(t.start_time, 'HH24:MI') AS start_time,
+		       m.title AS movie,
+		       c.name AS cinema,
+		       a.name AS auditorium,
+		       cap.seats,
+		       sold.tickets AS tickets_sold,
+		       COALESCE(ROUND(100.0 * sold.tickets / NULLIF(cap.seats, 0), 2), 0)::float8 AS occupancy_pct
+		FROM schedules s
+		JOIN movies m ON m.id = s.movie_id
+		JOIN cinemas c ON c.id = s.cinema_id
+		JOIN auditoriums a ON a.id = s.auditorium_id
+		JOIN times t ON t.id = s.time_id
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*)::int AS seats FROM seats se
+			WHERE se.auditorium_id = s.auditorium_id AND se.is_active
+		) cap
+		CROSS JOIN LATERAL (
+			SELECT COUNT(*)::int AS tickets FROM order_seats os
+			JOIN orders o ON o.id = os.order_id
+			WHERE o.schedule_id = s.id AND o.status = 'paid'
+		) sold
+		WHERE s.date BETWEEN $1::date AND $2::date
+		  AND ($4::int = 0 OR s.cinema_id = $4)
+		ORDER BY s.date, t.start_time, s.id`
+
+	return r.cached(ctx, "occupancy", p, query, p.From, p.To, p.Granularity, p.CinemaID)
+}
+
+// TopMovies: peringkat movie berdasarkan revenue di setiap periode
+func (r *ReportRepository) TopMovies(ctx context.Context, p models.ReportParams) (*models.Report, error) {
+	query := paidOrders + `,
+	sales AS (
+		SELECT date_trunc($3::text, o.order_date) AS period, o.movie_id,
+		       COUNT(*)::int AS orders,
+		       COALESCE(SUM(o.tickets), 0)::int AS tickets,
+		       COALESCE(SUM(o.total_price), 0)::bigint AS revenue
+		FROM o
+		GROUP BY 1, 2
+	),
+	ranked AS (
+		SELECT sales.*, RANK() OVER (PARTITION BY period ORDER BY revenue DESC, tickets DESC)::int AS rank
+		FROM sales
+	)
+	SELECT TO_CHAR(ranked.period, 'YYYY-MM-DD') AS period, ranked.rank,
+	       m.id AS movie_id, m.title AS movie,
+	       ranked.orders, ranked.tickets, ranked.revenue
+	FROM ranked
+	JOIN movies m ON m.id = ranked.movie_id
+	WHERE ranked.rank <= $5
+	ORDER BY ranked.period, ranked.rank, m.title`
+
+	return r.cached(ctx, "top_movies", p, query, p.From, p.To, p.Granularity, p.CinemaID, p.Limit)
+}
+
+// TicketTypes: tiket terjual dan pendapatan kotor (harga tiket sebelum promo / poin) per tipe tiket
+func (r *ReportRepository) TicketTypes(ctx context.Context, p models.ReportParams) (*models.Report, error) {
+	query := `
+		SELECT TO_CHAR(date_trunc($3::text, o.order_date), 'YYYY-MM-DD') AS period,
+		       tt.code AS ticket_type, tt.name,
+		       COUNT(*)::int AS tickets,
+		       COUNT(DISTINCT o.id)::int AS orders,
+		       COALESCE(SUM(os.price), 0)::bigint AS gross_revenue
+		FROM orders o
+		JOIN schedules s ON s.id = o.schedule_id
+		JOIN order_seats os ON os.order_id = o.id
+		JOIN ticket_types tt ON tt.code = os.ticket_type
+		WHERE o.status = 'paid'
+		  AND o.order_date >= $1::date AND o.order_date < $2::date + 1
+		  AND ($4::int = 0 OR s.cinema_id = $4)
+		GROUP BY 1, tt.code, tt.name, tt.sort_order
+		ORDER BY 1, tt.sort_order`
+
+	return r.cached(ctx, "ticket_types", p, query, p.From, p.To, p.Granularity, p.CinemaID)
+}
+
+// cached membaca report dari Redis, atau menjalankan query lalu menyimpannya
+func (r *ReportRepository) cached(ctx context.Context, name string, p models.ReportParams, query string, args ...interface{}) (*models.Report, error) {
+	key := fmt.Sprintf("reports:%s:%s:%s:%s:%d:%s:%d", name, p.From, p.To, p.Granularity, p.CinemaID, p.By, p.Limit)
+	if data, err := r.rdb.Get(ctx, key).Bytes(); err == nil {
+		var cached models.Report
+		if json.Unmarshal(data, &cached) == nil {
+			return &cached, nil
+		}
+	}
+
+	report, err := r.query(ctx, name, p, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(report); err == nil {
+		if err := r.rdb.Set(ctx, key, data, reportCacheTTL).Err(); err != nil {
+			log.Println("Redis SET error:", err)
+		}
+	}
+	return report, nil
+}
+
+func (r *ReportRepository) query(ctx context.Context, name string, p models.ReportParams, query string, args ...interface{}) (*models.Report, error) {
+	rows, err := r.DB.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	report := &models.Report{Name: name, Params: p, GeneratedAt: time.Now(), Rows: []map[string]interface{}{}}
+	for _, f := range rows.FieldDescriptions() {
+		report.Columns = append(report.Columns, f.Name)
+	}
+
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(values))
+		for i, v := range values {
+			row[report.Columns[i]] = v
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, rows.Err()
+}
@@ -34,7 +34,11 @@ func (r *ScheduleRepository) GetGenres(ctx context.Context) ([]models.Genre, err
 }
 
 func (r *ScheduleRepository) GetCinemas(ctx context.Context) ([]models.Cinema, error) {
-	rows, err := r.DB.Query(ctx, "SELECT id, name FROM cinemas")
+	rows, err := r.DB.Query(ctx, `
+		SELECT id, name, address, logo_url, price_tier, is_active
+		FROM cinemas
+		WHERE is_active
+		ORDER BY name`)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +47,7 @@ func (r *ScheduleRepository) GetCinemas(ctx context.Context) ([]models.Cinema, e
 	var cinemas []models.Cinema
 	for rows.Next() {
 		var c models.Cinema
-		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+		if err := rows.Scan(&c.ID, &c.Name, &c.Address, &c.LogoURL, &c.PriceTier, &c.IsActive); err != nil {
 			return nil, err
 		}
 		cinemas = append(cinemas, c)
@@ -52,7 +56,7 @@ func (r *ScheduleRepository) GetCinemas(ctx context.Context) ([]models.Cinema, e
 }
 
 func (r *ScheduleRepository) GetLocations(ctx context.Context) ([]models.Location, error) {
-	rows, err := r.DB.Query(ctx, "SELECT id, location FROM locations")
+	rows, err := r.DB.Query(ctx, "SELECT id, location, is_active FROM locations WHERE is_active ORDER BY location")
 	if err != nil {
 		return nil, err
 	}
@@ -61,7 +65,7 @@ func (r *ScheduleRepository) GetLocations(ctx context.Context) ([]models.Locatio
 	var locations []models.Location
 	for rows.Next() {
 		var l models.Location
-		if err := rows.Scan(&l.ID, &l.Name); err != nil {
+		if err := rows.Scan(&l.ID, &l.Name, &l.IsActive); err != nil {
 			return nil, err
 		}
 		locations = append(locations, l)
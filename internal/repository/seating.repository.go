@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cristian-yw/Weekly10/internal/seating"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrUnknownSeat   = errors.New("seat does not exist in this auditorium")
+	ErrDuplicateSeat = errors.New("seat is listed more than once")
+)
+
+// CheckSeats: validasi kursi sebelum di-hold, lihat checkSeats.
+func (r *OrderRepository) CheckSeats(ctx context.Context, scheduleID int, seats []string) error {
+	var exists bool
+	if err := r.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schedules WHERE id = $1)`, scheduleID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrScheduleNotFound
+	}
+	return checkSeats(ctx, r.DB, scheduleID, seats)
+}
+
+// checkSeats: setiap kursi hanya boleh disebut sekali, harus kursi aktif di studio jadwal,
+// dan belum ada di order paid / pending. Di dalam transaksi order, baris jadwal harus sudah
+// dikunci (FOR UPDATE) supaya dua order untuk kursi yang sama tidak lolos bersamaan.
+func checkSeats(ctx context.Context, q querier, scheduleID int, seats []string) error {
+	seen := make(map[string]bool, len(seats))
+	for _, seat := range seats {
+		if seen[seat] {
+			return fmt.Errorf("%w: %s", ErrDuplicateSeat, seat)
+		}
+		seen[seat] = true
+	}
+
+	var unknown string
+	err := q.QueryRow(ctx, `
+		SELECT code
+		FROM unnest($2::text[]) AS code
+		WHERE NOT EXISTS (
+		    SELECT 1
+		    FROM schedules sch
+		    JOIN seats s ON s.auditorium_id = sch.auditorium_id
+		    WHERE sch.id = $1 AND s.is_active AND s.seat_code = code
+		)
+		LIMIT 1`, scheduleID, seats).Scan(&unknown)
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownSeat, unknown)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	var booked string
+	err = q.QueryRow(ctx, `
+		SELECT os.seat_code
+		FROM order_seats os
+		JOIN orders o ON o.id = os.order_id
+		WHERE o.schedule_id = $1 AND o.status IN ('paid', 'pending') AND os.seat_code = ANY($2)
+		LIMIT 1`, scheduleID, seats).Scan(&booked)
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrSeatBooked, booked)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+// SeatLayout: semua kursi aktif di studio jadwal beserta jenisnya. Free = false untuk kursi
+// yang sudah ada di order paid / pending. Hold di Redis diperiksa oleh handler.
+func (r *OrderRepository) SeatLayout(ctx context.Context, scheduleID int) ([]seating.Seat, error) {
+	var exists bool
+	if err := r.DB.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM schedules WHERE id = $1)`, scheduleID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrScheduleNotFound
+	}
+
+	rows, err := r.DB.Query(ctx, `
+		SELECT s.seat_code, s.seat_type,
+		       NOT EXISTS (
+		           SELECT 1
+		           FROM order_seats os
+		           JOIN orders o ON o.id = os.order_id
+		           WHERE o.schedule_id = sch.id
+		             AND o.status IN ('paid', 'pending')
+		             AND os.seat_code = s.seat_code
+		       ) AS free
+		FROM schedules sch
+		JOIN seats s ON s.auditorium_id = sch.auditorium_id
+		WHERE sch.id = $1 AND s.is_active
+		ORDER BY s.seat_code`, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	seats := []seating.Seat{}
+	for rows.Next() {
+		var s seating.Seat
+		if err := rows.Scan(&s.Code, &s.Type, &s.Free); err != nil {
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+	return seats, rows.Err()
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/cristian-yw/Weekly10/internal/tmdb"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ID TMDB khusus test supaya data lain di database tidak tersentuh
+const (
+	testMovieBase  = 990000
+	testPersonBase = 991000
+	testGenreID    = 990001
+)
+
+// testDB membuka TEST_DATABASE_URL (database yang sudah dimigrasi); test dilewati jika kosong
+func testDB(t *testing.T) *pgxpool.Pool {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	cleanupSyncData(t, db)
+	t.Cleanup(func() { cleanupSyncData(t, db) })
+	return db
+}
+
+func cleanupSyncData(t *testing.T, db *pgxpool.Pool) {
+	ctx := context.Background()
+	for _, q := range []string{
+		`DELETE FROM movie_casts WHERE movie_id IN (SELECT id FROM movies WHERE tmdb_id BETWEEN 990000 AND 990999)`,
+		`DELETE FROM movie_genres WHERE movie_id IN (SELECT id FROM movies WHERE tmdb_id BETWEEN 990000 AND 990999)`,
+		`DELETE FROM movie_categories WHERE movie_id IN (SELECT id FROM movies WHERE tmdb_id BETWEEN 990000 AND 990999)`,
+		`DELETE FROM movies WHERE tmdb_id BETWEEN 990000 AND 990999`,
+		`DELETE FROM persons WHERE tmdb_id BETWEEN 991000 AND 991999`,
+		`DELETE FROM genres WHERE tmdb_id = 990001`,
+	} {
+		if _, err := db.Exec(ctx, q); err != nil {
+			t.Fatalf("cleanup: %v", err)
+		}
+	}
+}
+
+// fakeTMDB: stand-in TMDB dengan totalPages halaman berisi perPage movie.
+// Setiap movie punya satu director (di antara crew lain) dan tiga cast.
+type fakeTMDB struct {
+	mu         sync.Mutex
+	totalPages int
+	perPage    int
+	title      string // awalan judul, diganti untuk mensimulasikan perubahan di TMDB
+	pages      []int
+	details    int
+}
+
+func (f *fakeTMDB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/genre/movie/list":
+		json.NewEncoder(w).Encode(map[string]any{
+			"genres": []map[string]any{{"id": testGenreID, "name": "Sync Test Genre"}},
+		})
+
+	case r.URL.Path == "/movie/popular":
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		f.pages = append(f.pages, page)
+		results := []map[string]any{}
+		for i := 0; i < f.perPage; i++ {
+			results = append(results, map[string]any{"id": testMovieBase + page*10 + i, "title": "listed"})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"page": page, "total_pages": f.totalPages, "results": results})
+
+	case strings.HasPrefix(r.URL.Path, "/movie/"):
+		if r.URL.Query().Get("append_to_response") != "credits" {
+			http.Error(w, `{"status_message":"credits not appended"}`, http.StatusBadRequest)
+			return
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/movie/"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		f.details++
+		person := testPersonBase + (id-testMovieBase)*10
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":     id,
+			"title":  fmt.Sprintf("%s %d", f.title, id),
+			"genres": []map[string]any{{"id": testGenreID, "name": "Sync Test Genre"}},
+			"credits": map[string]any{
+				"crew": []map[string]any{
+					{"id": person, "name": "Producer", "job": "Producer"},
+					{"id": person + 1, "name": "Director", "job": "Director"},
+				},
+				"cast": []map[string]any{
+					{"id": person + 2, "name": "Lead", "order": 0},
+					{"id": person + 3, "name": "Second", "order": 1},
+					{"id": person + 4, "name": "Extra", "order": 2},
+				},
+			},
+		})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestSyncMovies(t *testing.T) {
+	db := testDB(t)
+	fake := &fakeTMDB{totalPages: 2, perPage: 2, title: "Synced"}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	repo := NewAdminRepository(db, tmdb.New(tmdb.Options{BaseURL: srv.URL, BearerToken: "test", RequestsPerSecond: -1}), nil)
+	ctx := context.Background()
+	// minta 5 halaman, TMDB hanya punya 2
+	opts := models.SyncOptions{List: "popular", Pages: 5, CastLimit: 2}
+
+	result, err := repo.SyncMovies(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(fake.pages) != "[1 2]" {
+		t.Fatalf("requested pages %v, want [1 2]", fake.pages)
+	}
+	if result.Pages != 2 || result.Processed != 4 || result.Created != 4 || result.Updated != 0 || result.Failed != 0 {
+		t.Fatalf("first sync = %+v", result)
+	}
+
+	movieID := testMovieBase + 11
+	assertCredits(t, db, movieID, []string{"Actor:Lead", "Actor:Second", "Director:Director"})
+
+	// sync ulang: movie di-update, credits tidak terduplikasi
+	fake.title = "Renamed"
+	fake.pages = nil
+	result, err = repo.SyncMovies(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Processed != 4 || result.Created != 0 || result.Updated != 4 || result.Failed != 0 {
+		t.Fatalf("re-sync = %+v", result)
+	}
+
+	var title string
+	var movies int
+	if err := db.QueryRow(ctx, `SELECT title FROM movies WHERE tmdb_id = $1`, movieID).Scan(&title); err != nil {
+		t.Fatal(err)
+	}
+	if title != fmt.Sprintf("Renamed %d", movieID) {
+		t.Fatalf("title after re-sync = %q", title)
+	}
+	if err := db.QueryRow(ctx, `SELECT COUNT(*) FROM movies WHERE tmdb_id BETWEEN 990000 AND 990999`).Scan(&movies); err != nil {
+		t.Fatal(err)
+	}
+	if movies != 4 {
+		t.Fatalf("movies after re-sync = %d, want 4", movies)
+	}
+	assertCredits(t, db, movieID, []string{"Actor:Lead", "Actor:Second", "Director:Director"})
+}
+
+// assertCredits: role:nama person yang terhubung ke movie, urut role lalu nama
+func assertCredits(t *testing.T, db *pgxpool.Pool, tmdbID int, want []string) {
+	t.Helper()
+	rows, err := db.Query(context.Background(), `
+		SELECT mc.role || ':' || p.name
+		FROM movie_casts mc
+		JOIN movies m ON m.id = mc.movie_id
+		JOIN persons p ON p.id = mc.person_id
+		WHERE m.tmdb_id = $1
+		ORDER BY mc.role, p.name`, tmdbID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var got []string
+	for rows.Next() {
+		var s string
+		if err := rows.Scan(&s); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, s)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("credits = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrUnknownTicketType: tipe tiket tidak ada atau tidak aktif
+	ErrUnknownTicketType = errors.New("unknown ticket type")
+	// ErrDuplicatePriceRule: sudah ada aturan harga untuk tipe dan cakupan yang sama
+	ErrDuplicatePriceRule = errors.New("price rule for this ticket type and scope already exists")
+)
+
+// defaultTicketType dipakai untuk kursi yang tidak diberi tipe
+const defaultTicketType = "adult"
+
+const ticketPriceColumns = `id, ticket_type, cinema_id, schedule_id, price, percent, created_at`
+
+type TicketRepository struct {
+	DB *pgxpool.Pool
+}
+
+func NewTicketRepository(db *pgxpool.Pool) *TicketRepository {
+	return &TicketRepository{DB: db}
+}
+
+func (r *TicketRepository) ListTypes(ctx context.Context) ([]models.TicketType, error) {
+	rows, err := r.DB.Query(ctx, `SELECT code, name, sort_order, is_active FROM ticket_types ORDER BY sort_order, code`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := []models.TicketType{}
+	for rows.Next() {
+		var t models.TicketType
+		if err := rows.Scan(&t.Code, &t.Name, &t.SortOrder, &t.IsActive); err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, rows.Err()
+}
+
+// SaveType membuat atau mengubah tipe tiket
+func (r *TicketRepository) SaveType(ctx context.Context, code string, req models.TicketTypeRequest) (*models.TicketType, error) {
+	isActive := req.IsActive == nil || *req.IsActive
+	var t models.TicketType
+	err := r.DB.QueryRow(ctx, `
+		INSERT INTO ticket_types (code, name, sort_order, is_active)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (code) DO UPDATE
+		SET name = EXCLUDED.name, sort_order = EXCLUDED.sort_order, is_active = EXCLUDED.is_active
+		RETURNING code, name, sort_order, is_active`, code, req.Name, req.SortOrder, isActive).
+		Scan(&t.Code, &t.Name, &t.SortOrder, &t.IsActive)
+	return &t, err
+}
+
+func (r *TicketRepository) ListPriceRules(ctx context.Context) ([]models.TicketPriceRule, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+ticketPriceColumns+`
+		FROM ticket_prices
+		ORDER BY ticket_type, schedule_id NULLS FIRST, cinema_id NULLS FIRST`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.TicketPriceRule{}
+	for rows.Next() {
+		var p models.TicketPriceRule
+		if err := rows.Scan(&p.ID, &p.TicketType, &p.CinemaID, &p.ScheduleID, &p.Price, &p.Percent, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, p)
+	}
+	return rules, rows.Err()
+}
+
+func (r *TicketRepository) CreatePriceRule(ctx context.Context, req models.TicketPriceRuleRequest) (*models.TicketPriceRule, error) {
+	var p models.TicketPriceRule
+	err := r.DB.QueryRow(ctx, `
+		INSERT INTO ticket_prices (ticket_type, cinema_id, schedule_id, price, percent)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+ticketPriceColumns, req.TicketType, req.CinemaID, req.ScheduleID, req.Price, req.Percent).
+		Scan(&p.ID, &p.TicketType, &p.CinemaID, &p.ScheduleID, &p.Price, &p.Percent, &p.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return nil, ErrDuplicatePriceRule
+		case "23503": // tipe tiket, cinema atau jadwal tidak ada
+			return nil, pgx.ErrNoRows
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *TicketRepository) DeletePriceRule(ctx context.Context, id int) error {
+	tag, err := r.DB.Exec(ctx, `DELETE FROM ticket_prices WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// TicketPrices: harga setiap tipe tiket aktif untuk satu jadwal, setelah aturan harga dinamis
+func (r *OrderRepository) TicketPrices(ctx context.Context, scheduleID int) ([]models.TicketPrice, error) {
+	show, breakdown, err := schedulePrice(ctx, r.DB, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	return ticketPrices(ctx, r.DB, scheduleID, show.CinemaID, breakdown.Price)
+}
+
+// ticketPrices memilih aturan paling spesifik per tipe tiket (jadwal, lalu cinema,
+// lalu umum). basePrice = harga jadwal setelah aturan harga dinamis; percent dihitung
+// dari basePrice, price tetap tidak terpengaruh. Tipe tanpa aturan memakai basePrice.
+func ticketPrices(ctx context.Context, q querier, scheduleID, cinemaID, basePrice int) ([]models.TicketPrice, error) {
+	rows, err := q.Query(ctx, `
+		SELECT t.code, t.name, COALESCE(r.price, $3::int * r.percent / 100, $3::int)
+		FROM ticket_types t
+		LEFT JOIN LATERAL (
+			SELECT p.price, p.percent
+			FROM ticket_prices p
+			WHERE p.ticket_type = t.code
+			  AND (p.schedule_id = $1 OR p.cinema_id = $2 OR (p.schedule_id IS NULL AND p.cinema_id IS NULL))
+			ORDER BY (p.schedule_id IS NOT NULL) DESC, (p.cinema_id IS NOT NULL) DESC
+			LIMIT 1
+		) r ON TRUE
+		WHERE t.is_active
+		ORDER BY t.sort_order, t.code`, scheduleID, cinemaID, basePrice)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []models.TicketPrice{}
+	for rows.Next() {
+		var p models.TicketPrice
+		if err := rows.Scan(&p.Type, &p.Name, &p.Price); err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	return prices, rows.Err()
+}
+
+// priceTickets memberi harga setiap kursi sesuai tipe tiketnya dan mengembalikan subtotal
+func priceTickets(ctx context.Context, q querier, scheduleID, cinemaID, basePrice int, seats []string, types map[string]string) ([]models.Ticket, int, error) {
+	prices, err := ticketPrices(ctx, q, scheduleID, cinemaID, basePrice)
+	if err != nil {
+		return nil, 0, err
+	}
+	byType := make(map[string]int, len(prices))
+	for _, p := range prices {
+		byType[p.Type] = p.Price
+	}
+
+	tickets := make([]models.Ticket, 0, len(seats))
+	subtotal := 0
+	for _, seat := range seats {
+		ticketType := types[seat]
+		if ticketType == "" {
+			ticketType = defaultTicketType
+		}
+		price, ok := byType[ticketType]
+		if !ok {
+			return nil, 0, fmt.Errorf("%w: %s", ErrUnknownTicketType, ticketType)
+		}
+		tickets = append(tickets, models.Ticket{Seat: seat, Type: ticketType, Price: price})
+		subtotal += price
+	}
+	return tickets, subtotal, nil
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrAlreadyWaitlisted = errors.New("already on the waitlist for this schedule")
+	ErrNotWaitlisted     = errors.New("not on the waitlist for this schedule")
+)
+
+const waitlistColumns = `id, schedule_id, user_id, seat_count, status, offered_seats, offered_at, offer_expires_at, created_at`
+
+func scanWaitlistEntry(row pgx.Row) (*models.WaitlistEntry, error) {
+	var e models.WaitlistEntry
+	if err := row.Scan(&e.ID, &e.ScheduleID, &e.UserID, &e.Count, &e.Status, &e.OfferedSeats,
+		&e.OfferedAt, &e.OfferExpiresAt, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// JoinWaitlist menambah user ke akhir waitlist. Pemanggil memastikan jadwal memang sold out.
+func (r *OrderRepository) JoinWaitlist(ctx context.Context, scheduleID, userID, count int) (*models.WaitlistEntry, error) {
+	var started bool
+	err := r.DB.QueryRow(ctx, `
+		SELECT (s.date + t.start_time)::timestamptz <= NOW()
+		FROM schedules s
+		JOIN times t ON t.id = s.time_id
+		WHERE s.id = $1`, scheduleID).Scan(&started)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if started {
+		return nil, ErrShowStarted
+	}
+
+	e, err := scanWaitlistEntry(r.DB.QueryRow(ctx, `
+		INSERT INTO schedule_waitlist (schedule_id, user_id, seat_count)
+		VALUES ($1, $2, $3)
+		RETURNING `+waitlistColumns, scheduleID, userID, count))
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrAlreadyWaitlisted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.withPosition(ctx, e)
+}
+
+// GetWaitlist: entry terakhir user untuk jadwal, termasuk yang sudah selesai
+func (r *OrderRepository) GetWaitlist(ctx context.Context, scheduleID, userID int) (*models.WaitlistEntry, error) {
+	e, err := scanWaitlistEntry(r.DB.QueryRow(ctx, `
+		SELECT `+waitlistColumns+`
+		FROM schedule_waitlist
+		WHERE schedule_id = $1 AND user_id = $2
+		ORDER BY id DESC
+		LIMIT 1`, scheduleID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotWaitlisted
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r.withPosition(ctx, e)
+}
+
+// LeaveWaitlist keluar dari waitlist. Jika sedang offered, kursi yang ditawarkan
+// dikembalikan supaya hold-nya bisa dilepas pemanggil.
+func (r *OrderRepository) LeaveWaitlist(ctx context.Context, scheduleID, userID int) (*models.WaitlistEntry, error) {
+	e, err := scanWaitlistEntry(r.DB.QueryRow(ctx, `
+		UPDATE schedule_waitlist SET status = 'left'
+		WHERE schedule_id = $1 AND user_id = $2 AND status IN ('waiting', 'offered')
+		RETURNING `+waitlistColumns, scheduleID, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotWaitlisted
+	}
+	return e, err
+}
+
+// WaitingEntries: antrian jadwal yang belum mendapat tawaran, urut FIFO
+func (r *OrderRepository) WaitingEntries(ctx context.Context, scheduleID int) ([]models.WaitlistEntry, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT `+waitlistColumns+`
+		FROM schedule_waitlist
+		WHERE schedule_id = $1 AND status = 'waiting'
+		ORDER BY id`, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.WaitlistEntry{}
+	for rows.Next() {
+		e, err := scanWaitlistEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *e)
+	}
+	return entries, rows.Err()
+}
+
+// OfferWaitlist mencatat tawaran kursi dan notifikasi waitlist_offer dalam satu transaksi.
+// Batas tawaran dihitung dari waktu notifikasi ditulis (NOW() transaksi ini) + ttl.
+// false jika entry sudah tidak waiting (keluar / sudah ditawari).
+func (r *OrderRepository) OfferWaitlist(ctx context.Context, entryID int, seats []string, ttl time.Duration) (time.Time, bool, error) {
+	tx, err := r.DB.BeginTx(ctx, pgx.TxOptions{})
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var userID int
+	n := &models.WaitlistOfferNotification{Seats: seats}
+	err = tx.QueryRow(ctx, `
+		UPDATE schedule_waitlist
+		SET status = 'offered', offered_seats = $2, offered_at = NOW(), offer_expires_at = NOW() + $3 * INTERVAL '1 second'
+		WHERE id = $1 AND status = 'waiting'
+		RETURNING user_id, schedule_id, offer_expires_at`, entryID, seats, int64(ttl.Seconds())).Scan(&userID, &n.ScheduleID, &n.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	err = tx.QueryRow(ctx, `
+		SELECT m.title, c.name, TO_CHAR(s.date, 'YYYY-MM-DD'), TO_CHAR(t.start_time, 'HH24:MI')
+		FROM schedules s
+		JOIN movies m ON m.id = s.movie_id
+		JOIN cinemas c ON c.id = s.cinema_id
+		JOIN times t ON t.id = s.time_id
+		WHERE s.id = $1`, n.ScheduleID).Scan(&n.MovieTitle, &n.Cinema, &n.Date, &n.StartTime)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	// satu notifikasi per tawaran
+	dedupKey := "waitlist_offer:" + strconv.Itoa(entryID)
+	if err := enqueueNotification(ctx, tx, userID, "waitlist_offer", dedupKey, n); err != nil {
+		return time.Time{}, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return time.Time{}, false, err
+	}
+	return n.ExpiresAt, true, nil
+}
+
+// ExpireWaitlistOffers menandai tawaran yang lewat waktu sebagai expired. Hold kursinya
+// kadaluarsa sendiri di Redis, lalu kursi ditawarkan ke antrian berikutnya.
+func (r *OrderRepository) ExpireWaitlistOffers(ctx context.Context) error {
+	_, err := r.DB.Exec(ctx, `
+		UPDATE schedule_waitlist SET status = 'expired'
+		WHERE status = 'offered' AND offer_expires_at <= NOW()`)
+	return err
+}
+
+// WaitlistSchedules: jadwal yang belum tayang dan masih punya antrian waiting
+func (r *OrderRepository) WaitlistSchedules(ctx context.Context) ([]int, error) {
+	rows, err := r.DB.Query(ctx, `
+		SELECT DISTINCT w.schedule_id
+		FROM schedule_waitlist w
+		JOIN schedules s ON s.id = w.schedule_id
+		JOIN times t ON t.id = s.time_id
+		WHERE w.status = 'waiting' AND (s.date + t.start_time)::timestamptz > NOW()
+		ORDER BY w.schedule_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// withPosition mengisi posisi antrian untuk entry yang masih waiting
+func (r *OrderRepository) withPosition(ctx context.Context, e *models.WaitlistEntry) (*models.WaitlistEntry, error) {
+	if e.Status != "waiting" {
+		return e, nil
+	}
+	err := r.DB.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM schedule_waitlist
+		WHERE schedule_id = $1 AND status = 'waiting' AND id <= $2`, e.ScheduleID, e.ID).Scan(&e.Position)
+	return e, err
+}
+
+// markWaitlistBooked dipanggil di transaksi CreateOrder: entry user untuk jadwal ini selesai
+func markWaitlistBooked(ctx context.Context, q querier, scheduleID, userID int) error {
+	_, err := q.Exec(ctx, `
+		UPDATE schedule_waitlist SET status = 'booked'
+		WHERE schedule_id = $1 AND user_id = $2 AND status IN ('waiting', 'offered')`, scheduleID, userID)
+	return err
+}
@@ -1,25 +1,60 @@
 package routers
 
 import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
 	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/jobs"
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/models"
 	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/cristian-yw/Weekly10/internal/tmdb"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
 func InitAdminMovieRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
-	movieRepo := repository.NewAdminRepository(db)
-	movieHandler := handlers.NewAdminHandler(movieRepo)
+	// genre list TMDB di-cache di Redis, atau di disk jika TMDB_CACHE_DIR diisi
+	var tmdbCache tmdb.Cache = tmdb.NewRedisCache(rdb)
+	if dir := os.Getenv("TMDB_CACHE_DIR"); dir != "" {
+		tmdbCache = tmdb.NewDiskCache(dir)
+	}
+	tmdbClient := tmdb.NewFromEnv(tmdbCache)
+
+	mediaStore := media.NewStoreFromEnv()
+	movieRepo := repository.NewAdminRepository(db, tmdbClient, mediaStore)
+	syncRunner := jobs.NewSyncRunner(movieRepo, rdb)
+	movieHandler := handlers.NewAdminHandler(movieRepo, syncRunner, mediaStore)
+
+	// contoh: TMDB_SYNC_CRON="0 3 * * *" TMDB_SYNC_LIST=upcoming TMDB_SYNC_PAGES=3 TMDB_MIRROR_IMAGES=true
+	if spec := os.Getenv("TMDB_SYNC_CRON"); spec != "" {
+		pages, _ := strconv.Atoi(os.Getenv("TMDB_SYNC_PAGES"))
+		mirror, _ := strconv.ParseBool(os.Getenv("TMDB_MIRROR_IMAGES"))
+		opts := models.SyncOptions{List: os.Getenv("TMDB_SYNC_LIST"), Pages: pages, MirrorImages: mirror}
+		if err := jobs.StartSyncSchedule(context.Background(), syncRunner, spec, opts); err != nil {
+			log.Println("TMDB_SYNC_CRON:", err)
+		}
+	}
 
 	admin := r.Group("/admin")
 	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
 	{
+		admin.POST("/sync", movieHandler.SyncMovies)
 		admin.POST("/sync/popular", movieHandler.SyncPopular)
-		admin.POST("/movies", movieHandler.CreateMovie)       // Create Movie
-		admin.GET("/movies/:id", movieHandler.GetMovieByID)   // Get Movie by ID
-		admin.PATCH("/movies/:id", movieHandler.PatchMovie)   // Update Movie
+		admin.GET("/sync/jobs/:id", movieHandler.GetSyncJob)
+		admin.POST("/movies", movieHandler.CreateMovie) // Create Movie
+		admin.POST("/movies/import/:tmdbId", movieHandler.ImportMovie)
+		admin.POST("/import", movieHandler.ImportMovies) // bulk CSV/JSON
+		admin.GET("/tmdb/search", movieHandler.SearchTMDB)
+		admin.GET("/movies/:id", movieHandler.GetMovieByID) // Get Movie by ID
+		admin.PATCH("/movies/:id", movieHandler.PatchMovie) // Update Movie
+		admin.PATCH("/movies/:id/images", movieHandler.ReplaceMovieImages)
 		admin.DELETE("/movies/:id", movieHandler.DeleteMovie) // Delete Movie
+		admin.PUT("/users/:id/role", movieHandler.SetUserRole)
 	}
 }
@@ -0,0 +1,42 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitCinemaRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	cinemaRepo := repository.NewCinemaRepository(db)
+	cinemaHandler := handlers.NewCinemaHandler(cinemaRepo)
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		admin.GET("/cinemas", cinemaHandler.ListCinemas)
+		admin.POST("/cinemas", cinemaHandler.CreateCinema)
+		admin.PATCH("/cinemas/:id", cinemaHandler.UpdateCinema)
+		admin.DELETE("/cinemas/:id", cinemaHandler.RetireCinema) // soft delete
+
+		admin.GET("/cinemas/:id/auditoriums", cinemaHandler.ListAuditoriums)
+		admin.POST("/cinemas/:id/auditoriums", cinemaHandler.CreateAuditorium)
+		admin.PATCH("/auditoriums/:id", cinemaHandler.UpdateAuditorium)
+		admin.DELETE("/auditoriums/:id", cinemaHandler.RetireAuditorium)
+		admin.GET("/auditoriums/:id/seats", cinemaHandler.ListSeats)
+		admin.POST("/auditoriums/:id/seats", cinemaHandler.AddSeats)
+		admin.DELETE("/seats/:id", cinemaHandler.RetireSeat)
+
+		admin.GET("/locations", cinemaHandler.ListLocations)
+		admin.POST("/locations", cinemaHandler.CreateLocation)
+		admin.PATCH("/locations/:id", cinemaHandler.UpdateLocation)
+		admin.DELETE("/locations/:id", cinemaHandler.RetireLocation)
+
+		admin.GET("/times", cinemaHandler.ListTimes)
+		admin.POST("/times", cinemaHandler.CreateTime)
+		admin.PATCH("/times/:id", cinemaHandler.UpdateTime)
+		admin.DELETE("/times/:id", cinemaHandler.RetireTime)
+	}
+}
@@ -0,0 +1,35 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitConcessionRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	concessionRepo := repository.NewConcessionRepository(db)
+	concessionHandler := handlers.NewConcessionHandler(concessionRepo)
+
+	r.GET("/cinemas/:id/concessions", concessionHandler.Menu)
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		admin.GET("/concessions", concessionHandler.ListItems)
+		admin.POST("/concessions", concessionHandler.CreateItem)
+		admin.PATCH("/concessions/:id", concessionHandler.UpdateItem)
+		admin.DELETE("/concessions/:id", concessionHandler.DeactivateItem) // soft delete
+		admin.GET("/cinemas/:id/concessions", concessionHandler.CinemaItems)
+		admin.PUT("/cinemas/:id/concessions/:itemId", concessionHandler.SetCinemaItem)
+	}
+
+	staff := r.Group("/staff")
+	staff.Use(middleware.AuthMiddleware(rdb), middleware.StaffOnly())
+	{
+		staff.GET("/orders/:id/items", concessionHandler.PickupOrder)
+		staff.POST("/orders/:id/pickup", concessionHandler.Pickup)
+	}
+}
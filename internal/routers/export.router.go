@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitExportRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	exportRepo := repository.NewExportRepository(db)
+	exportHandler := handlers.NewExportHandler(exportRepo)
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		admin.GET("/export/:dataset", exportHandler.Export) // csv, jsonl, xlsx
+	}
+}
@@ -0,0 +1,29 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitGiftCardRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	giftCardRepo := repository.NewGiftCardRepository(db)
+	giftCardHandler := handlers.NewGiftCardHandler(giftCardRepo)
+
+	admin := r.Group("/admin/gift-cards")
+	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		admin.POST("", giftCardHandler.Issue)
+		admin.GET("/:code", giftCardHandler.Lookup)
+		admin.POST("/:code/void", giftCardHandler.Void)
+	}
+
+	user := r.Group("/gift-cards")
+	user.Use(middleware.AuthMiddleware(rdb))
+	{
+		user.GET("/:code", giftCardHandler.Balance)
+	}
+}
@@ -0,0 +1,41 @@
+package routers
+
+import (
+	"context"
+	"log"
+
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/jobs"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/notify"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitNotificationRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
+
+	// outbox dikirim di background, lihat notify.NewChannelFromEnv untuk konfigurasi channel
+	worker, err := notify.NewWorkerFromEnv(notificationRepo)
+	if err != nil {
+		log.Fatalf("notification config error: %v", err)
+	}
+	go worker.Run(context.Background())
+
+	// pengingat jam tayang masuk outbox yang sama, lihat SHOWTIME_REMINDERS
+	reminders, err := jobs.NewReminderSchedulerFromEnv(repository.NewJobRepository(db))
+	if err != nil {
+		log.Fatalf("showtime reminder config error: %v", err)
+	}
+	go reminders.Run(context.Background())
+
+	admin := r.Group("/admin/notifications")
+	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		admin.GET("", notificationHandler.List)
+		admin.POST("/:id/retry", notificationHandler.Retry)
+	}
+}
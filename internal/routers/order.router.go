@@ -1,7 +1,11 @@
 package routers
 
 import (
+	"context"
+
+	"github.com/cristian-yw/Weekly10/internal/booking"
 	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/middleware"
 	"github.com/cristian-yw/Weekly10/internal/repository"
 	"github.com/gin-gonic/gin"
@@ -12,14 +16,56 @@ import (
 func InitOrderRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 	// buat repository dan handler
 	orderRepo := repository.NewOrderRepository(db)
-	orderHandler := handlers.NewOrderHandler(orderRepo)
+
+	// status kursi real-time: hold di Redis, event disebar lewat Redis pub/sub
+	seatHub := booking.NewHub(rdb)
+	seatHolds := booking.NewHolds(rdb, seatHub)
+	go seatHub.Run(context.Background(), seatHolds)
+
+	orderHandler := handlers.NewOrderHandler(orderRepo, media.NewStoreFromEnv(), seatHolds, seatHub, booking.NewWaitRoom(rdb))
+	// kursi group booking yang belum dibayar dilepas setelah deadline
+	go orderHandler.RunGroupDeadlines(context.Background())
+	// kursi yang dilepas ditawarkan dulu ke waitlist
+	go orderHandler.RunWaitlist(context.Background())
 
 	api := r.Group("/orders")
 	api.Use(middleware.AuthMiddleware(rdb), middleware.UserOnly())
 	{
 		api.GET("/:movieId/schedules", orderHandler.GetSchedule)
 		api.GET("/seats/:scheduleId", orderHandler.GetAvailableSeats)
+		api.POST("/seats/:scheduleId/suggest", orderHandler.SuggestSeats)
+		api.GET("/tickets/:scheduleId", orderHandler.GetTicketPrices)
+		api.POST("/seats/:scheduleId/hold", orderHandler.HoldSeats)
+		api.DELETE("/seats/:scheduleId/hold", orderHandler.ReleaseSeats)
+		api.POST("/seats/:scheduleId/stream-token", orderHandler.SeatStreamToken)
+		api.POST("/quote", orderHandler.Quote)
 		api.POST("/", orderHandler.CreateOrder)
+
+		// group booking: satu order per anggota
+		api.POST("/groups", orderHandler.CreateGroup)
+		api.GET("/groups/:token", orderHandler.GetGroup)
+		api.DELETE("/groups/:token", orderHandler.CancelGroup)
+		api.POST("/groups/:token/claim", orderHandler.ClaimGroupSeat)
+		api.DELETE("/groups/:token/claim", orderHandler.UnclaimGroupSeat)
+		api.POST("/groups/:token/quote", orderHandler.QuoteGroupSeat)
+		api.POST("/groups/:token/pay", orderHandler.PayGroupSeat)
+
+		// waiting room
+		api.POST("/queue/:scheduleId", orderHandler.JoinQueue)
+		api.GET("/queue/:scheduleId", orderHandler.QueueStatus)
+		api.DELETE("/queue/:scheduleId", orderHandler.LeaveQueue)
 	}
 	api.GET("/:movieId", orderHandler.GetMovieDetail)
+	// EventSource tidak bisa mengirim header Authorization, jadi stream memakai
+	// token berumur pendek di query (lihat POST /orders/seats/:scheduleId/stream-token)
+	r.GET("/orders/seats/:scheduleId/stream", middleware.SeatStreamAuth(), orderHandler.StreamSeats)
+
+	// waitlist jadwal yang sold out
+	schedules := r.Group("/schedules")
+	schedules.Use(middleware.AuthMiddleware(rdb), middleware.UserOnly())
+	{
+		schedules.POST("/:id/waitlist", orderHandler.JoinWaitlist)
+		schedules.GET("/:id/waitlist", orderHandler.GetWaitlist)
+		schedules.DELETE("/:id/waitlist", orderHandler.LeaveWaitlist)
+	}
 }
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/booking"
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitPaymentRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	paymentRepo := repository.NewPaymentRepository(db)
+	// hanya untuk publish event kursi, tidak perlu Run
+	paymentHandler := handlers.NewPaymentHandler(paymentRepo, booking.NewHub(rdb))
+
+	// tanpa JWT, request diverifikasi lewat signature HMAC
+	r.POST("/payments/webhook", paymentHandler.Webhook)
+}
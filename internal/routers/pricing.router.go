@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitPricingRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	pricingRepo := repository.NewPricingRepository(db)
+	pricingHandler := handlers.NewPricingHandler(pricingRepo)
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		admin.GET("/pricing-rules", pricingHandler.ListRules)
+		admin.POST("/pricing-rules", pricingHandler.CreateRule)
+		admin.GET("/pricing-rules/preview", pricingHandler.Preview)
+		admin.GET("/pricing-rules/:id", pricingHandler.GetRule)
+		admin.PUT("/pricing-rules/:id", pricingHandler.UpdateRule)
+		admin.DELETE("/pricing-rules/:id", pricingHandler.DeactivateRule) // soft delete
+		admin.GET("/orders/:id/pricing", pricingHandler.OrderPricing)
+	}
+}
@@ -2,6 +2,7 @@ package routers
 
 import (
 	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/middleware"
 	"github.com/cristian-yw/Weekly10/internal/repository"
 	"github.com/gin-gonic/gin"
@@ -11,7 +12,7 @@ import (
 
 func InitUserRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 	userRepo := repository.NewUserRepository(db)
-	userHandler := handlers.NewUserHandler(userRepo)
+	userHandler := handlers.NewUserHandler(userRepo, media.NewStoreFromEnv())
 
 	api := r.Group("/user")
 	api.Use(middleware.AuthMiddleware(rdb))
@@ -19,6 +20,7 @@ func InitUserRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 
 		api.GET("/profile", userHandler.GetProfile)
 		api.GET("/history", userHandler.GetHistory)
+		api.GET("/points", userHandler.GetPoints)
 		api.PATCH("/profile", userHandler.UpdateProfile)
 		api.PATCH("/password", userHandler.ChangePassword)
 	}
@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitPromotionRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	promotionRepo := repository.NewPromotionRepository(db)
+	promotionHandler := handlers.NewPromotionHandler(promotionRepo)
+
+	promotions := r.Group("/admin/promotions")
+	promotions.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		promotions.GET("", promotionHandler.ListPromotions)
+		promotions.POST("", promotionHandler.CreatePromotion)
+		promotions.GET("/:id", promotionHandler.GetPromotion)
+		promotions.PUT("/:id", promotionHandler.UpdatePromotion)
+		promotions.DELETE("/:id", promotionHandler.DeactivatePromotion) // soft delete
+		promotions.GET("/:id/redemptions", promotionHandler.ListRedemptions)
+	}
+}
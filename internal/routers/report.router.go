@@ -0,0 +1,24 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitReportRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	reportRepo := repository.NewReportRepository(db, rdb)
+	reportHandler := handlers.NewReportHandler(reportRepo)
+
+	reports := r.Group("/admin/reports")
+	reports.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		reports.GET("/revenue", reportHandler.Revenue)
+		reports.GET("/occupancy", reportHandler.Occupancy)
+		reports.GET("/top-movies", reportHandler.TopMovies)
+		reports.GET("/ticket-types", reportHandler.TicketTypes)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	docs "github.com/cristian-yw/Weekly10/docs"
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/storage"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
@@ -22,12 +24,26 @@ func InitRouter(db *pgxpool.Pool, rdb *redis.Client) *gin.Engine {
 	InitOrderRouter(router, db, rdb)
 	InitUserRouter(router, db, rdb)
 	InitAdminMovieRouter(router, db, rdb)
+	InitCinemaRouter(router, db, rdb)
+	InitExportRouter(router, db, rdb)
+	InitReportRouter(router, db, rdb)
+	InitWaitRoomRouter(router, rdb)
+	InitPromotionRouter(router, db, rdb)
+	InitPaymentRouter(router, db, rdb)
+	InitGiftCardRouter(router, db, rdb)
+	InitConcessionRouter(router, db, rdb)
+	InitTicketRouter(router, db, rdb)
+	InitPricingRouter(router, db, rdb)
+	InitNotificationRouter(router, db, rdb)
 	Initschedule(router, db, rdb)
 
 	docs.SwaggerInfo.BasePath = "/"
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	router.Static("/uploads", "./uploads")
+	// upload hanya di-serve dari disk jika STORAGE_DRIVER=local (default ./uploads)
+	if local, ok := media.NewStoreFromEnv().Backend.(*storage.Local); ok {
+		router.Static(local.PublicPath, local.Root)
+	}
 
 	router.NoRoute(func(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{
@@ -0,0 +1,25 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/cristian-yw/Weekly10/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitTicketRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
+	ticketRepo := repository.NewTicketRepository(db)
+	ticketHandler := handlers.NewTicketHandler(ticketRepo)
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		admin.GET("/ticket-types", ticketHandler.ListTypes)
+		admin.PUT("/ticket-types/:code", ticketHandler.SaveType)
+		admin.GET("/ticket-prices", ticketHandler.ListPriceRules)
+		admin.POST("/ticket-prices", ticketHandler.CreatePriceRule)
+		admin.DELETE("/ticket-prices/:id", ticketHandler.DeletePriceRule)
+	}
+}
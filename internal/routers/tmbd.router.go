@@ -2,6 +2,7 @@ package routers
 
 import (
 	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/media"
 	"github.com/cristian-yw/Weekly10/internal/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -10,7 +11,7 @@ import (
 
 func InitMovieRouter(r *gin.Engine, db *pgxpool.Pool, rdb *redis.Client) {
 	movieRepo := repository.NewMovieRepository(db, rdb)
-	movieHandler := handlers.NewMovieHandler(movieRepo)
+	movieHandler := handlers.NewMovieHandler(movieRepo, media.NewStoreFromEnv())
 
 	api := r.Group("/movies")
 	{
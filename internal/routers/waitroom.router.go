@@ -0,0 +1,21 @@
+package routers
+
+import (
+	"github.com/cristian-yw/Weekly10/internal/booking"
+	"github.com/cristian-yw/Weekly10/internal/handlers"
+	"github.com/cristian-yw/Weekly10/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitWaitRoomRouter(r *gin.Engine, rdb *redis.Client) {
+	waitRoomHandler := handlers.NewWaitRoomHandler(booking.NewWaitRoom(rdb))
+
+	waitroom := r.Group("/admin/waitroom")
+	waitroom.Use(middleware.AuthMiddleware(rdb), middleware.AdminOnly())
+	{
+		waitroom.GET("/:scope/:id", waitRoomHandler.Get)
+		waitroom.PUT("/:scope/:id", waitRoomHandler.Put)
+		waitroom.DELETE("/:scope/:id", waitRoomHandler.Delete)
+	}
+}
@@ -0,0 +1,228 @@
+// Package seating memilih blok kursi terbaik untuk satu jadwal (best available).
+// Package ini tidak mengakses database atau Redis; pemanggil mengirim denah kursi lengkap
+// beserta status kosong / terisi. Untuk input yang sama hasilnya selalu sama.
+package seating
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+const (
+	TypeStandard   = "standard"
+	TypeWheelchair = "wheelchair"
+	TypeCouple     = "couple"
+
+	// MaxCount: jumlah kursi maksimal dalam satu saran
+	MaxCount = 10
+
+	// idealRowRatio: posisi baris ideal dihitung dari depan (0) ke belakang (1)
+	idealRowRatio = 2.0 / 3.0
+	rowWeight     = 1.0
+	centerWeight  = 0.8
+)
+
+var (
+	ErrInvalidCount = errors.New("count must be between 1 and 10")
+	ErrCoupleCount  = errors.New("couple seats are sold in pairs, count must be even")
+	ErrWheelchair   = errors.New("wheelchair spaces cannot exceed count")
+	ErrNoBlock      = errors.New("no contiguous block of free seats matches the request")
+)
+
+// Seat: satu kursi aktif di studio. Code berformat baris + nomor, mis. "A1", "AA12".
+type Seat struct {
+	Code string
+	Type string
+	Free bool
+}
+
+// Request: kebutuhan blok kursi.
+// Wheelchair = jumlah ruang kursi roda yang harus ada di dalam blok; tanpa itu ruang kursi roda
+// tidak pernah disarankan. Couple = blok hanya terdiri dari kursi couple utuh per pasang.
+type Request struct {
+	Count      int
+	Wheelchair int
+	Couple     bool
+}
+
+type position struct {
+	Seat
+	number int
+	pair   int // index pasangan couple dalam satu baris, -1 untuk kursi biasa
+}
+
+type row struct {
+	name  string
+	seats []position
+}
+
+// Validate memeriksa request sebelum denah dimuat
+func (r Request) Validate() error {
+	switch {
+	case r.Count < 1 || r.Count > MaxCount:
+		return ErrInvalidCount
+	case r.Couple && r.Count%2 != 0:
+		return ErrCoupleCount
+	case r.Wheelchair < 0 || r.Wheelchair > r.Count:
+		return ErrWheelchair
+	case r.Couple && r.Wheelchair > 0:
+		return ErrWheelchair
+	}
+	return nil
+}
+
+// Suggest mencari blok kursi bersebelahan (nomor berurutan tanpa celah / lorong) di satu baris.
+// Skor = jarak baris ke baris ideal + jarak tengah blok ke tengah baris, keduanya dinormalisasi 0-1.
+// Jika skor sama, baris lebih depan lalu nomor kursi lebih kecil yang dipilih.
+func Suggest(seats []Seat, req Request) (*models.SeatSuggestion, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	rows := layout(seats)
+	if len(rows) == 0 {
+		return nil, ErrNoBlock
+	}
+	ideal := idealRowRatio * float64(len(rows)-1)
+	rowSpan := math.Max(1, float64(len(rows)-1))
+
+	var best *models.SeatSuggestion
+	for ri, r := range rows {
+		first, last := r.seats[0].number, r.seats[len(r.seats)-1].number
+		center := float64(first+last) / 2
+		halfWidth := math.Max(1, float64(last-first)/2)
+		rowScore := rowWeight * math.Abs(float64(ri)-ideal) / rowSpan
+
+		for start := 0; start+req.Count <= len(r.seats); start++ {
+			block := r.seats[start : start+req.Count]
+			if !fits(block, req) {
+				continue
+			}
+			mid := float64(block[0].number+block[len(block)-1].number) / 2
+			score := rowScore + centerWeight*math.Abs(mid-center)/halfWidth
+			// dibulatkan supaya perbedaan floating point tidak mengubah urutan
+			score = math.Round(score*1e6) / 1e6
+			if best == nil || score < best.Score {
+				codes := make([]string, len(block))
+				for i, p := range block {
+					codes[i] = p.Code
+				}
+				best = &models.SeatSuggestion{Row: r.name, Seats: codes, Score: score}
+			}
+		}
+	}
+	if best == nil {
+		return nil, ErrNoBlock
+	}
+	return best, nil
+}
+
+// fits: blok harus kosong, bersebelahan, dan memenuhi aturan kursi roda / couple
+func fits(block []position, req Request) bool {
+	wheelchair := 0
+	for i, p := range block {
+		if !p.Free {
+			return false
+		}
+		if i > 0 && p.number != block[i-1].number+1 {
+			return false
+		}
+		switch p.Type {
+		case TypeWheelchair:
+			wheelchair++
+		case TypeCouple:
+			if !req.Couple {
+				return false
+			}
+		default:
+			if req.Couple {
+				return false
+			}
+		}
+	}
+	if wheelchair != req.Wheelchair {
+		return false
+	}
+	if req.Couple {
+		// setiap dua kursi harus satu pasangan, jadi pasangan tidak pernah terpotong
+		for i := 0; i < len(block); i += 2 {
+			if block[i].pair < 0 || block[i].pair != block[i+1].pair {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// layout mengelompokkan kursi per baris (urut nama baris, A paling dekat layar) dan nomor kursi.
+// Kursi couple dipasangkan berurutan dari awal setiap deret couple: 1-2, 3-4, dst.
+func layout(seats []Seat) []row {
+	byRow := map[string][]position{}
+	for _, s := range seats {
+		name, number, ok := ParseCode(s.Code)
+		if !ok {
+			continue
+		}
+		if s.Type == "" {
+			s.Type = TypeStandard
+		}
+		byRow[name] = append(byRow[name], position{Seat: s, number: number, pair: -1})
+	}
+
+	names := make([]string, 0, len(byRow))
+	for name := range byRow {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, compareRows)
+
+	rows := make([]row, 0, len(names))
+	for _, name := range names {
+		ps := byRow[name]
+		slices.SortFunc(ps, func(a, b position) int { return a.number - b.number })
+
+		pair, run := 0, 0
+		for i := range ps {
+			contiguous := i > 0 && ps[i].number == ps[i-1].number+1 && ps[i-1].Type == TypeCouple
+			if ps[i].Type != TypeCouple {
+				run = 0
+				continue
+			}
+			if !contiguous {
+				run = 0
+			}
+			if run%2 == 0 {
+				pair++
+			}
+			ps[i].pair = pair
+			run++
+		}
+		rows = append(rows, row{name: name, seats: ps})
+	}
+	return rows
+}
+
+// ParseCode memecah kode kursi menjadi baris dan nomor: "B12" -> ("B", 12)
+func ParseCode(code string) (string, int, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	i := strings.IndexFunc(code, func(r rune) bool { return r >= '0' && r <= '9' })
+	if i <= 0 {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(code[i:])
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return code[:i], number, true
+}
+
+// compareRows: "B" sebelum "AA" (baris dua huruf ada di belakang baris satu huruf)
+func compareRows(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
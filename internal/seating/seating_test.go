@@ -0,0 +1,244 @@
+package seating
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// grid: baris-baris dengan nomor 1..n, semua kosong dan standard
+func grid(rows string, n int) []Seat {
+	var seats []Seat
+	for _, r := range rows {
+		for i := 1; i <= n; i++ {
+			seats = append(seats, Seat{Code: string(r) + strconv.Itoa(i), Type: TypeStandard, Free: true})
+		}
+	}
+	return seats
+}
+
+// with mengubah kursi tertentu pada denah
+func with(seats []Seat, change func(*Seat), codes ...string) []Seat {
+	for i := range seats {
+		if slices.Contains(codes, seats[i].Code) {
+			change(&seats[i])
+		}
+	}
+	return seats
+}
+
+func taken(s *Seat)      { s.Free = false }
+func wheelchair(s *Seat) { s.Type = TypeWheelchair }
+func couple(s *Seat)     { s.Type = TypeCouple }
+
+func without(seats []Seat, codes ...string) []Seat {
+	return slices.DeleteFunc(seats, func(s Seat) bool { return slices.Contains(codes, s.Code) })
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name  string
+		seats []Seat
+		req   Request
+		row   string
+		want  []string
+		score float64
+	}{
+		{
+			name:  "ideal row two thirds back, centred",
+			seats: grid("ABCD", 10),
+			req:   Request{Count: 2},
+			row:   "C",
+			want:  []string{"C5", "C6"},
+			score: 0,
+		},
+		{
+			name:  "same score picks the front row",
+			seats: with(grid("ABCD", 10), taken, "C1", "C2", "C3", "C4", "C5", "C6", "C7", "C8", "C9", "C10"),
+			req:   Request{Count: 2},
+			row:   "B",
+			want:  []string{"B5", "B6"},
+			score: 1.0 / 3,
+		},
+		{
+			name:  "same score picks the lower seat number",
+			seats: grid("A", 10),
+			req:   Request{Count: 3},
+			row:   "A",
+			want:  []string{"A4", "A5", "A6"},
+			score: 0.8 * 0.5 / 4.5,
+		},
+		{
+			name:  "taken seat breaks the block",
+			seats: with(grid("A", 10), taken, "A5"),
+			req:   Request{Count: 4},
+			row:   "A",
+			want:  []string{"A6", "A7", "A8", "A9"},
+		},
+		{
+			name:  "aisle breaks the block",
+			seats: without(grid("A", 10), "A5"),
+			req:   Request{Count: 4},
+			row:   "A",
+			want:  []string{"A6", "A7", "A8", "A9"},
+		},
+		{
+			name:  "wheelchair spaces are skipped unless requested",
+			seats: with(grid("A", 8), wheelchair, "A4", "A5"),
+			req:   Request{Count: 2},
+			row:   "A",
+			want:  []string{"A2", "A3"},
+		},
+		{
+			name:  "exactly one wheelchair space",
+			seats: with(grid("A", 8), wheelchair, "A1", "A2"),
+			req:   Request{Count: 2, Wheelchair: 1},
+			row:   "A",
+			want:  []string{"A2", "A3"},
+		},
+		{
+			name:  "exactly two wheelchair spaces",
+			seats: with(grid("A", 8), wheelchair, "A1", "A2"),
+			req:   Request{Count: 2, Wheelchair: 2},
+			row:   "A",
+			want:  []string{"A1", "A2"},
+		},
+		{
+			name:  "couple pair is never split",
+			seats: with(with(grid("A", 6), couple, "A1", "A2", "A3", "A4", "A5", "A6"), taken, "A4"),
+			req:   Request{Count: 2, Couple: true},
+			row:   "A",
+			want:  []string{"A1", "A2"},
+		},
+		{
+			name:  "couple pairs start at the beginning of each couple run",
+			seats: with(grid("A", 8), couple, "A3", "A4", "A5", "A6", "A7", "A8"),
+			req:   Request{Count: 2, Couple: true},
+			row:   "A",
+			want:  []string{"A3", "A4"},
+		},
+		{
+			name:  "standard request skips couple seats",
+			seats: with(grid("A", 8), couple, "A3", "A4", "A5", "A6"),
+			req:   Request{Count: 2},
+			row:   "A",
+			want:  []string{"A1", "A2"},
+		},
+		{
+			name:  "two letter rows sit behind single letter rows",
+			seats: append(grid("AB", 4), Seat{Code: "AA1", Free: true}, Seat{Code: "AA2", Free: true}, Seat{Code: "AA3", Free: true}, Seat{Code: "AA4", Free: true}),
+			req:   Request{Count: 2},
+			row:   "B",
+			want:  []string{"B2", "B3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Suggest(tt.seats, tt.req)
+			if err != nil {
+				t.Fatalf("Suggest: %v", err)
+			}
+			if got.Row != tt.row || !slices.Equal(got.Seats, tt.want) {
+				t.Fatalf("got %s %v, want %s %v", got.Row, got.Seats, tt.row, tt.want)
+			}
+			if tt.score != 0 && got.Score != roundScore(tt.score) {
+				t.Fatalf("score = %v, want %v", got.Score, roundScore(tt.score))
+			}
+		})
+	}
+}
+
+func roundScore(f float64) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'f', 6, 64), 64)
+	return v
+}
+
+func TestSuggestErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		seats []Seat
+		req   Request
+		want  error
+	}{
+		{"count zero", grid("A", 10), Request{Count: 0}, ErrInvalidCount},
+		{"count over max", grid("A", 20), Request{Count: MaxCount + 1}, ErrInvalidCount},
+		{"odd couple count", grid("A", 10), Request{Count: 3, Couple: true}, ErrCoupleCount},
+		{"wheelchair over count", grid("A", 10), Request{Count: 1, Wheelchair: 2}, ErrWheelchair},
+		{"wheelchair with couple", grid("A", 10), Request{Count: 2, Wheelchair: 1, Couple: true}, ErrWheelchair},
+		{"no seats", nil, Request{Count: 1}, ErrNoBlock},
+		{"all taken", with(grid("A", 3), taken, "A1", "A2", "A3"), Request{Count: 1}, ErrNoBlock},
+		{"row too short", grid("AB", 3), Request{Count: 4}, ErrNoBlock},
+		{"no wheelchair space", grid("A", 10), Request{Count: 2, Wheelchair: 1}, ErrNoBlock},
+		{"no couple seats", grid("A", 10), Request{Count: 2, Couple: true}, ErrNoBlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Suggest(tt.seats, tt.req); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLayout(t *testing.T) {
+	seats := []Seat{
+		{Code: "AA1", Free: true},
+		{Code: "B3", Type: TypeCouple, Free: true},
+		{Code: "B1", Type: TypeCouple, Free: true},
+		{Code: "B2", Type: TypeCouple, Free: true},
+		{Code: "B5", Type: TypeCouple, Free: true},
+		{Code: "B6", Type: TypeCouple, Free: true},
+		{Code: "A2", Free: true},
+		{Code: "bad", Free: true},
+	}
+	rows := layout(seats)
+
+	var names []string
+	for _, r := range rows {
+		names = append(names, r.name)
+	}
+	if !slices.Equal(names, []string{"A", "B", "AA"}) {
+		t.Fatalf("rows = %v", names)
+	}
+	if got := rows[0].seats[0].Type; got != TypeStandard {
+		t.Fatalf("empty type = %q, want %q", got, TypeStandard)
+	}
+
+	var numbers, pairs []int
+	for _, p := range rows[1].seats {
+		numbers = append(numbers, p.number)
+		pairs = append(pairs, p.pair)
+	}
+	if !slices.Equal(numbers, []int{1, 2, 3, 5, 6}) {
+		t.Fatalf("numbers = %v", numbers)
+	}
+	// B3 sendirian (B4 tidak ada), B5-B6 mulai pasangan baru
+	if !slices.Equal(pairs, []int{1, 1, 2, 3, 3}) {
+		t.Fatalf("pairs = %v", pairs)
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		code   string
+		row    string
+		number int
+		ok     bool
+	}{
+		{"A1", "A", 1, true},
+		{"B12", "B", 12, true},
+		{" aa3 ", "AA", 3, true},
+		{"12", "", 0, false},
+		{"A", "", 0, false},
+		{"A0", "", 0, false},
+		{"A1B", "", 0, false},
+		{"", "", 0, false},
+	}
+	for _, tt := range tests {
+		row, number, ok := ParseCode(tt.code)
+		if row != tt.row || number != tt.number || ok != tt.ok {
+			t.Errorf("ParseCode(%q) = %q, %d, %v; want %q, %d, %v", tt.code, row, number, ok, tt.row, tt.number, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Local menyimpan object di folder Root dan di-serve router.Static di PublicPath
+type Local struct {
+	Root       string
+	PublicPath string
+}
+
+func NewLocal(root, publicPath string) *Local {
+	return &Local{Root: root, PublicPath: publicPath}
+}
+
+func (l *Local) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	dst := l.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+
+	// tulis ke file sementara dulu supaya file yang sedang di-serve tidak pernah setengah jadi
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	err := os.Remove(l.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (l *Local) URL(ctx context.Context, key string) (string, error) {
+	return path.Join(l.PublicPath, key), nil
+}
+
+func (l *Local) path(key string) string {
+	// path.Clean dengan prefix "/" mencegah key keluar dari Root (mis. "../../etc")
+	return filepath.Join(l.Root, filepath.FromSlash(path.Clean("/"+key)))
+}
@@ -0,0 +1,63 @@
+// Package storage menyimpan object (gambar upload) di disk lokal atau di
+// object storage yang kompatibel dengan S3 (AWS S3, MinIO, R2, dll).
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Storage: backend penyimpanan object. Key selalu memakai "/" sebagai pemisah,
+// mis. "avatars/original/abc.png".
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// URL mengembalikan URL publik, atau URL bertanda tangan jika bucket private
+	URL(ctx context.Context, key string) (string, error)
+}
+
+// NewFromEnv memilih backend dari STORAGE_DRIVER (local | s3).
+//
+//	local: UPLOAD_DIR (default "uploads"), di-serve di /uploads
+//	s3:    S3_ENDPOINT, S3_REGION, S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY,
+//	       S3_PUBLIC_URL (opsional, tanpa ini URL di-presign), S3_PRESIGN_TTL,
+//	       S3_PATH_STYLE
+func NewFromEnv() (Storage, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", "local":
+		root := os.Getenv("UPLOAD_DIR")
+		if root == "" {
+			root = "uploads"
+		}
+		return NewLocal(root, "/uploads"), nil
+	case "s3":
+		ttl := time.Hour
+		if v := os.Getenv("S3_PRESIGN_TTL"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("S3_PRESIGN_TTL: %w", err)
+			}
+			ttl = d
+		}
+		pathStyle := os.Getenv("S3_ENDPOINT") != ""
+		if v := os.Getenv("S3_PATH_STYLE"); v != "" {
+			pathStyle, _ = strconv.ParseBool(v)
+		}
+		return NewS3(S3Options{
+			Endpoint:   os.Getenv("S3_ENDPOINT"),
+			Region:     os.Getenv("S3_REGION"),
+			Bucket:     os.Getenv("S3_BUCKET"),
+			AccessKey:  os.Getenv("S3_ACCESS_KEY"),
+			SecretKey:  os.Getenv("S3_SECRET_KEY"),
+			PublicURL:  os.Getenv("S3_PUBLIC_URL"),
+			PresignTTL: ttl,
+			PathStyle:  pathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q", driver)
+	}
+}
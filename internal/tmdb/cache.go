@@ -0,0 +1,75 @@
+package tmdb
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cache menyimpan response TMDB yang jarang berubah (mis. daftar genre)
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+}
+
+type RedisCache struct {
+	rdb *redis.Client
+}
+
+func NewRedisCache(rdb *redis.Client) *RedisCache {
+	return &RedisCache{rdb: rdb}
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	data, err := c.rdb.Get(ctx, "tmdb:cache:"+key).Bytes()
+	return data, err == nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	c.rdb.Set(ctx, "tmdb:cache:"+key, value, ttl)
+}
+
+// DiskCache menyimpan satu file per key. Waktu kadaluarsa disimpan sebagai mtime file.
+type DiskCache struct {
+	dir string
+}
+
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *DiskCache) Get(_ context.Context, key string) ([]byte, bool) {
+	p := c.path(key)
+	info, err := os.Stat(p)
+	if err != nil || time.Now().After(info.ModTime()) {
+		return nil, false
+	}
+	data, err := os.ReadFile(p)
+	return data, err == nil
+}
+
+func (c *DiskCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, value, 0o644); err != nil {
+		return
+	}
+	expires := time.Now().Add(ttl)
+	if err := os.Chtimes(tmp, expires, expires); err != nil {
+		os.Remove(tmp)
+		return
+	}
+	os.Rename(tmp, c.path(key))
+}
@@ -0,0 +1,325 @@
+// Package tmdb adalah client untuk The Movie Database API (v3) dengan timeout,
+// rate limiting, retry dan cache untuk data yang jarang berubah.
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristian-yw/Weekly10/internal/models"
+)
+
+const (
+	DefaultBaseURL      = "https://api.themoviedb.org/3"
+	DefaultImageBaseURL = "https://image.tmdb.org/t/p"
+
+	maxImageBytes = 20 << 20
+	// maxBackoff: jeda terlama antar retry, termasuk Retry-After dari server
+	maxBackoff = 30 * time.Second
+)
+
+// ErrImageTooLarge: gambar CDN melebihi maxImageBytes
+var ErrImageTooLarge = errors.New("tmdb image is too large")
+
+type Options struct {
+	BaseURL      string
+	ImageBaseURL string
+	// BearerToken (v4 read access token) diutamakan; jika kosong dipakai APIKey (v3)
+	BearerToken string
+	APIKey      string
+
+	Timeout           time.Duration
+	RequestsPerSecond float64
+	Burst             int
+	MaxRetries        int
+	RetryBackoff      time.Duration
+
+	// Cache opsional untuk daftar genre
+	Cache    Cache
+	CacheTTL time.Duration
+}
+
+type Client struct {
+	opts    Options
+	http    *http.Client
+	limiter *rateLimiter
+}
+
+// APIError: response non-2xx dari TMDB
+type APIError struct {
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("tmdb %s: status %d: %s", e.Path, e.StatusCode, e.Message)
+}
+
+func New(opts Options) *Client {
+	if opts.BaseURL == "" {
+		opts.BaseURL = DefaultBaseURL
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+	if opts.ImageBaseURL == "" {
+		opts.ImageBaseURL = DefaultImageBaseURL
+	}
+	opts.ImageBaseURL = strings.TrimRight(opts.ImageBaseURL, "/")
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.RequestsPerSecond == 0 {
+		opts.RequestsPerSecond = 20
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = 10
+	}
+	if opts.MaxRetries < 0 {
+		opts.MaxRetries = 0
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 500 * time.Millisecond
+	}
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = 24 * time.Hour
+	}
+
+	return &Client{
+		opts:    opts,
+		http:    &http.Client{Timeout: opts.Timeout},
+		limiter: newRateLimiter(opts.RequestsPerSecond, opts.Burst),
+	}
+}
+
+// NewFromEnv membaca TMDB_BASE_URL, TMDB_IMAGE_BASE_URL, TMDB_TOKEN (v4), API_KEY (v3) dan TMDB_RATE_LIMIT
+func NewFromEnv(cache Cache) *Client {
+	rate, _ := strconv.ParseFloat(os.Getenv("TMDB_RATE_LIMIT"), 64)
+	return New(Options{
+		BaseURL:           os.Getenv("TMDB_BASE_URL"),
+		ImageBaseURL:      os.Getenv("TMDB_IMAGE_BASE_URL"),
+		BearerToken:       os.Getenv("TMDB_TOKEN"),
+		APIKey:            os.Getenv("API_KEY"),
+		RequestsPerSecond: rate,
+		MaxRetries:        3,
+		Cache:             cache,
+	})
+}
+
+// Genres: daftar genre movie, di-cache jika Cache diset
+func (c *Client) Genres(ctx context.Context) ([]models.TMDBGenre, error) {
+	const cacheKey = "genre/movie/list"
+	if c.opts.Cache != nil {
+		if data, ok := c.opts.Cache.Get(ctx, cacheKey); ok {
+			var cached models.TMDBGenreResponse
+			if json.Unmarshal(data, &cached) == nil {
+				return cached.Genres, nil
+			}
+		}
+	}
+
+	var resp models.TMDBGenreResponse
+	if err := c.get(ctx, "/genre/movie/list", nil, &resp); err != nil {
+		return nil, err
+	}
+
+	if c.opts.Cache != nil {
+		if data, err := json.Marshal(resp); err == nil {
+			c.opts.Cache.Set(ctx, cacheKey, data, c.opts.CacheTTL)
+		}
+	}
+	return resp.Genres, nil
+}
+
+// MovieList: satu halaman dari /movie/{list} (popular, upcoming, now_playing, top_rated)
+func (c *Client) MovieList(ctx context.Context, list string, page int) (*models.TMDBResponse, error) {
+	var resp models.TMDBResponse
+	query := url.Values{"page": {strconv.Itoa(page)}}
+	if err := c.get(ctx, "/movie/"+list, query, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SearchMovies: /search/movie
+func (c *Client) SearchMovies(ctx context.Context, query string, page int) (*models.TMDBResponse, error) {
+	var resp models.TMDBResponse
+	params := url.Values{
+		"query":         {query},
+		"page":          {strconv.Itoa(page)},
+		"include_adult": {"false"},
+	}
+	if err := c.get(ctx, "/search/movie", params, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// MovieDetail: /movie/{id} beserta credits
+func (c *Client) MovieDetail(ctx context.Context, tmdbID int) (*models.TMDBMovieDetail, error) {
+	var detail models.TMDBMovieDetail
+	query := url.Values{"append_to_response": {"credits"}}
+	if err := c.get(ctx, fmt.Sprintf("/movie/%d", tmdbID), query, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// ImageURL: URL CDN gambar TMDB, size mis. "w500" atau "original"
+func (c *Client) ImageURL(path, size string) string {
+	return c.opts.ImageBaseURL + "/" + size + path
+}
+
+// Image mengunduh gambar dari CDN TMDB (tidak memakai kuota rate limit API)
+func (c *Client) Image(ctx context.Context, path, size string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.ImageURL(path, size), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Path: path, StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+	if resp.ContentLength > maxImageBytes {
+		return nil, fmt.Errorf("%w: %s", ErrImageTooLarge, path)
+	}
+	// satu byte lebih supaya gambar yang terpotong terdeteksi
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageBytes {
+		return nil, fmt.Errorf("%w: %s", ErrImageTooLarge, path)
+	}
+	return data, nil
+}
+
+// get melakukan GET dengan rate limit dan retry (exponential backoff + jitter)
+// untuk error jaringan, 429 dan 5xx. Header Retry-After dihormati.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.opts.BearerToken == "" && c.opts.APIKey != "" {
+		query.Set("api_key", c.opts.APIKey)
+	}
+	endpoint := c.opts.BaseURL + path + "?" + query.Encode()
+
+	var lastErr error
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleep(ctx, c.backoff(attempt, lastErr)); err != nil {
+				return err
+			}
+		}
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+
+		retry, err := c.do(ctx, path, endpoint, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			return err
+		}
+	}
+	return lastErr
+}
+
+func (c *Client) do(ctx context.Context, path, endpoint string, out interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.opts.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.BearerToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var body struct {
+			StatusMessage string `json:"status_message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &body) != nil || body.StatusMessage == "" {
+			body.StatusMessage = http.StatusText(resp.StatusCode)
+		}
+
+		apiErr := &retryableError{
+			APIError:   &APIError{Path: path, StatusCode: resp.StatusCode, Message: body.StatusMessage},
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retry, apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("tmdb %s: decode: %w", path, err)
+	}
+	return false, nil
+}
+
+// retryableError membawa Retry-After dari response sebelumnya
+type retryableError struct {
+	*APIError
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Unwrap() error { return e.APIError }
+
+func (c *Client) backoff(attempt int, lastErr error) time.Duration {
+	if re, ok := lastErr.(*retryableError); ok && re.retryAfter > 0 {
+		return re.retryAfter
+	}
+	d := min(c.opts.RetryBackoff<<(attempt-1), maxBackoff)
+	return min(d+rand.N(d/2+1), maxBackoff)
+}
+
+// parseRetryAfter: detik atau HTTP date, dibatasi maxBackoff supaya server
+// tidak bisa menahan sync terlalu lama
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(min(secs, int(maxBackoff/time.Second))) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	}
+	return min(d, maxBackoff)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
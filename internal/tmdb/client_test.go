@@ -0,0 +1,281 @@
+package tmdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	return newTestClientWith(t, Options{BearerToken: "test-token", RequestsPerSecond: -1}, handler)
+}
+
+// newTestClientWith: client ke httptest server dengan opsi tambahan (BaseURL diisi otomatis)
+func newTestClientWith(t *testing.T, opts Options, handler http.HandlerFunc) *Client {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	opts.BaseURL, opts.ImageBaseURL = srv.URL, srv.URL
+	return New(opts)
+}
+
+func TestMovieListPages(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/movie/popular" || r.Header.Get("Authorization") != "Bearer test-token" {
+			http.Error(w, `{"status_message":"bad request"}`, http.StatusBadRequest)
+			return
+		}
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		n, _ := strconv.Atoi(page)
+		json.NewEncoder(w).Encode(map[string]any{
+			"page":        n,
+			"total_pages": 2,
+			"results":     []map[string]any{{"id": n*10 + 1, "title": "Movie " + page}},
+		})
+	})
+
+	for page := 1; page <= 2; page++ {
+		list, err := c.MovieList(context.Background(), "popular", page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if list.Page != page || list.TotalPages != 2 || len(list.Results) != 1 || list.Results[0].ID != page*10+1 {
+			t.Fatalf("page %d: %+v", page, list)
+		}
+	}
+	if len(pages) != 2 || pages[0] != "1" || pages[1] != "2" {
+		t.Fatalf("requested pages %v", pages)
+	}
+}
+
+func TestMovieDetailCredits(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/movie/550" || r.URL.Query().Get("append_to_response") != "credits" {
+			http.Error(w, `{"status_message":"credits not requested"}`, http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{
+			"id": 550, "title": "Fight Club", "runtime": 139,
+			"genres": [{"id": 18, "name": "Drama"}],
+			"credits": {
+				"cast": [{"id": 819, "name": "Edward Norton", "character": "Narrator", "order": 0}],
+				"crew": [{"id": 7467, "name": "David Fincher", "job": "Director"}]
+			}
+		}`))
+	})
+
+	d, err := c.MovieDetail(context.Background(), 550)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Title != "Fight Club" || len(d.Genres) != 1 || len(d.Credits.Cast) != 1 || len(d.Credits.Crew) != 1 {
+		t.Fatalf("detail = %+v", d)
+	}
+	if d.Credits.Crew[0].Job != "Director" || d.Credits.Cast[0].Name != "Edward Norton" {
+		t.Fatalf("credits = %+v", d.Credits)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"3600", maxBackoff},
+		{"99999999999999", maxBackoff},
+		{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), maxBackoff},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestImageSizeLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		chunked bool
+		wantErr error
+	}{
+		{"at limit", maxImageBytes, false, nil},
+		{"over limit with content length", maxImageBytes + 1, false, ErrImageTooLarge},
+		{"over limit chunked", maxImageBytes + 1, true, ErrImageTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if !tt.chunked {
+					w.Header().Set("Content-Length", strconv.Itoa(tt.size))
+				}
+				w.Write(bytes.Repeat([]byte{0xff}, tt.size))
+			})
+			data, err := c.Image(context.Background(), "/poster.jpg", "w780")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && len(data) != tt.size {
+				t.Fatalf("len = %d, want %d", len(data), tt.size)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		failures  int
+		wantCalls int32
+		wantErr   int
+	}{
+		{"429 then success", http.StatusTooManyRequests, 1, 2, 0},
+		{"500 then success", http.StatusInternalServerError, 2, 3, 0},
+		{"503 then success", http.StatusServiceUnavailable, 1, 2, 0},
+		{"404 is not retried", http.StatusNotFound, 1, 1, http.StatusNotFound},
+		{"gives up after max attempts", http.StatusBadGateway, 10, 3, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			opts := Options{BearerToken: "test-token", RequestsPerSecond: -1, MaxRetries: 2, RetryBackoff: time.Millisecond}
+			c := newTestClientWith(t, opts, func(w http.ResponseWriter, r *http.Request) {
+				if int(calls.Add(1)) <= tt.failures {
+					http.Error(w, `{"status_message":"try again"}`, tt.status)
+					return
+				}
+				w.Write([]byte(`{"page": 1, "total_pages": 1, "results": []}`))
+			})
+
+			_, err := c.MovieList(context.Background(), "popular", 1)
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", got, tt.wantCalls)
+			}
+			if tt.wantErr == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantErr || apiErr.Message != "try again" {
+				t.Fatalf("err = %v, want status %d", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	opts := Options{BearerToken: "test-token", RequestsPerSecond: -1, MaxRetries: 1, RetryBackoff: time.Millisecond}
+	c := newTestClientWith(t, opts, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"page": 1, "total_pages": 1, "results": []}`))
+	})
+
+	start := time.Now()
+	if _, err := c.MovieList(context.Background(), "popular", 1); err != nil {
+		t.Fatal(err)
+	}
+	// Retry-After mengalahkan RetryBackoff 1ms
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Fatalf("retried after %v, want at least 1s", elapsed)
+	}
+
+	// Retry-After yang terlalu lama dibatasi maxBackoff
+	long := &retryableError{APIError: &APIError{StatusCode: http.StatusTooManyRequests}, retryAfter: parseRetryAfter("3600")}
+	if got := c.backoff(1, long); got != maxBackoff {
+		t.Fatalf("backoff with Retry-After 3600 = %v, want %v", got, maxBackoff)
+	}
+	// tanpa Retry-After: exponential backoff + jitter, tetap di bawah maxBackoff
+	for attempt := 1; attempt <= 20; attempt++ {
+		base := min(c.opts.RetryBackoff<<(attempt-1), maxBackoff)
+		if got := c.backoff(attempt, nil); got < base || got > maxBackoff {
+			t.Fatalf("backoff(%d) = %v, want between %v and %v", attempt, got, base, maxBackoff)
+		}
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	// 20 token/detik dengan burst 2: dua request langsung, sisanya satu per 50ms
+	l := newRateLimiter(20, 2)
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Fatalf("burst took %v", elapsed)
+	}
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("4 tokens after %v, want at least 100ms", elapsed)
+	}
+
+	// bucket kosong: Wait berhenti saat ctx selesai
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	slow := newRateLimiter(0.1, 1)
+	slow.Wait(context.Background())
+	if err := slow.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want deadline exceeded", err)
+	}
+
+	// rate <= 0 mematikan limiter
+	off := newRateLimiter(-1, 1)
+	for i := 0; i < 100; i++ {
+		if err := off.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       Options
+		wantHeader string
+		wantKey    string
+	}{
+		{"bearer token", Options{BearerToken: "v4-token"}, "Bearer v4-token", ""},
+		{"api key", Options{APIKey: "v3-key"}, "", "v3-key"},
+		{"bearer wins over api key", Options{BearerToken: "v4-token", APIKey: "v3-key"}, "Bearer v4-token", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var header, key string
+			tt.opts.RequestsPerSecond = -1
+			c := newTestClientWith(t, tt.opts, func(w http.ResponseWriter, r *http.Request) {
+				header, key = r.Header.Get("Authorization"), r.URL.Query().Get("api_key")
+				w.Write([]byte(`{"page": 1, "total_pages": 1, "results": []}`))
+			})
+			if _, err := c.MovieList(context.Background(), "popular", 1); err != nil {
+				t.Fatal(err)
+			}
+			if header != tt.wantHeader || key != tt.wantKey {
+				t.Fatalf("Authorization = %q, api_key = %q; want %q, %q", header, key, tt.wantHeader, tt.wantKey)
+			}
+		})
+	}
+}